@@ -0,0 +1,206 @@
+// Package audit menulis dan memverifikasi audit log tindakan administratif.
+// Setiap entri di-hash bersama hash entri sebelumnya (hash chain), sehingga
+// perubahan atau penghapusan baris di database dapat dideteksi oleh Verify.
+package audit
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"project-backend/config"
+	"project-backend/models"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Key context untuk data sebelum/sesudah yang diisi handler
+const (
+	beforeKey   = "audit_before"
+	afterKey    = "audit_after"
+	targetIDKey = "audit_target_id"
+)
+
+// Hash awal rantai (sebelum entri pertama)
+const genesisHash = "0000000000000000000000000000000000000000000000000000000000000000"
+
+// Entry adalah data yang dicatat untuk satu tindakan
+type Entry struct {
+	ActorID    uint
+	ActorName  string
+	ActorRole  string
+	Action     string
+	TargetType string
+	TargetID   string
+	Before     interface{}
+	After      interface{}
+	IP         string
+	UserAgent  string
+}
+
+// SetBefore menyimpan keadaan objek sebelum diubah, dibaca oleh middleware audit
+func SetBefore(c *gin.Context, v interface{}) {
+	c.Set(beforeKey, v)
+}
+
+// SetAfter menyimpan keadaan objek setelah diubah, dibaca oleh middleware audit
+func SetAfter(c *gin.Context, v interface{}) {
+	c.Set(afterKey, v)
+}
+
+// SetTargetID dipakai jika target tidak berasal dari parameter :id (mis. objek baru)
+func SetTargetID(c *gin.Context, id uint) {
+	c.Set(targetIDKey, strconv.FormatUint(uint64(id), 10))
+}
+
+// FromContext membentuk Entry dari request gin yang sedang berjalan
+func FromContext(c *gin.Context, action, targetType string) Entry {
+	e := Entry{
+		ActorID:    c.GetUint("userID"),
+		ActorRole:  c.GetString("role"),
+		Action:     action,
+		TargetType: targetType,
+		TargetID:   c.Param("id"),
+		IP:         c.ClientIP(),
+		UserAgent:  c.Request.UserAgent(),
+	}
+	if v, ok := c.Get("currentUser"); ok {
+		if u, ok := v.(models.User); ok {
+			e.ActorName = u.Name
+		}
+	}
+	if id := c.GetString(targetIDKey); id != "" {
+		e.TargetID = id
+	}
+	e.Before, _ = c.Get(beforeKey)
+	e.After, _ = c.Get(afterKey)
+	return e
+}
+
+func toJSON(v interface{}) string {
+	if v == nil {
+		return ""
+	}
+	if s, ok := v.(string); ok {
+		return s
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	return string(b)
+}
+
+// computeHash menghitung hash entri dari isi entri dan hash sebelumnya.
+// Waktu dibulatkan ke detik (UTC) agar tidak terpengaruh presisi kolom database.
+func computeHash(l models.AuditLog) string {
+	payload := fmt.Sprintf("%s|%d|%s|%s|%s|%s|%s|%s|%s|%s|%s|%s",
+		l.PrevHash,
+		l.ActorID, l.ActorName, l.ActorRole,
+		l.Action, l.TargetType, l.TargetID,
+		l.Before, l.After,
+		l.IP, l.UserAgent,
+		l.CreatedAt.UTC().Format(time.RFC3339),
+	)
+	sum := sha256.Sum256([]byte(payload))
+	return hex.EncodeToString(sum[:])
+}
+
+// Record menambahkan satu entri ke audit log
+func Record(e Entry) error {
+	if e.ActorName == "" && e.ActorID != 0 {
+		var actor models.User
+		if err := config.DB.Unscoped().Select("name").First(&actor, e.ActorID).Error; err == nil {
+			e.ActorName = actor.Name
+		}
+	}
+
+	return config.DB.Transaction(func(tx *gorm.DB) error {
+		// entri ditulis berurutan di database agar PrevHash selalu menunjuk entri terakhir
+		if err := lockChain(tx); err != nil {
+			return err
+		}
+		prev := genesisHash
+		var last models.AuditLog
+		if err := tx.Select("hash").Order("id DESC").Limit(1).Find(&last).Error; err != nil {
+			return err
+		}
+		if last.Hash != "" {
+			prev = last.Hash
+		}
+
+		entry := models.AuditLog{
+			ActorID:    e.ActorID,
+			ActorName:  e.ActorName,
+			ActorRole:  e.ActorRole,
+			Action:     e.Action,
+			TargetType: e.TargetType,
+			TargetID:   e.TargetID,
+			Before:     toJSON(e.Before),
+			After:      toJSON(e.After),
+			IP:         e.IP,
+			UserAgent:  e.UserAgent,
+			CreatedAt:  time.Now().Truncate(time.Second),
+			PrevHash:   prev,
+		}
+		if len(entry.UserAgent) > 255 {
+			entry.UserAgent = entry.UserAgent[:255]
+		}
+		entry.Hash = computeHash(entry)
+		return tx.Create(&entry).Error
+	})
+}
+
+// lockChain mengunci baris AuditChainLock (dibuat jika belum ada) sampai transaksi selesai
+func lockChain(tx *gorm.DB) error {
+	lock := models.AuditChainLock{ID: 1}
+	if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&lock).Error; err != nil {
+		return err
+	}
+	return tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&lock, lock.ID).Error
+}
+
+// VerifyResult adalah hasil pemeriksaan rantai hash
+type VerifyResult struct {
+	Valid    bool   `json:"valid"`
+	Checked  int    `json:"checked"`
+	BrokenID uint   `json:"broken_id,omitempty"` // entri pertama yang tidak cocok
+	Reason   string `json:"reason,omitempty"`
+}
+
+// Verify memeriksa seluruh rantai audit log dari entri pertama
+func Verify() (VerifyResult, error) {
+	var res VerifyResult
+	prev := genesisHash
+
+	var batch []models.AuditLog
+	err := config.DB.FindInBatches(&batch, 500, func(tx *gorm.DB, _ int) error {
+		for _, l := range batch {
+			if l.PrevHash != prev {
+				res.BrokenID = l.ID
+				res.Reason = "prev_hash tidak cocok dengan entri sebelumnya (entri dihapus atau disisipkan)"
+				return errStop
+			}
+			if computeHash(l) != l.Hash {
+				res.BrokenID = l.ID
+				res.Reason = "isi entri tidak cocok dengan hash (entri diubah)"
+				return errStop
+			}
+			prev = l.Hash
+			res.Checked++
+		}
+		return nil
+	}).Error
+	if err != nil && err != errStop {
+		return res, err
+	}
+
+	res.Valid = res.BrokenID == 0
+	return res, nil
+}
+
+var errStop = fmt.Errorf("audit: rantai hash terputus")
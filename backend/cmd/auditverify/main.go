@@ -0,0 +1,29 @@
+// Perintah auditverify memeriksa keutuhan rantai hash audit log.
+//
+//	go run ./cmd/auditverify
+//
+// Keluar dengan kode 1 jika ditemukan entri yang diubah, dihapus atau disisipkan.
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"project-backend/audit"
+	"project-backend/config"
+)
+
+func main() {
+	config.Connect()
+
+	res, err := audit.Verify()
+	if err != nil {
+		log.Fatal("Gagal memverifikasi audit log:", err)
+	}
+
+	if !res.Valid {
+		fmt.Printf("AUDIT LOG RUSAK pada entri #%d: %s (%d entri valid sebelumnya)\n", res.BrokenID, res.Reason, res.Checked)
+		os.Exit(1)
+	}
+	fmt.Printf("Audit log utuh: %d entri terverifikasi\n", res.Checked)
+}
@@ -0,0 +1,20 @@
+package config
+
+// Nama aplikasi yang dicetak di dokumen dan email
+const AppName = "Lapor Pak!"
+
+// Alamat frontend publik, dipakai untuk tautan pelacakan di PDF, QR code dan email
+const FrontendURL = "http://localhost:3000"
+
+// TrackingURL mengembalikan tautan halaman pelacakan untuk tracking ID
+func TrackingURL(trackingID string) string {
+	return FrontendURL + "/?tracking_id=" + trackingID
+}
+
+// Alamat publik API, dipakai untuk tautan gambar di email
+const BackendURL = "http://localhost:8080"
+
+// TrackingQRURL mengembalikan tautan gambar QR code pelacakan (PNG)
+func TrackingQRURL(trackingID string) string {
+	return BackendURL + "/reports/qr/" + trackingID + "?format=png"
+}
@@ -3,6 +3,7 @@ package config
 import (
 	"fmt"
 	"log"
+	"project-backend/geo"
 	"project-backend/models"
 
 	"gorm.io/driver/mysql"
@@ -22,6 +23,43 @@ func Connect() {
 	DB = db
 	fmt.Println("Database connected")
 
+	migrateWebhookDeliveries()
+
 	// Auto migrate tables
-	DB.AutoMigrate(&models.User{}, &models.Report{}, &models.Riwayat{}, &models.Comment{}, &models.FollowUp{}, &models.Category{}, &models.BuktiFoto{})
+	DB.AutoMigrate(&models.User{}, &models.Report{}, &models.Riwayat{}, &models.Comment{}, &models.FollowUp{}, &models.Category{}, &models.BuktiFoto{}, &models.Endorsement{}, &models.ReportRevision{}, &models.ReportRating{}, &models.AuditLog{}, &models.AuditChainLock{}, &models.Region{}, &models.ReportSubscription{}, &models.Notification{}, &models.NotificationPreference{}, &models.EmailMessage{}, &models.OutboundMessage{}, &models.MessageRateLock{}, &models.Webhook{}, &models.WebhookDelivery{}, &models.OutboxEvent{}, &models.OutboxDelivery{}, &models.Job{})
+
+	backfillGeohash()
+}
+
+// migrateWebhookDeliveries menomori pengiriman webhook lama sebelum AutoMigrate membuat
+// indeks unik (webhook_id, event_id, redelivery): pengiriman ulang dan duplikat dari
+// Enqueue bersamaan diberi nomor sesuai ID-nya sehingga tidak bentrok dengan pengiriman asli
+func migrateWebhookDeliveries() {
+	m := DB.Migrator()
+	if !m.HasTable(&models.WebhookDelivery{}) || m.HasColumn(&models.WebhookDelivery{}, "Redelivery") {
+		return
+	}
+	if err := m.AddColumn(&models.WebhookDelivery{}, "Redelivery"); err != nil {
+		log.Println("Webhook delivery migration failed:", err)
+		return
+	}
+	DB.Exec("UPDATE webhook_deliveries SET redelivery = id WHERE redelivery_of IS NOT NULL")
+	DB.Exec("UPDATE webhook_deliveries d JOIN webhook_deliveries first " +
+		"ON first.webhook_id = d.webhook_id AND first.event_id = d.event_id AND first.redelivery = 0 AND first.id < d.id " +
+		"SET d.redelivery = d.id WHERE d.redelivery = 0")
+}
+
+// backfillGeohash mengisi geohash laporan lama yang dibuat sebelum kolom ini ada
+func backfillGeohash() {
+	var reports []models.Report
+	DB.Select("id", "latitude", "longitude").Where("geohash = '' OR geohash IS NULL").
+		FindInBatches(&reports, 500, func(tx *gorm.DB, batch int) error {
+			for _, r := range reports {
+				hash := geo.Encode(r.Latitude, r.Longitude, geo.StoredPrecision)
+				if err := DB.Model(&models.Report{}).Where("id = ?", r.ID).UpdateColumn("geohash", hash).Error; err != nil {
+					return err
+				}
+			}
+			return nil
+		})
 }
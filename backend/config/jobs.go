@@ -0,0 +1,4 @@
+package config
+
+// Jumlah job latar yang boleh berjalan bersamaan di satu proses server
+const JobWorkers = 4
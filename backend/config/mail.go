@@ -0,0 +1,26 @@
+package config
+
+import "os"
+
+// Pengaturan SMTP untuk email keluar, diisi lewat env SMTP_HOST, SMTP_USERNAME dan
+// SMTP_PASSWORD. Jika SMTPHost kosong, email tidak dikirim (lihat mailer.DisabledMailer),
+// kecuali MAIL_SINK_DIR diisi untuk pengembangan.
+var (
+	SMTPHost     = os.Getenv("SMTP_HOST")
+	SMTPUsername = os.Getenv("SMTP_USERNAME")
+	SMTPPassword = os.Getenv("SMTP_PASSWORD")
+)
+
+const (
+	SMTPPort = 587
+	MailFrom = "Lapor Pak! <no-reply@laporpak.local>"
+)
+
+// Folder penampung email saat pengembangan tanpa SMTP (lihat mailer.FileMailer).
+// Berkasnya berisi alamat dan isi email pelapor, jadi hanya aktif jika diisi lewat env.
+var MailSinkDir = os.Getenv("MAIL_SINK_DIR")
+
+// Kunci HMAC untuk tautan berhenti berlangganan email notifikasi. Wajib diisi jika
+// email dikirim lewat SMTP atau MAIL_SINK_DIR; tanpanya tautan berhenti berlangganan
+// dinonaktifkan dan email notifikasi laporan tidak diantrekan.
+var UnsubscribeSecret = os.Getenv("UNSUBSCRIBE_SECRET")
@@ -0,0 +1,24 @@
+package config
+
+import (
+	"os"
+	"time"
+)
+
+// Gateway WhatsApp/SMS untuk notifikasi ke nomor HP pelapor, diatur lewat env.
+// Jika MESSAGING_URL kosong, pesan tidak dikirim (lihat messaging.DisabledGateway).
+var (
+	MessagingURL   = os.Getenv("MESSAGING_URL")
+	MessagingToken = os.Getenv("MESSAGING_TOKEN")
+	// Token yang harus dikirim gateway di header X-Webhook-Token saat meneruskan
+	// pesan masuk (balasan STOP/MULAI dari pelapor); kosong berarti webhook ditolak
+	MessagingWebhookToken = os.Getenv("MESSAGING_WEBHOOK_TOKEN")
+)
+
+const MessagingSender = "LaporPak"
+
+// Batas pesan WhatsApp/SMS per nomor penerima dalam satu jendela waktu
+const (
+	MessagingRateLimit  = 5
+	MessagingRateWindow = time.Hour
+)
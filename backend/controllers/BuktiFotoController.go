@@ -2,6 +2,7 @@ package controllers
 
 import (
 	"net/http"
+	"project-backend/audit"
 	"project-backend/config"
 	"project-backend/models"
 
@@ -31,6 +32,7 @@ func SoftDeleteBuktiFoto(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"message": "Gagal menghapus bukti foto"})
 		return
 	}
+	audit.SetBefore(c, gin.H{"report_id": buktiFoto.ReportID, "photo_url": buktiFoto.PhotoURL})
 
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Bukti foto berhasil dihapus",
@@ -80,6 +82,8 @@ func HardDeleteBuktiFoto(c *gin.Context) {
 		return
 	}
 
+	audit.SetBefore(c, gin.H{"report_id": buktiFoto.ReportID, "photo_url": buktiFoto.PhotoURL, "deleted_at": buktiFoto.DeletedAt})
+
 	// Hard delete bukti foto
 	if err := config.DB.Unscoped().Delete(&buktiFoto).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
@@ -7,7 +7,6 @@ import (
 	"strings"
 
 	"github.com/gin-gonic/gin"
-	"gorm.io/gorm"
 )
 
 // GetTotalReports -> untuk menghitung total semua aduan (laporan)
@@ -36,6 +35,7 @@ func GetAdminStats(c *gin.Context) {
 		reportProcessing int64
 		reportDone       int64
 		reportRejected   int64
+		reportCancelled  int64
 		commentCount     int64
 		followupCount    int64
 	)
@@ -51,50 +51,39 @@ func GetAdminStats(c *gin.Context) {
 		return
 	}
 
-	baseDB := config.DB.Model(&models.Report{})
-
-	if role == "admin" {
-		var categories []models.Category
-		if err := config.DB.Where("user_id = ?", admin.ID).Find(&categories).Error; err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"message": "Gagal mengambil kategori admin"})
-			return
-		}
-		var categoryIDs []uint
-		for _, cat := range categories {
-			categoryIDs = append(categoryIDs, cat.ID)
-		}
-
-		// Hanya filter kalau admin punya kategori
-		if len(categoryIDs) > 0 {
-			baseDB = baseDB.Where("category_id IN ?", categoryIDs)
-		}
-	}
+	// admin hanya menghitung laporan dalam kategori/wilayah tugasnya
+	baseDB := config.DB.Model(&models.Report{}).Scopes(adminScope(role, admin.ID))
 
 	if err := baseDB.Count(&reportCount).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"message": "Gagal menghitung total laporan"})
 		return
 	}
 
-	if err := config.DB.Model(&models.Report{}).Scopes(filterCategory(role, admin.ID)).Where("status = ?", "Diajukan").Count(&reportPending).Error; err != nil {
+	if err := config.DB.Model(&models.Report{}).Scopes(adminScope(role, admin.ID)).Where("status = ?", "Diajukan").Count(&reportPending).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"message": "Gagal menghitung laporan Diajukan"})
 		return
 	}
 
-	if err := config.DB.Model(&models.Report{}).Scopes(filterCategory(role, admin.ID)).Where("status = ?", "Diproses").Count(&reportProcessing).Error; err != nil {
+	if err := config.DB.Model(&models.Report{}).Scopes(adminScope(role, admin.ID)).Where("status = ?", "Diproses").Count(&reportProcessing).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"message": "Gagal menghitung laporan Diproses"})
 		return
 	}
 
-	if err := config.DB.Model(&models.Report{}).Scopes(filterCategory(role, admin.ID)).Where("status = ?", "Selesai").Count(&reportDone).Error; err != nil {
+	if err := config.DB.Model(&models.Report{}).Scopes(adminScope(role, admin.ID)).Where("status = ?", "Selesai").Count(&reportDone).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"message": "Gagal menghitung laporan Selesai"})
 		return
 	}
 
-	if err := config.DB.Model(&models.Report{}).Scopes(filterCategory(role, admin.ID)).Where("status = ?", "Ditolak").Count(&reportRejected).Error; err != nil {
+	if err := config.DB.Model(&models.Report{}).Scopes(adminScope(role, admin.ID)).Where("status = ?", "Ditolak").Count(&reportRejected).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"message": "Gagal menghitung laporan Ditolak"})
 		return
 	}
 
+	if err := config.DB.Model(&models.Report{}).Scopes(adminScope(role, admin.ID)).Where("status = ?", "Dibatalkan").Count(&reportCancelled).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": "Gagal menghitung laporan Dibatalkan"})
+		return
+	}
+
 	if err := config.DB.Model(&models.User{}).Count(&userCount).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"message": "Gagal menghitung jumlah user"})
 		return
@@ -135,33 +124,13 @@ func GetAdminStats(c *gin.Context) {
 		"processing_reports":   reportProcessing,
 		"done_reports":         reportDone,
 		"rejected_reports":     reportRejected,
+		"cancelled_reports":    reportCancelled,
 		"comment_count":        commentCount,
 		"followup_count":       followupCount,
+		"satisfaction":         satisfactionStats(role, admin.ID),
 	})
 }
 
-// Scope helper to abstract category filtering
-func filterCategory(role string, userID uint) func(db *gorm.DB) *gorm.DB {
-	return func(db *gorm.DB) *gorm.DB {
-		if role == "admin" || role == "kategori_admin" {
-			var categories []models.Category
-			err := config.DB.Where("user_id = ?", userID).Find(&categories).Error
-			if err != nil {
-				return db // jangan filter kalau error
-			}
-			var categoryIDs []uint
-			for _, cat := range categories {
-				categoryIDs = append(categoryIDs, cat.ID)
-			}
-			if len(categoryIDs) > 0 {
-				return db.Where("category_id IN ?", categoryIDs)
-			}
-			return db // kalau tidak punya kategori, lihat semua
-		}
-		return db
-	}
-}
-
 // Tambahkan endpoint baru
 func GetTrends(c *gin.Context) {
 	type PeriodCount struct {
@@ -0,0 +1,213 @@
+package controllers
+
+import (
+	"math"
+	"net/http"
+	"project-backend/config"
+	"project-backend/geo"
+	"project-backend/models"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultHeatmapPrecision = 6 // sel ~1.2km x 0.6km
+	defaultBaselinePeriods  = 6
+	maxBaselinePeriods      = 24
+	defaultHotspotMinCount  = 3
+	hotspotAlpha            = 0.05
+)
+
+type heatmapCell struct {
+	Geohash      string     `json:"geohash"`
+	Lat          float64    `json:"lat"`
+	Lon          float64    `json:"lon"`
+	BBox         [4]float64 `json:"bbox"` // [minLon, minLat, maxLon, maxLat]
+	Count        int64      `json:"count"`
+	BaselineMean float64    `json:"baseline_mean"`
+	ZScore       float64    `json:"z_score"`
+	PValue       float64    `json:"p_value"`
+	Hotspot      bool       `json:"hotspot"`
+}
+
+type cellCount struct {
+	Cell  string
+	Total int64
+	Lat   float64
+	Lon   float64
+}
+
+// poissonUpperTail menghitung P(X >= k) untuk X ~ Poisson(lambda)
+func poissonUpperTail(k int64, lambda float64) float64 {
+	if k <= 0 {
+		return 1
+	}
+	// P(X <= k-1) dijumlahkan per suku dalam bentuk log agar e^-lambda tidak underflow
+	var cdf float64
+	logLambda := math.Log(lambda)
+	for i := int64(0); i < k; i++ {
+		lg, _ := math.Lgamma(float64(i + 1))
+		cdf += math.Exp(float64(i)*logLambda - lambda - lg)
+	}
+	if cdf > 1 {
+		cdf = 1
+	}
+	return 1 - cdf
+}
+
+// GET /admin/analytics/heatmap?from=2025-08-01&to=2025-08-31&precision=6&baseline_periods=6
+// Jumlah laporan per sel geohash dalam rentang waktu, dibandingkan dengan rata-rata
+// beberapa periode sebelumnya yang panjangnya sama. Sel dengan lonjakan yang signifikan
+// (uji Poisson satu sisi, dikoreksi Benjamini-Hochberg) ditandai sebagai hotspot.
+// Filter daftar laporan (category_id, status, kode_wilayah, dst.) juga berlaku.
+func GetHeatmap(c *gin.Context) {
+	now := time.Now()
+	to := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local).AddDate(0, 0, 1)
+	from := to.AddDate(0, 0, -30)
+	if v := c.Query("from"); v != "" {
+		t, err := time.ParseInLocation("2006-01-02", v, time.Local)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"message": "Format from harus YYYY-MM-DD"})
+			return
+		}
+		from = t
+	}
+	if v := c.Query("to"); v != "" {
+		t, err := time.ParseInLocation("2006-01-02", v, time.Local)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"message": "Format to harus YYYY-MM-DD"})
+			return
+		}
+		to = t.AddDate(0, 0, 1)
+	}
+	if !from.Before(to) {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "from harus sebelum to"})
+		return
+	}
+
+	precision := defaultHeatmapPrecision
+	if v := c.Query("precision"); v != "" {
+		p, err := strconv.Atoi(v)
+		if err != nil || p < 3 || p > 8 {
+			c.JSON(http.StatusBadRequest, gin.H{"message": "precision harus bernilai 3 sampai 8"})
+			return
+		}
+		precision = p
+	}
+	periods := defaultBaselinePeriods
+	if v, err := strconv.Atoi(c.Query("baseline_periods")); err == nil && v > 0 {
+		periods = v
+	}
+	if periods > maxBaselinePeriods {
+		periods = maxBaselinePeriods
+	}
+	minCount := int64(defaultHotspotMinCount)
+	if v, err := strconv.Atoi(c.Query("min_count")); err == nil && v > 0 {
+		minCount = int64(v)
+	}
+
+	window := to.Sub(from)
+	baselineFrom := from.Add(-window * time.Duration(periods))
+	role := c.GetString("role")
+	adminID := c.GetUint("userID")
+
+	cellExpr := "SUBSTR(reports.geohash, 1, " + strconv.Itoa(precision) + ")"
+	countCells := func(start, end time.Time) ([]cellCount, error) {
+		query := config.DB.Model(&models.Report{}).
+			Scopes(adminScope(role, adminID), reportAttributeFilters(c)).
+			Where("reports.created_at >= ? AND reports.created_at < ?", start, end).
+			Where("reports.geohash <> ''")
+		if c.Query("status") == "" {
+			// laporan yang dibatalkan pelapor tidak dihitung kecuali diminta
+			query = query.Where("reports.status <> ?", "Dibatalkan")
+		}
+		var rows []cellCount
+		err := query.
+			Select(cellExpr + " AS cell, COUNT(*) AS total, AVG(reports.latitude) AS lat, AVG(reports.longitude) AS lon").
+			Group(cellExpr).
+			Scan(&rows).Error
+		return rows, err
+	}
+
+	current, err := countCells(from, to)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": "Gagal menghitung heatmap"})
+		return
+	}
+	baseline, err := countCells(baselineFrom, from)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": "Gagal menghitung baseline heatmap"})
+		return
+	}
+	baselineByCell := make(map[string]int64, len(baseline))
+	for _, b := range baseline {
+		baselineByCell[b.Cell] = b.Total
+	}
+
+	cells := make([]heatmapCell, 0, len(current))
+	var maxCount int64
+	for _, cur := range current {
+		minLat, minLon, maxLat, maxLon := geo.Decode(cur.Cell)
+		// +0.5 agar sel tanpa riwayat tidak langsung dianggap signifikan oleh satu-dua laporan
+		expected := (float64(baselineByCell[cur.Cell]) + 0.5) / float64(periods)
+		cell := heatmapCell{
+			Geohash:      cur.Cell,
+			Lat:          cur.Lat,
+			Lon:          cur.Lon,
+			BBox:         [4]float64{minLon, minLat, maxLon, maxLat},
+			Count:        cur.Total,
+			BaselineMean: math.Round(float64(baselineByCell[cur.Cell])/float64(periods)*100) / 100,
+			ZScore:       math.Round((float64(cur.Total)-expected)/math.Sqrt(expected)*100) / 100,
+			PValue:       poissonUpperTail(cur.Total, expected),
+		}
+		cells = append(cells, cell)
+		if cur.Total > maxCount {
+			maxCount = cur.Total
+		}
+	}
+
+	// Benjamini-Hochberg: urutkan p-value, sel ke-i signifikan jika p <= i/m * alpha
+	order := make([]int, len(cells))
+	for i := range order {
+		order[i] = i
+	}
+	sort.Slice(order, func(a, b int) bool { return cells[order[a]].PValue < cells[order[b]].PValue })
+	cutoff := -1
+	for rank, i := range order {
+		if cells[i].PValue <= float64(rank+1)/float64(len(cells))*hotspotAlpha {
+			cutoff = rank
+		}
+	}
+	var hotspots []heatmapCell
+	for rank := 0; rank <= cutoff; rank++ {
+		cell := &cells[order[rank]]
+		if cell.Count >= minCount {
+			cell.Hotspot = true
+			hotspots = append(hotspots, *cell)
+		}
+	}
+	sort.Slice(hotspots, func(a, b int) bool { return hotspots[a].ZScore > hotspots[b].ZScore })
+
+	// titik siap pakai untuk layer heatmap: [lat, lon, intensitas 0..1]
+	points := make([][3]float64, 0, len(cells))
+	for _, cell := range cells {
+		points = append(points, [3]float64{cell.Lat, cell.Lon, float64(cell.Count) / float64(maxCount)})
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": gin.H{
+			"from":             from.Format("2006-01-02"),
+			"to":               to.AddDate(0, 0, -1).Format("2006-01-02"),
+			"baseline_from":    baselineFrom.Format("2006-01-02"),
+			"baseline_periods": periods,
+			"precision":        precision,
+			"max_count":        maxCount,
+			"cells":            cells,
+			"hotspots":         hotspots,
+			"points":           points,
+		},
+	})
+}
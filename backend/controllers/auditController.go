@@ -0,0 +1,84 @@
+package controllers
+
+import (
+	"net/http"
+	"project-backend/audit"
+	"project-backend/config"
+	"project-backend/models"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GET /admin/audit-logs?actor_id=&action=&target_type=&target_id=&from=2025-08-01&to=2025-08-31&page=&limit=
+func GetAuditLogs(c *gin.Context) {
+	db := config.DB.Model(&models.AuditLog{})
+
+	if v := c.Query("actor_id"); v != "" {
+		db = db.Where("actor_id = ?", v)
+	}
+	if v := c.Query("action"); v != "" {
+		db = db.Where("action = ?", v)
+	}
+	if v := c.Query("target_type"); v != "" {
+		db = db.Where("target_type = ?", v)
+	}
+	if v := c.Query("target_id"); v != "" {
+		db = db.Where("target_id = ?", v)
+	}
+	if v := c.Query("from"); v != "" {
+		from, err := time.ParseInLocation("2006-01-02", v, time.Local)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"message": "Format tanggal salah. Gunakan format YYYY-MM-DD"})
+			return
+		}
+		db = db.Where("created_at >= ?", from)
+	}
+	if v := c.Query("to"); v != "" {
+		to, err := time.ParseInLocation("2006-01-02", v, time.Local)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"message": "Format tanggal salah. Gunakan format YYYY-MM-DD"})
+			return
+		}
+		db = db.Where("created_at < ?", to.AddDate(0, 0, 1))
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 200 {
+		limit = 50
+	}
+
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": "Gagal menghitung audit log"})
+		return
+	}
+
+	var logs []models.AuditLog
+	if err := db.Order("id DESC").Offset((page - 1) * limit).Limit(limit).Find(&logs).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": "Gagal mengambil audit log"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  logs,
+		"total": total,
+		"page":  page,
+		"limit": limit,
+	})
+}
+
+// GET /admin/audit-logs/verify -> periksa keutuhan rantai hash audit log
+func VerifyAuditLogs(c *gin.Context) {
+	res, err := audit.Verify()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": "Gagal memverifikasi audit log", "error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": res})
+}
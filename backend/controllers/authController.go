@@ -3,7 +3,10 @@ package controllers
 import (
 	"fmt"
 	"net/http"
+	"project-backend/audit"
 	"project-backend/config"
+	"project-backend/mailer"
+	"project-backend/messaging"
 	"project-backend/models"
 	"strings"
 	"time"
@@ -28,7 +31,7 @@ func Login(c *gin.Context) {
 
 	var user models.User
 	// Preload categories agar tersedia di user.Categories
-	if err := config.DB.Preload("Categories").Where("email = ?", loginData.Email).First(&user).Error; err != nil {
+	if err := config.DB.Preload("Categories").Preload("Regions").Where("email = ?", loginData.Email).First(&user).Error; err != nil {
 		c.JSON(http.StatusUnauthorized, gin.H{"message": "Invalid credentials"})
 		return
 	}
@@ -56,6 +59,11 @@ func Login(c *gin.Context) {
 	}
 	hasCategories := len(categoryIDs) > 0
 
+	regionCodes := []string{}
+	for _, region := range user.Regions {
+		regionCodes = append(regionCodes, region.Code)
+	}
+
 	// Buat claims untuk token, termasuk info role dan kategori
 	claims := jwt.MapClaims{
 		"user_id":        user.ID,
@@ -65,6 +73,7 @@ func Login(c *gin.Context) {
 		"category_ids":   categoryIDs,
 		"categories":     categoryNames,
 		"has_categories": hasCategories,
+		"region_codes":   regionCodes,
 		"exp":            time.Now().Add(time.Hour * 24).Unix(),
 	}
 
@@ -87,6 +96,7 @@ func Login(c *gin.Context) {
 			"is_active":    user.IsActive,
 			"category_ids": categoryIDs,
 			"categories":   categoryNames,
+			"region_codes": regionCodes,
 		},
 	})
 }
@@ -137,14 +147,23 @@ func Register(c *gin.Context) {
 func UpdateProfile(c *gin.Context) {
 	userID := c.GetUint("userID")
 	var input struct {
-		Name  string `json:"name"`
-		Email string `json:"email"`
+		Name     string `json:"name"`
+		Email    string `json:"email"`
+		Language string `json:"language"`
+		// nil berarti tidak diubah; Phone "" menghapus nomor HP
+		Phone            *string `json:"phone"`
+		MessagingOptIn   *bool   `json:"messaging_opt_in"`
+		MessagingChannel string  `json:"messaging_channel"`
 	}
 
 	if err := c.ShouldBindJSON(&input); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid input"})
 		return
 	}
+	if input.Language != "" && !mailer.IsSupportedLanguage(input.Language) {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Bahasa tidak didukung"})
+		return
+	}
 
 	var user models.User
 	if err := config.DB.First(&user, userID).Error; err != nil {
@@ -158,6 +177,44 @@ func UpdateProfile(c *gin.Context) {
 	if input.Email != "" {
 		user.Email = input.Email
 	}
+	if input.Language != "" {
+		user.Language = input.Language
+	}
+	if input.MessagingChannel != "" {
+		if !messaging.IsValidChannel(input.MessagingChannel) {
+			c.JSON(http.StatusBadRequest, gin.H{"message": "Kanal pesan harus whatsapp atau sms"})
+			return
+		}
+		user.MessagingChannel = input.MessagingChannel
+	}
+	if input.Phone != nil {
+		phone := ""
+		if strings.TrimSpace(*input.Phone) != "" {
+			var err error
+			if phone, err = messaging.NormalizePhone(*input.Phone); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"message": "Nomor HP tidak valid"})
+				return
+			}
+		}
+		if phone != user.Phone {
+			// nomor baru harus disetujui ulang
+			user.Phone = phone
+			user.MessagingOptIn = false
+			user.MessagingOptInAt = nil
+		}
+	}
+	if input.MessagingOptIn != nil && *input.MessagingOptIn != user.MessagingOptIn {
+		if *input.MessagingOptIn && user.Phone == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"message": "Isi nomor HP sebelum mengaktifkan notifikasi WhatsApp/SMS"})
+			return
+		}
+		user.MessagingOptIn = *input.MessagingOptIn
+		user.MessagingOptInAt = nil
+		if user.MessagingOptIn {
+			now := time.Now()
+			user.MessagingOptInAt = &now
+		}
+	}
 
 	if err := config.DB.Save(&user).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to update profile"})
@@ -167,9 +224,13 @@ func UpdateProfile(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Profile updated successfully",
 		"user": gin.H{
-			"id":    user.ID,
-			"name":  user.Name,
-			"email": user.Email,
+			"id":                user.ID,
+			"name":              user.Name,
+			"email":             user.Email,
+			"language":          user.Language,
+			"phone":             user.Phone,
+			"messaging_opt_in":  user.MessagingOptIn,
+			"messaging_channel": user.MessagingChannel,
 		},
 	})
 }
@@ -256,12 +317,12 @@ func CreateAdmin(c *gin.Context) {
 	userID := c.GetUint("userID")
 
 	var currentUser models.User
-	if err := config.DB.Preload("Categories").First(&currentUser, userID).Error; err != nil {
+	if err := config.DB.Preload("Categories").Preload("Regions").First(&currentUser, userID).Error; err != nil {
 		c.JSON(http.StatusUnauthorized, gin.H{"message": "User tidak ditemukan"})
 		return
 	}
 
-	isSuperadmin := (currentUser.Role == "admin" && len(currentUser.Categories) == 0) || currentUser.Role == "superadmin"
+	isSuperadmin := (currentUser.Role == "admin" && len(currentUser.Categories) == 0 && len(currentUser.Regions) == 0) || currentUser.Role == "superadmin"
 
 	if !isSuperadmin {
 		c.JSON(http.StatusForbidden, gin.H{"message": "Hanya superadmin yang dapat membuat admin baru"})
@@ -302,6 +363,8 @@ func CreateAdmin(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"message": "Gagal membuat admin"})
 		return
 	}
+	audit.SetTargetID(c, admin.ID)
+	audit.SetAfter(c, gin.H{"name": admin.Name, "email": admin.Email, "role": admin.Role})
 
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Admin berhasil dibuat dengan password default: 'Password'",
@@ -319,7 +382,7 @@ func DeleteUser(c *gin.Context) {
 	// Cek role dengan cara yang sama seperti fungsi lain
 	userID := c.GetUint("userID")
 	var currentUser models.User
-	if err := config.DB.Preload("Categories").First(&currentUser, userID).Error; err != nil {
+	if err := config.DB.Preload("Categories").Preload("Regions").First(&currentUser, userID).Error; err != nil {
 		c.JSON(http.StatusUnauthorized, models.Response{
 			Status:  401,
 			Message: "User tidak ditemukan",
@@ -328,7 +391,7 @@ func DeleteUser(c *gin.Context) {
 	}
 
 	// Cek apakah superadmin
-	isSuperadmin := (currentUser.Role == "admin" && len(currentUser.Categories) == 0) || currentUser.Role == "superadmin"
+	isSuperadmin := (currentUser.Role == "admin" && len(currentUser.Categories) == 0 && len(currentUser.Regions) == 0) || currentUser.Role == "superadmin"
 	if !isSuperadmin {
 		c.JSON(http.StatusForbidden, models.Response{
 			Status:  403,
@@ -356,7 +419,7 @@ func DeleteUser(c *gin.Context) {
 func GetDeletedUsers(c *gin.Context) {
 	userID := c.GetUint("userID")
 	var currentUser models.User
-	if err := config.DB.Preload("Categories").First(&currentUser, userID).Error; err != nil {
+	if err := config.DB.Preload("Categories").Preload("Regions").First(&currentUser, userID).Error; err != nil {
 		c.JSON(http.StatusUnauthorized, models.Response{
 			Status:  401,
 			Message: "User tidak ditemukan",
@@ -364,7 +427,7 @@ func GetDeletedUsers(c *gin.Context) {
 		return
 	}
 
-	isSuperadmin := (currentUser.Role == "admin" && len(currentUser.Categories) == 0) || currentUser.Role == "superadmin"
+	isSuperadmin := (currentUser.Role == "admin" && len(currentUser.Categories) == 0 && len(currentUser.Regions) == 0) || currentUser.Role == "superadmin"
 	if !isSuperadmin {
 		c.JSON(http.StatusForbidden, models.Response{
 			Status:  403,
@@ -393,7 +456,7 @@ func GetDeletedUsers(c *gin.Context) {
 func ToggleActiveUser(c *gin.Context) {
 	userID := c.GetUint("userID")
 	var currentUser models.User
-	if err := config.DB.Preload("Categories").First(&currentUser, userID).Error; err != nil {
+	if err := config.DB.Preload("Categories").Preload("Regions").First(&currentUser, userID).Error; err != nil {
 		c.JSON(http.StatusUnauthorized, models.Response{
 			Status:  401,
 			Message: "User tidak ditemukan",
@@ -401,7 +464,7 @@ func ToggleActiveUser(c *gin.Context) {
 		return
 	}
 
-	isSuperadmin := (currentUser.Role == "admin" && len(currentUser.Categories) == 0) || currentUser.Role == "superadmin"
+	isSuperadmin := (currentUser.Role == "admin" && len(currentUser.Categories) == 0 && len(currentUser.Regions) == 0) || currentUser.Role == "superadmin"
 	if !isSuperadmin {
 		c.JSON(http.StatusForbidden, models.Response{
 			Status:  403,
@@ -420,6 +483,7 @@ func ToggleActiveUser(c *gin.Context) {
 		return
 	}
 
+	audit.SetBefore(c, gin.H{"is_active": user.IsActive})
 	user.IsActive = !user.IsActive
 	if err := config.DB.Save(&user).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, models.Response{
@@ -429,6 +493,8 @@ func ToggleActiveUser(c *gin.Context) {
 		return
 	}
 
+	audit.SetAfter(c, gin.H{"is_active": user.IsActive})
+
 	status := "activated"
 	if !user.IsActive {
 		status = "deactivated"
@@ -444,12 +510,12 @@ func ToggleActiveUser(c *gin.Context) {
 func RestoreUser(c *gin.Context) {
 	userID := c.GetUint("userID")
 	var currentUser models.User
-	if err := config.DB.Preload("Categories").First(&currentUser, userID).Error; err != nil {
+	if err := config.DB.Preload("Categories").Preload("Regions").First(&currentUser, userID).Error; err != nil {
 		c.JSON(http.StatusUnauthorized, gin.H{"message": "User tidak ditemukan"})
 		return
 	}
 
-	isSuperadmin := (currentUser.Role == "admin" && len(currentUser.Categories) == 0) || currentUser.Role == "superadmin"
+	isSuperadmin := (currentUser.Role == "admin" && len(currentUser.Categories) == 0 && len(currentUser.Regions) == 0) || currentUser.Role == "superadmin"
 	if !isSuperadmin {
 		c.JSON(http.StatusForbidden, gin.H{"message": "Hanya superadmin yang dapat memulihkan user"})
 		return
@@ -470,18 +536,33 @@ func RestoreUser(c *gin.Context) {
 func HardDeleteUser(c *gin.Context) {
 	userID := c.GetUint("userID")
 	var currentUser models.User
-	if err := config.DB.Preload("Categories").First(&currentUser, userID).Error; err != nil {
+	if err := config.DB.Preload("Categories").Preload("Regions").First(&currentUser, userID).Error; err != nil {
 		c.JSON(http.StatusUnauthorized, gin.H{"message": "User tidak ditemukan"})
 		return
 	}
 
-	isSuperadmin := (currentUser.Role == "admin" && len(currentUser.Categories) == 0) || currentUser.Role == "superadmin"
+	isSuperadmin := (currentUser.Role == "admin" && len(currentUser.Categories) == 0 && len(currentUser.Regions) == 0) || currentUser.Role == "superadmin"
 	if !isSuperadmin {
 		c.JSON(http.StatusForbidden, gin.H{"message": "Hanya superadmin yang dapat menghapus user permanen"})
 		return
 	}
 
 	id := c.Param("id")
+	var target models.User
+	if err := config.DB.Unscoped().First(&target, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"message": "User not found"})
+		return
+	}
+	audit.SetBefore(c, gin.H{"name": target.Name, "email": target.Email, "role": target.Role, "is_active": target.IsActive})
+
+	// lepaskan penugasan wilayah agar baris admin_regions tidak menghalangi penghapusan
+	config.DB.Model(&target).Association("Regions").Clear()
+	// data milik user yang tidak berarti tanpa user tersebut
+	config.DB.Where("user_id = ?", target.ID).Delete(&models.Notification{})
+	config.DB.Where("user_id = ?", target.ID).Delete(&models.NotificationPreference{})
+	config.DB.Where("user_id = ?", target.ID).Delete(&models.ReportSubscription{})
+	config.DB.Where("user_id = ?", target.ID).Delete(&models.OutboundMessage{})
+
 	if err := config.DB.Unscoped().Delete(&models.User{}, id).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to permanently delete user"})
 		return
@@ -2,6 +2,7 @@ package controllers
 
 import (
 	"net/http"
+	"project-backend/audit"
 	"project-backend/config"
 	"project-backend/models"
 	"strconv"
@@ -73,8 +74,9 @@ func CreateCategory(c *gin.Context) {
 	}
 
 	var input struct {
-		Name   string `json:"name" binding:"required"`
-		UserID uint   `json:"user_id" binding:"required"`
+		Name     string `json:"name" binding:"required"`
+		UserID   uint   `json:"user_id" binding:"required"`
+		Severity int    `json:"severity"` // opsional, 1-5
 	}
 
 	if err := c.ShouldBindJSON(&input); err != nil {
@@ -82,15 +84,26 @@ func CreateCategory(c *gin.Context) {
 		return
 	}
 
+	if input.Severity < 0 || input.Severity > 5 {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Severity harus antara 1 sampai 5"})
+		return
+	}
+	if input.Severity == 0 {
+		input.Severity = 3
+	}
+
 	cat := models.Category{
-		Name:   input.Name,
-		UserID: input.UserID,
+		Name:     input.Name,
+		UserID:   input.UserID,
+		Severity: input.Severity,
 	}
 
 	if err := config.DB.Create(&cat).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"message": "Gagal membuat kategori", "error": err.Error()})
 		return
 	}
+	audit.SetTargetID(c, cat.ID)
+	audit.SetAfter(c, cat)
 	c.JSON(http.StatusOK, gin.H{"message": "Kategori dibuat", "data": cat})
 }
 
@@ -121,19 +134,30 @@ func UpdateCategory(c *gin.Context) {
 	}
 
 	var input struct {
-		Name   string `json:"name" binding:"required"`
-		UserID *uint  `json:"user_id"` // optional
+		Name     string `json:"name" binding:"required"`
+		UserID   *uint  `json:"user_id"`  // optional
+		Severity *int   `json:"severity"` // optional, 1-5
 	}
 	if err := c.ShouldBindJSON(&input); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid input"})
 		return
 	}
 
+	audit.SetBefore(c, cat)
+
 	cat.Name = input.Name
 	if input.UserID != nil {
 		cat.UserID = *input.UserID
 	}
+	if input.Severity != nil {
+		if *input.Severity < 1 || *input.Severity > 5 {
+			c.JSON(http.StatusBadRequest, gin.H{"message": "Severity harus antara 1 sampai 5"})
+			return
+		}
+		cat.Severity = *input.Severity
+	}
 	config.DB.Save(&cat)
+	audit.SetAfter(c, cat)
 
 	c.JSON(http.StatusOK, gin.H{"message": "Kategori diupdate", "data": cat})
 }
@@ -166,6 +190,11 @@ func DeleteCategory(c *gin.Context) {
 		return
 	}
 
+	var cat models.Category
+	if err := config.DB.First(&cat, uint(id)).Error; err == nil {
+		audit.SetBefore(c, cat)
+	}
+
 	if err := config.DB.Delete(&models.Category{}, uint(id)).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"message": "Gagal menghapus kategori"})
 		return
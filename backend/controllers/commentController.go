@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
 // Tambah komentar oleh user login
@@ -34,10 +35,30 @@ func CreateComment(c *gin.Context) {
 		CreatedAt: time.Now(),
 	}
 
-	if err := config.DB.Create(&comment).Error; err != nil {
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&comment).Error; err != nil {
+			return err
+		}
+		if err := enqueueSearchIndex(tx, comment.ReportID); err != nil {
+			return err
+		}
+		return writeReportEvent(tx, eventCommentCreated, comment.ReportID, reportEventData{
+			ActorID:   comment.UserID,
+			ActorRole: c.GetString("role"),
+			CommentID: comment.ID,
+			Text:      comment.Text,
+			CreatedAt: comment.CreatedAt,
+		})
+	})
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"message": "Gagal menambahkan komentar"})
 		return
 	}
+	pushReportActivity(eventCommentCreated, comment.ReportID, gin.H{
+		"comment_id": comment.ID,
+		"text":       comment.Text,
+		"created_at": comment.CreatedAt,
+	})
 
 	c.JSON(http.StatusOK, gin.H{"message": "Komentar berhasil ditambahkan", "data": comment})
 }
@@ -52,7 +73,11 @@ func GetCommentsByReport(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"data": comments})
+	// komentar pelapor laporan anonim tidak menampilkan namanya
+	var report models.Report
+	config.DB.Select("id", "user_id", "is_anonymous").First(&report, reportID)
+
+	c.JSON(http.StatusOK, gin.H{"data": newPublicComments(comments, anonymousReporter(report))})
 }
 
 func GetCommentTrends(c *gin.Context) {
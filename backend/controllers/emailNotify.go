@@ -0,0 +1,206 @@
+package controllers
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"html"
+	"log"
+	"net/http"
+	"net/url"
+	"project-backend/config"
+	"project-backend/mailer"
+	"project-backend/models"
+	"project-backend/notification"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm/clause"
+)
+
+// Template email notifikasi untuk pelapor (lihat mailer/templates)
+const (
+	emailReportReceived  = "report_received"
+	emailReportProcessed = "report_processed"
+	emailReportResolved  = "report_resolved"
+	emailReportRejected  = "report_rejected"
+	emailAdminReply      = "admin_reply"
+)
+
+// Nilai t pada tautan berhenti berlangganan untuk menonaktifkan semua email notifikasi
+const unsubscribeAll = "all"
+
+// emailData adalah isi yang tersedia di template email notifikasi
+type emailData struct {
+	AppName           string
+	Name              string
+	TrackingID        string
+	Title             string
+	Status            string
+	Reason            string
+	Reply             string
+	TrackingURL       string
+	QRURL             string
+	UnsubscribeURL    string
+	UnsubscribeAllURL string
+}
+
+// unsubscribeSignature menandatangani pasangan user dan jenis notifikasi agar tautan
+// berhenti berlangganan tidak bisa dipakai untuk user lain
+func unsubscribeSignature(userID uint, typ string) string {
+	mac := hmac.New(sha256.New, []byte(config.UnsubscribeSecret))
+	mac.Write([]byte(strconv.FormatUint(uint64(userID), 10) + ":" + typ))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func unsubscribeURL(userID uint, typ string) string {
+	q := url.Values{}
+	q.Set("u", strconv.FormatUint(uint64(userID), 10))
+	q.Set("t", typ)
+	q.Set("s", unsubscribeSignature(userID, typ))
+	return config.BackendURL + "/notifications/unsubscribe?" + q.Encode()
+}
+
+// sendReportEmail mengantrekan email template ke pelapor laporan jika pelapor aktif,
+// punya alamat email dan tidak menonaktifkan email untuk jenis notifikasi typ. Hanya
+// kegagalan mengantrekan yang dikembalikan (bisa dicoba ulang).
+func sendReportEmail(report models.Report, template, typ, reason, reply, dedupKey string) error {
+	// tanpa kunci tautan berhenti berlangganan email tidak dikirim (lihat config.UnsubscribeSecret)
+	if config.UnsubscribeSecret == "" {
+		return nil
+	}
+	var owner models.User
+	if err := config.DB.Select("id", "name", "email", "is_active", "language").First(&owner, report.UserID).Error; err != nil {
+		return nil
+	}
+	if !owner.IsActive || owner.Email == "" || !notification.Enabled(config.DB, owner.ID, typ, notification.ChannelEmail) {
+		return nil
+	}
+
+	unsubscribe := unsubscribeURL(owner.ID, typ)
+	msg, err := mailer.Render(owner.Language, template, emailData{
+		AppName:           config.AppName,
+		Name:              owner.Name,
+		TrackingID:        report.TrackingID,
+		Title:             report.Title,
+		Status:            report.Status,
+		Reason:            reason,
+		Reply:             reply,
+		TrackingURL:       config.TrackingURL(report.TrackingID),
+		QRURL:             config.TrackingQRURL(report.TrackingID),
+		UnsubscribeURL:    unsubscribe,
+		UnsubscribeAllURL: unsubscribeURL(owner.ID, unsubscribeAll),
+	})
+	if err != nil {
+		log.Printf("email: gagal menyusun %s laporan %d: %v", template, report.ID, err)
+		return nil
+	}
+	msg.To = []string{owner.Email}
+	msg.Headers = map[string]string{
+		"List-Unsubscribe":      "<" + unsubscribe + ">",
+		"List-Unsubscribe-Post": "List-Unsubscribe=One-Click",
+	}
+	return mailer.EnqueueOnce(config.DB, dedupKey, msg)
+}
+
+// emailReportCreated mengirim tanda terima laporan baru beserta kode QR pelacakan
+func emailReportCreated(report models.Report, dedupKey string) error {
+	return sendReportEmail(report, emailReportReceived, notification.TypeStatusChanged, "", "", dedupKey)
+}
+
+// emailStatusChanged memberi tahu pelapor lewat email saat laporannya diproses,
+// selesai atau ditolak. deskripsi dipakai sebagai keterangan/alasan.
+func emailStatusChanged(report models.Report, deskripsi, dedupKey string) error {
+	var template string
+	switch report.Status {
+	case "Diproses":
+		template = emailReportProcessed
+	case "Selesai":
+		template = emailReportResolved
+	case "Ditolak":
+		template = emailReportRejected
+	default:
+		return nil
+	}
+	return sendReportEmail(report, template, notification.TypeStatusChanged, deskripsi, "", dedupKey)
+}
+
+// emailAdminReplied memberi tahu pelapor lewat email tentang komentar atau tindak
+// lanjut dari petugas
+func emailAdminReplied(typ string, report models.Report, actorID uint, text, dedupKey string) error {
+	if report.UserID == actorID {
+		return nil
+	}
+	return sendReportEmail(report, emailAdminReply, typ, "", text, dedupKey)
+}
+
+// unsubscribeTarget membaca dan memeriksa tanda tangan tautan berhenti berlangganan
+func unsubscribeTarget(c *gin.Context) (uint, string, bool) {
+	if config.UnsubscribeSecret == "" {
+		return 0, "", false
+	}
+	userID64, err := strconv.ParseUint(c.Query("u"), 10, 32)
+	typ := c.Query("t")
+	if err != nil || (typ != unsubscribeAll && !notification.IsValidType(typ)) ||
+		!hmac.Equal([]byte(c.Query("s")), []byte(unsubscribeSignature(uint(userID64), typ))) {
+		return 0, "", false
+	}
+	return uint(userID64), typ, true
+}
+
+// GET /notifications/unsubscribe?u=<user>&t=<jenis|all>&s=<tanda tangan>
+// Tautan dari email notifikasi hanya menampilkan halaman konfirmasi; pemindai tautan
+// di klien email yang membuka GET tidak boleh ikut menonaktifkan email.
+func ConfirmUnsubscribeEmail(c *gin.Context) {
+	if _, _, ok := unsubscribeTarget(c); !ok {
+		unsubscribePage(c, http.StatusBadRequest, "Tautan berhenti berlangganan tidak valid.", "")
+		return
+	}
+	message := "Berhenti menerima email notifikasi jenis ini?"
+	if c.Query("t") == unsubscribeAll {
+		message = "Berhenti menerima semua email notifikasi?"
+	}
+	form := "<form method=\"post\" action=\"" + html.EscapeString(c.Request.URL.RequestURI()) + "\">" +
+		"<button type=\"submit\" style=\"padding:8px 16px;\">Berhenti berlangganan</button></form>"
+	unsubscribePage(c, http.StatusOK, message, form)
+}
+
+// POST /notifications/unsubscribe?u=<user>&t=<jenis|all>&s=<tanda tangan>
+// Dikirim dari tombol halaman konfirmasi atau langsung oleh klien email untuk berhenti
+// berlangganan sekali klik (RFC 8058, body List-Unsubscribe=One-Click). Tidak memerlukan login.
+func UnsubscribeEmail(c *gin.Context) {
+	userID, typ, ok := unsubscribeTarget(c)
+	if !ok {
+		unsubscribePage(c, http.StatusBadRequest, "Tautan berhenti berlangganan tidak valid.", "")
+		return
+	}
+
+	types := []string{typ}
+	if typ == unsubscribeAll {
+		types = types[:0]
+		for _, t := range notification.Types {
+			types = append(types, t.Type)
+		}
+	}
+	prefs := make([]models.NotificationPreference, 0, len(types))
+	for _, t := range types {
+		prefs = append(prefs, models.NotificationPreference{UserID: userID, Type: t, Channel: notification.ChannelEmail, Enabled: false})
+	}
+	err := config.DB.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "type"}, {Name: "channel"}},
+		DoUpdates: clause.AssignmentColumns([]string{"enabled"}),
+	}).Create(&prefs).Error
+	if err != nil {
+		unsubscribePage(c, http.StatusInternalServerError, "Gagal menyimpan preferensi. Silakan coba lagi.", "")
+		return
+	}
+	unsubscribePage(c, http.StatusOK, "Anda tidak akan menerima email notifikasi ini lagi. Pengaturan dapat diubah kembali di halaman preferensi notifikasi.", "")
+}
+
+// unsubscribePage menampilkan pesan, diikuti form (HTML yang sudah di-escape) jika ada
+func unsubscribePage(c *gin.Context, status int, message, form string) {
+	page := "<!DOCTYPE html><html lang=\"id\"><head><meta charset=\"utf-8\"><title>" + html.EscapeString(config.AppName) +
+		"</title></head><body style=\"font-family:Arial,Helvetica,sans-serif;max-width:480px;margin:48px auto;color:#1f2937;\"><h2>" +
+		html.EscapeString(config.AppName) + "</h2><p>" + html.EscapeString(message) + "</p>" + form + "</body></html>"
+	c.Data(status, "text/html; charset=utf-8", []byte(page))
+}
@@ -0,0 +1,56 @@
+package controllers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"project-backend/config"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestConfirmUnsubscribeEmail(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	defer func(secret string) { config.UnsubscribeSecret = secret }(config.UnsubscribeSecret)
+	config.UnsubscribeSecret = ""
+	unsigned, err := url.Parse(unsubscribeURL(7, unsubscribeAll))
+	if err != nil {
+		t.Fatal(err)
+	}
+	config.UnsubscribeSecret = "rahasia-uji"
+	valid, err := url.Parse(unsubscribeURL(7, unsubscribeAll))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		secret   string
+		query    string
+		status   int
+		wantForm bool
+	}{
+		{"tautan valid menampilkan konfirmasi", "rahasia-uji", valid.RawQuery, http.StatusOK, true},
+		{"tanda tangan salah", "rahasia-uji", "u=7&t=all&s=abc", http.StatusBadRequest, false},
+		{"tanda tangan user lain", "rahasia-uji", strings.Replace(valid.RawQuery, "u=7", "u=8", 1), http.StatusBadRequest, false},
+		{"tanpa kunci tautan dinonaktifkan", "", unsigned.RawQuery, http.StatusBadRequest, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config.UnsubscribeSecret = tt.secret
+			// config.DB tidak diisi: GET yang menyentuh database akan panik
+			r := gin.New()
+			r.GET("/notifications/unsubscribe", ConfirmUnsubscribeEmail)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/notifications/unsubscribe?"+tt.query, nil))
+			if w.Code != tt.status {
+				t.Fatalf("status = %d, want %d", w.Code, tt.status)
+			}
+			if got := strings.Contains(w.Body.String(), `<form method="post"`); got != tt.wantForm {
+				t.Errorf("form konfirmasi = %v, want %v", got, tt.wantForm)
+			}
+		})
+	}
+}
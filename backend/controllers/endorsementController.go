@@ -0,0 +1,130 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"project-backend/config"
+	"project-backend/models"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var errAlreadyEndorsed = errors.New("Anda sudah mendukung laporan ini")
+
+// POST /reports/:id/endorse -> warga menandai "saya juga terdampak"
+func EndorseReport(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	var report models.Report
+	if err := config.DB.First(&report, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"message": "Laporan tidak ditemukan"})
+		return
+	}
+
+	if report.UserID == userID {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Tidak dapat mendukung laporan milik sendiri"})
+		return
+	}
+
+	// indeks unik (report_id, user_id) menentukan dukungan ganda, termasuk dari dua
+	// request bersamaan; baris yang bentrok dilewati dan dijawab 409
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		endorsement := models.Endorsement{ReportID: report.ID, UserID: userID}
+		res := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&endorsement)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return errAlreadyEndorsed
+		}
+		return tx.Model(&models.Report{}).Where("id = ?", report.ID).
+			UpdateColumn("endorsement_count", gorm.Expr("endorsement_count + 1")).Error
+	})
+	if err == errAlreadyEndorsed {
+		c.JSON(http.StatusConflict, gin.H{"message": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": "Gagal menambahkan dukungan"})
+		return
+	}
+
+	config.DB.Select("endorsement_count").First(&report, report.ID)
+	updatePriority(&report, "jumlah dukungan warga bertambah")
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Dukungan berhasil ditambahkan",
+		"data": gin.H{
+			"report_id":         report.ID,
+			"endorsed":          true,
+			"endorsement_count": report.EndorsementCount,
+		},
+	})
+}
+
+// DELETE /reports/:id/endorse -> warga menarik kembali dukungannya
+func UnendorseReport(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	var report models.Report
+	if err := config.DB.First(&report, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"message": "Laporan tidak ditemukan"})
+		return
+	}
+
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		res := tx.Where("report_id = ? AND user_id = ?", report.ID, userID).Delete(&models.Endorsement{})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return tx.Model(&models.Report{}).Where("id = ? AND endorsement_count > 0", report.ID).
+			UpdateColumn("endorsement_count", gorm.Expr("endorsement_count - 1")).Error
+	})
+	if err == gorm.ErrRecordNotFound {
+		c.JSON(http.StatusNotFound, gin.H{"message": "Anda belum mendukung laporan ini"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": "Gagal menghapus dukungan"})
+		return
+	}
+
+	config.DB.Select("endorsement_count").First(&report, report.ID)
+	updatePriority(&report, "jumlah dukungan warga berkurang")
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Dukungan berhasil dihapus",
+		"data": gin.H{
+			"report_id":         report.ID,
+			"endorsed":          false,
+			"endorsement_count": report.EndorsementCount,
+		},
+	})
+}
+
+// GET /reports/:id/endorse -> status dukungan user login pada laporan
+func GetEndorsementStatus(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	var report models.Report
+	if err := config.DB.Select("id", "endorsement_count").First(&report, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"message": "Laporan tidak ditemukan"})
+		return
+	}
+
+	var count int64
+	config.DB.Model(&models.Endorsement{}).Where("report_id = ? AND user_id = ?", report.ID, userID).Count(&count)
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": gin.H{
+			"report_id":         report.ID,
+			"endorsed":          count > 0,
+			"endorsement_count": report.EndorsementCount,
+		},
+	})
+}
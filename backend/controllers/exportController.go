@@ -0,0 +1,297 @@
+package controllers
+
+import (
+	"net/http"
+	"project-backend/config"
+	"project-backend/export"
+	"project-backend/models"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// exportRow adalah satu baris laporan untuk ekspor (diambil dengan join, tanpa preload)
+type exportRow struct {
+	ID               uint
+	TrackingID       string
+	CreatedAt        time.Time
+	Title            string
+	Kategori         string
+	Status           string
+	Priority         string
+	Wilayah          string
+	KodeWilayah      string
+	Lokasi           string
+	Latitude         float64
+	Longitude        float64
+	Pelapor          string
+	IsAnonymous      bool
+	EndorsementCount int
+	ResolvedAt       *time.Time
+	Description      string
+}
+
+var reportExportHeader = []string{
+	"ID", "Tracking ID", "Tanggal Dibuat", "Judul", "Kategori", "Status", "Prioritas",
+	"Wilayah", "Kode Wilayah", "Lokasi", "Latitude", "Longitude", "Pelapor", "Anonim",
+	"Dukungan", "Tanggal Selesai", "Deskripsi",
+}
+
+// writeReportExport menulis laporan dari query (sudah berisi filter) baris per baris
+func writeReportExport(w export.Writer, query *gorm.DB, orderBy string) error {
+	rows, err := query.Model(&models.Report{}).
+		Select("reports.id, reports.tracking_id, reports.created_at, reports.title, categories.name AS kategori, " +
+			"reports.status, reports.priority, reports.wilayah, reports.kode_wilayah, reports.lokasi, " +
+			"reports.latitude, reports.longitude, users.name AS pelapor, reports.is_anonymous, " +
+			"reports.endorsement_count, reports.resolved_at, reports.description").
+		Joins("LEFT JOIN categories ON categories.id = reports.category_id").
+		Joins("LEFT JOIN users ON users.id = reports.user_id").
+		Order(orderBy).
+		Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	if err := w.Sheet("Laporan", reportExportHeader); err != nil {
+		return err
+	}
+	for rows.Next() {
+		var r exportRow
+		if err := config.DB.ScanRows(rows, &r); err != nil {
+			return err
+		}
+		if r.IsAnonymous {
+			r.Pelapor = "Anonim"
+		}
+		if err := w.Row(r.ID, r.TrackingID, r.CreatedAt, r.Title, r.Kategori, r.Status, r.Priority,
+			r.Wilayah, r.KodeWilayah, r.Lokasi, r.Latitude, r.Longitude, r.Pelapor, r.IsAnonymous,
+			r.EndorsementCount, r.ResolvedAt, r.Description); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+type statusCount struct {
+	Status string
+	Total  int64
+}
+
+// writeStatsExport menulis ringkasan status, rekap per kategori dan tren per periode
+// ("week"/"month") untuk laporan dalam scope
+func writeStatsExport(w export.Writer, scope func(*gorm.DB) *gorm.DB, period string) error {
+	statuses := []string{"Diajukan", "Diproses", "Selesai", "Ditolak", "Dibatalkan"}
+	reports := func() *gorm.DB { return config.DB.Model(&models.Report{}).Scopes(scope) }
+
+	// ringkasan
+	var byStatus []statusCount
+	if err := reports().Select("reports.status, COUNT(*) AS total").Group("reports.status").Scan(&byStatus).Error; err != nil {
+		return err
+	}
+	counts := map[string]int64{}
+	var total int64
+	for _, s := range byStatus {
+		counts[s.Status] = s.Total
+		total += s.Total
+	}
+	if err := w.Sheet("Ringkasan", []string{"Indikator", "Nilai"}); err != nil {
+		return err
+	}
+	if err := w.Row("Total laporan", total); err != nil {
+		return err
+	}
+	for _, s := range statuses {
+		if err := w.Row("Laporan "+s, counts[s]); err != nil {
+			return err
+		}
+	}
+	var rating struct {
+		Average float64
+		Count   int64
+	}
+	reports().Joins("JOIN report_ratings ON report_ratings.report_id = reports.id").
+		Select("COALESCE(AVG(report_ratings.stars), 0) AS average, COUNT(*) AS count").Scan(&rating)
+	if err := w.Row("Rata-rata kepuasan (1-5)", rating.Average); err != nil {
+		return err
+	}
+	if err := w.Row("Jumlah penilaian", rating.Count); err != nil {
+		return err
+	}
+
+	// rekap per kategori dan status
+	var catRows []labelStatusCount
+	if err := reports().Select("COALESCE(categories.name, '-') AS label, reports.status, COUNT(*) AS total").
+		Joins("LEFT JOIN categories ON categories.id = reports.category_id").
+		Group("categories.name, reports.status").
+		Order("label").
+		Scan(&catRows).Error; err != nil {
+		return err
+	}
+	if err := w.Sheet("Per Kategori", append(append([]string{"Kategori"}, statuses...), "Total")); err != nil {
+		return err
+	}
+	if err := writePivot(w, catRows, statuses); err != nil {
+		return err
+	}
+
+	// tren per periode (dihitung di Go agar tidak bergantung fungsi tanggal database)
+	trend := newPeriodTrend(period)
+	err := reportBatches(reports().Select("reports.id, reports.created_at, reports.status"), 1000,
+		func(r createdRow) uint { return r.ID },
+		func(rows []createdRow) error {
+			trend.add(rows)
+			return nil
+		})
+	if err != nil {
+		return err
+	}
+	if err := w.Sheet("Tren", append(append([]string{"Periode"}, statuses...), "Total")); err != nil {
+		return err
+	}
+	for _, p := range trend.sortedPeriods() {
+		values := []interface{}{p}
+		var sum int64
+		for _, s := range statuses {
+			values = append(values, trend.totals[p][s])
+			sum += trend.totals[p][s]
+		}
+		if err := w.Row(append(values, sum)...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// createdRow adalah kolom laporan untuk tren ekspor
+type createdRow struct {
+	ID        uint
+	CreatedAt time.Time
+	Status    string
+}
+
+// periodTrend menjumlahkan laporan per periode ("week"/"month") dan status,
+// batch demi batch
+type periodTrend struct {
+	period  string
+	totals  map[string]map[string]int64
+	periods []string
+}
+
+func newPeriodTrend(period string) *periodTrend {
+	return &periodTrend{period: period, totals: map[string]map[string]int64{}}
+}
+
+func (t *periodTrend) add(rows []createdRow) {
+	for _, r := range rows {
+		key := r.CreatedAt.Format("2006-01")
+		if t.period == "week" {
+			y, wk := r.CreatedAt.ISOWeek()
+			key = strconv.Itoa(y) + "-W" + twoDigits(wk)
+		}
+		if t.totals[key] == nil {
+			t.totals[key] = map[string]int64{}
+			t.periods = append(t.periods, key)
+		}
+		t.totals[key][r.Status]++
+	}
+}
+
+func (t *periodTrend) sortedPeriods() []string {
+	sort.Strings(t.periods)
+	return t.periods
+}
+
+type labelStatusCount struct {
+	Label  string
+	Status string
+	Total  int64
+}
+
+// writePivot menulis baris (label, status, jumlah) sebagai tabel label x status
+func writePivot(w export.Writer, rows []labelStatusCount, statuses []string) error {
+	var labels []string
+	table := map[string]map[string]int64{}
+	for _, r := range rows {
+		if table[r.Label] == nil {
+			table[r.Label] = map[string]int64{}
+			labels = append(labels, r.Label)
+		}
+		table[r.Label][r.Status] += r.Total
+	}
+	for _, label := range labels {
+		values := []interface{}{label}
+		var sum int64
+		for _, s := range statuses {
+			values = append(values, table[label][s])
+			sum += table[label][s]
+		}
+		if err := w.Row(append(values, sum)...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// startExport menyiapkan header unduhan dan Writer untuk ?format=csv|xlsx
+func startExport(c *gin.Context, name string) (export.Writer, bool) {
+	format := c.DefaultQuery("format", export.FormatCSV)
+	w, err := export.New(format, c.Writer)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return nil, false
+	}
+	filename := name + "-" + time.Now().Format("20060102-1504") + "." + format
+	c.Header("Content-Type", export.ContentType(format))
+	c.Header("Content-Disposition", `attachment; filename="`+filename+`"`)
+	c.Status(http.StatusOK)
+	return w, true
+}
+
+// GET /reports/admin/export?format=csv|xlsx&status=&category_id=&from=&to=&sort=&order=
+// Mengunduh daftar laporan dengan filter yang sama seperti API daftar laporan,
+// dibatasi pada kategori/wilayah tugas admin.
+func ExportReports(c *gin.Context) {
+	w, ok := startExport(c, "laporan")
+	if !ok {
+		return
+	}
+	sortKey, desc := reportSort(c)
+	query := config.DB.Scopes(adminScope(c.GetString("role"), c.GetUint("userID")), reportFilters(c))
+	err := writeReportExport(w, query, orderClause(sortKey, desc))
+	if err == nil {
+		err = w.Close()
+	}
+	if err != nil {
+		// header sudah terkirim, jadi kegagalan hanya bisa dicatat
+		c.Error(err)
+	}
+}
+
+// GET /admin/stats/export?format=csv|xlsx&period=month|week&from=&to=&category_id=
+// Mengunduh ringkasan statistik, rekap per kategori dan tren laporan
+func ExportStats(c *gin.Context) {
+	period := c.DefaultQuery("period", "month")
+	if period != "week" && period != "month" {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid period"})
+		return
+	}
+	w, ok := startExport(c, "statistik-laporan")
+	if !ok {
+		return
+	}
+	role, adminID := c.GetString("role"), c.GetUint("userID")
+	scope := func(db *gorm.DB) *gorm.DB {
+		return db.Scopes(adminScope(role, adminID), reportFilters(c))
+	}
+	err := writeStatsExport(w, scope, period)
+	if err == nil {
+		err = w.Close()
+	}
+	if err != nil {
+		c.Error(err)
+	}
+}
@@ -0,0 +1,54 @@
+package controllers
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestPeriodTrendAcrossBatches(t *testing.T) {
+	at := func(s string) time.Time {
+		v, err := time.ParseInLocation("2006-01-02", s, time.Local)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return v
+	}
+	batches := [][]createdRow{
+		{{ID: 1, CreatedAt: at("2025-08-04"), Status: "Diajukan"}, {ID: 2, CreatedAt: at("2025-08-05"), Status: "Selesai"}},
+		{{ID: 3, CreatedAt: at("2025-07-31"), Status: "Diajukan"}, {ID: 4, CreatedAt: at("2025-08-11"), Status: "Diajukan"}},
+		{{ID: 5, CreatedAt: at("2025-08-04"), Status: "Diajukan"}},
+	}
+
+	tests := []struct {
+		period string
+		want   map[string]map[string]int64
+	}{
+		{"month", map[string]map[string]int64{
+			"2025-07": {"Diajukan": 1},
+			"2025-08": {"Diajukan": 3, "Selesai": 1},
+		}},
+		{"week", map[string]map[string]int64{
+			"2025-W31": {"Diajukan": 1},
+			"2025-W32": {"Diajukan": 2, "Selesai": 1},
+			"2025-W33": {"Diajukan": 1},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.period, func(t *testing.T) {
+			trend := newPeriodTrend(tt.period)
+			for _, b := range batches {
+				trend.add(b)
+			}
+			if !reflect.DeepEqual(trend.totals, tt.want) {
+				t.Errorf("totals = %v, want %v", trend.totals, tt.want)
+			}
+			periods := trend.sortedPeriods()
+			for i := 1; i < len(periods); i++ {
+				if periods[i-1] >= periods[i] {
+					t.Errorf("periode tidak urut: %v", periods)
+				}
+			}
+		})
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
 // Admin menambahkan tindak lanjut
@@ -59,10 +60,32 @@ func CreateFollowUp(c *gin.Context) {
 		CreatedAt: time.Now(),
 	}
 
-	if err := config.DB.Create(&followUp).Error; err != nil {
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&followUp).Error; err != nil {
+			return err
+		}
+		if err := enqueueSearchIndex(tx, followUp.ReportID); err != nil {
+			return err
+		}
+		return writeReportEvent(tx, eventFollowUpCreated, followUp.ReportID, reportEventData{
+			ActorID:    adminID,
+			ActorRole:  c.GetString("role"),
+			FollowUpID: followUp.ID,
+			Text:       followUp.Deskripsi,
+			PhotoURL:   followUp.PhotoURL,
+			CreatedAt:  followUp.CreatedAt,
+		})
+	})
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"message": "Gagal menambahkan tindak lanjut"})
 		return
 	}
+	pushReportActivity(eventFollowUpCreated, followUp.ReportID, gin.H{
+		"followup_id": followUp.ID,
+		"deskripsi":   followUp.Deskripsi,
+		"photo_url":   followUp.PhotoURL,
+		"created_at":  followUp.CreatedAt,
+	})
 
 	c.JSON(http.StatusOK, gin.H{"message": "Tindak lanjut berhasil ditambahkan", "data": followUp})
 }
@@ -77,7 +100,7 @@ func GetFollowUpsByReport(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"data": followups})
+	c.JSON(http.StatusOK, gin.H{"data": newPublicFollowUps(followups)})
 }
 
 func GetFollowupTrends(c *gin.Context) {
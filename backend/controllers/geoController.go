@@ -0,0 +1,259 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"project-backend/config"
+	"project-backend/geo"
+	"project-backend/models"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultNearbyRadius = 1000.0  // meter
+	maxNearbyRadius     = 50000.0 // meter
+	defaultGeoLimit     = 50
+	maxGeoLimit         = 200
+)
+
+var errOutsideJurisdiction = errors.New("Lokasi berada di luar wilayah layanan")
+
+// resolveRegion mencari kode wilayah paling rinci dan nama kabupaten/kota dari koordinat.
+// Jika batas wilayah tidak dimuat, lookup dilewati tanpa error.
+func resolveRegion(lat, lon float64) (code, kabupaten string, err error) {
+	idx := geo.Boundaries()
+	if idx == nil {
+		return "", "", nil
+	}
+	loc, ok := idx.Lookup(lat, lon)
+	if !ok {
+		return "", "", errOutsideJurisdiction
+	}
+	if loc.Kabupaten != nil {
+		kabupaten = loc.Kabupaten.Name
+	}
+	return loc.Code(), kabupaten, nil
+}
+
+// applyRegion memperbarui KodeWilayah dan Wilayah laporan sesuai koordinatnya
+func applyRegion(report *models.Report) error {
+	code, kabupaten, err := resolveRegion(report.Latitude, report.Longitude)
+	if err != nil {
+		return err
+	}
+	if code != "" {
+		report.KodeWilayah = code
+		report.RegionID = regionIDByCode(code)
+	}
+	if kabupaten != "" {
+		report.Wilayah = kabupaten
+	}
+	return nil
+}
+
+// GET /wilayah/lookup?lat=-7.78&lon=110.36
+// Menampilkan hierarki wilayah dari koordinat, dipakai form pengaduan sebelum dikirim.
+func LookupRegion(c *gin.Context) {
+	lat, okLat := parseCoordQuery(c, "lat", -90, 90)
+	lon, okLon := parseCoordQuery(c, "lon", -180, 180)
+	if !okLat || !okLon {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Parameter lat dan lon wajib diisi dengan koordinat yang valid"})
+		return
+	}
+	idx := geo.Boundaries()
+	if idx == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"message": "Data batas wilayah belum tersedia"})
+		return
+	}
+	loc, ok := idx.Lookup(lat, lon)
+	if !ok {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"message": errOutsideJurisdiction.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": loc, "kode_wilayah": loc.Code()})
+}
+
+// geoReport adalah ringkasan publik laporan beserta jaraknya (meter) dari titik acuan.
+// Jarak dihitung dari koordinat yang sudah disamarkan.
+type geoReport struct {
+	publicReport
+	DistanceM float64 `json:"distance_m"`
+}
+
+func parseCoordQuery(c *gin.Context, key string, min, max float64) (float64, bool) {
+	v, err := strconv.ParseFloat(strings.TrimSpace(c.Query(key)), 64)
+	if err != nil || v < min || v > max {
+		return 0, false
+	}
+	return v, true
+}
+
+func geoLimit(c *gin.Context) int {
+	limit := defaultGeoLimit
+	if l, err := strconv.Atoi(c.Query("limit")); err == nil && l > 0 {
+		limit = l
+	}
+	if limit > maxGeoLimit {
+		limit = maxGeoLimit
+	}
+	return limit
+}
+
+// loadGeoReports memuat laporan sesuai urutan points sebagai publicReport dengan
+// koordinat disamarkan sejauh fuzz meter, lalu menghitung jaraknya dari refLat/refLon
+func loadGeoReports(points []reportPoint, refLat, refLon, fuzz float64) ([]geoReport, error) {
+	byID, err := loadPointReports(points, "User", "Category")
+	if err != nil {
+		return nil, err
+	}
+	result := make([]geoReport, 0, len(points))
+	for _, p := range points {
+		if r, ok := byID[p.ID]; ok {
+			pr := newPublicReport(r, fuzz)
+			result = append(result, geoReport{
+				publicReport: pr,
+				DistanceM:    geo.Distance(refLat, refLon, pr.Latitude, pr.Longitude),
+			})
+		}
+	}
+	return result, nil
+}
+
+// GET /reports/nearby?lat=-7.79&lon=110.37&radius_m=1000&limit=50&fuzz_m=
+// Laporan dalam radius tertentu, diurutkan dari yang terdekat. Filter daftar laporan
+// (status, category_id, dst.) juga berlaku kecuali is_anonymous dan assignee. Koordinat
+// disamarkan seperti peta publik.
+func GetNearbyReports(c *gin.Context) {
+	lat, okLat := parseCoordQuery(c, "lat", -90, 90)
+	lon, okLon := parseCoordQuery(c, "lon", -180, 180)
+	if !okLat || !okLon {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Parameter lat dan lon wajib diisi dengan koordinat yang valid"})
+		return
+	}
+	fuzz, ok := parseFuzzQuery(c)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "fuzz_m harus berupa angka positif"})
+		return
+	}
+	radius := defaultNearbyRadius
+	if v := c.Query("radius_m"); v != "" {
+		r, err := strconv.ParseFloat(v, 64)
+		if err != nil || r <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"message": "radius_m harus berupa angka positif"})
+			return
+		}
+		radius = r
+	}
+	if radius > maxNearbyRadius {
+		radius = maxNearbyRadius
+	}
+	limit := geoLimit(c)
+
+	// saring kasar dengan prefix geohash dan bounding box, lalu hitung jarak dari
+	// koordinat samaran dengan haversine (lihat snapPoints)
+	box := geo.RadiusBox(lat, lon, radius)
+	cells := geo.CoveringCells(geo.SnapBox(box, reportFuzz(true, fuzz)))
+	conds := make([]string, len(cells))
+	args := make([]interface{}, len(cells))
+	for i, cell := range cells {
+		conds[i] = "reports.geohash LIKE ?"
+		args[i] = cell + "%"
+	}
+	query := config.DB.Model(&models.Report{}).
+		Where("("+strings.Join(conds, " OR ")+")", args...).
+		Scopes(publicReportFilters(c))
+
+	points, err := publicPoints(query, box, fuzz)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": "Gagal mengambil laporan terdekat"})
+		return
+	}
+
+	within := points[:0]
+	for _, p := range points {
+		p.distance = geo.Distance(lat, lon, p.Latitude, p.Longitude)
+		if p.distance <= radius {
+			within = append(within, p)
+		}
+	}
+	sort.Slice(within, func(i, j int) bool {
+		if within[i].distance == within[j].distance {
+			return within[i].ID < within[j].ID
+		}
+		return within[i].distance < within[j].distance
+	})
+
+	meta := ListMeta{Total: int64(len(within)), Limit: limit}
+	if len(within) > limit {
+		meta.HasMore = true
+		within = within[:limit]
+	}
+
+	reports, err := loadGeoReports(within, lat, lon, fuzz)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": "Gagal mengambil laporan terdekat"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"data":   reports,
+		"meta":   meta,
+		"center": gin.H{"lat": lat, "lon": lon, "radius_m": radius},
+	})
+}
+
+// GET /reports/bbox?min_lat=&min_lon=&max_lat=&max_lon=&limit=100&fuzz_m=
+// Laporan di dalam area peta yang sedang ditampilkan. distance_m dihitung dari titik
+// tengah area, atau dari ?lat=&lon= jika diisi. Urutan mengikuti ?sort=&order=.
+func GetReportsInBBox(c *gin.Context) {
+	var box geo.BoundingBox
+	var ok [4]bool
+	box.MinLat, ok[0] = parseCoordQuery(c, "min_lat", -90, 90)
+	box.MinLon, ok[1] = parseCoordQuery(c, "min_lon", -180, 180)
+	box.MaxLat, ok[2] = parseCoordQuery(c, "max_lat", -90, 90)
+	box.MaxLon, ok[3] = parseCoordQuery(c, "max_lon", -180, 180)
+	if !ok[0] || !ok[1] || !ok[2] || !ok[3] || box.MinLat > box.MaxLat || box.MinLon > box.MaxLon {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Parameter min_lat, min_lon, max_lat dan max_lon wajib diisi dengan area yang valid"})
+		return
+	}
+
+	fuzz, fuzzOK := parseFuzzQuery(c)
+	if !fuzzOK {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "fuzz_m harus berupa angka positif"})
+		return
+	}
+
+	refLat, refLon := box.Center()
+	if lat, okLat := parseCoordQuery(c, "lat", -90, 90); okLat {
+		if lon, okLon := parseCoordQuery(c, "lon", -180, 180); okLon {
+			refLat, refLon = lat, lon
+		}
+	}
+	limit := geoLimit(c)
+
+	sortKey, desc := reportSort(c)
+	query := config.DB.Model(&models.Report{}).
+		Scopes(publicReportFilters(c)).
+		Order(orderClause(sortKey, desc))
+
+	points, err := publicPoints(query, box, fuzz)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": "Gagal mengambil laporan"})
+		return
+	}
+	meta := ListMeta{Total: int64(len(points)), Limit: limit}
+	if len(points) > limit {
+		meta.HasMore = true
+		points = points[:limit]
+	}
+
+	reports, err := loadGeoReports(points, refLat, refLon, fuzz)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": "Gagal mengambil laporan"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": reports, "meta": meta})
+}
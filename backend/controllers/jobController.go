@@ -0,0 +1,190 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"image"
+	"net/http"
+	"os"
+	"project-backend/audit"
+	"project-backend/config"
+	"project-backend/jobs"
+	"project-backend/models"
+	"project-backend/outbox"
+	"project-backend/search"
+	"project-backend/thumbnail"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// Jenis job latar
+const (
+	jobPhotoThumbnail   = "photo.thumbnail"
+	jobSearchIndex      = "search.index"
+	jobSubscriptionsRun = "subscriptions.run"
+	jobSLACheck         = "sla.check"
+	jobOutboxCleanup    = "outbox.cleanup"
+	jobJobsCleanup      = "jobs.cleanup"
+)
+
+// Event outbox dan job sukses disimpan selama ini sebelum dibersihkan
+const finishedRetention = 7 * 24 * time.Hour
+
+type thumbnailPayload struct {
+	BuktiFotoID uint `json:"bukti_foto_id"`
+}
+
+type searchIndexPayload struct {
+	ReportID uint `json:"report_id"`
+}
+
+// RegisterJobs mendaftarkan handler job latar dan jadwal cron-nya. Dipanggil saat start-up.
+func RegisterJobs() error {
+	jobs.Register(jobPhotoThumbnail, jobs.Options{Concurrency: 2, Timeout: time.Minute}, generateThumbnail)
+	jobs.Register(jobSearchIndex, jobs.Options{Concurrency: 2, Timeout: time.Minute}, func(ctx context.Context, p searchIndexPayload) error {
+		return search.IndexReport(ctx, p.ReportID)
+	})
+
+	// tugas berkala hanya boleh satu yang berjalan dan tidak perlu dicoba ulang berkali-kali
+	// karena jadwal berikutnya akan segera tiba
+	periodic := jobs.Options{Concurrency: 1, MaxAttempts: 2}
+	jobs.Register(jobSubscriptionsRun, periodic, func(ctx context.Context, _ struct{}) error {
+		return RunDueSubscriptions(ctx, time.Now())
+	})
+	jobs.Register(jobSLACheck, periodic, func(ctx context.Context, _ struct{}) error {
+		return CheckSLABreaches(ctx, time.Now())
+	})
+	jobs.Register(jobOutboxCleanup, periodic, func(ctx context.Context, _ struct{}) error {
+		return outbox.Cleanup(config.DB.WithContext(ctx), time.Now().Add(-finishedRetention))
+	})
+	jobs.Register(jobJobsCleanup, periodic, func(ctx context.Context, _ struct{}) error {
+		return jobs.Cleanup(config.DB.WithContext(ctx), time.Now().Add(-finishedRetention))
+	})
+
+	schedules := []struct{ name, spec, typ string }{
+		{"report-subscriptions", "* * * * *", jobSubscriptionsRun},
+		{"sla-breach-check", "*/10 * * * *", jobSLACheck},
+		{"outbox-cleanup", "0 * * * *", jobOutboxCleanup},
+		{"jobs-cleanup", "30 3 * * *", jobJobsCleanup},
+	}
+	for _, s := range schedules {
+		if err := jobs.Schedule(s.name, s.spec, s.typ, struct{}{}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// enqueueThumbnail memasukkan job pembuatan thumbnail foto bukti. db boleh transaksi.
+func enqueueThumbnail(db *gorm.DB, buktiFotoID uint) error {
+	_, err := jobs.Enqueue(db, jobPhotoThumbnail, thumbnailPayload{BuktiFotoID: buktiFotoID}, jobs.EnqueueOptions{})
+	return err
+}
+
+// enqueueSearchIndex memasukkan job pembaruan indeks pencarian laporan. db boleh transaksi.
+func enqueueSearchIndex(db *gorm.DB, reportID uint) error {
+	_, err := jobs.Enqueue(db, jobSearchIndex, searchIndexPayload{ReportID: reportID}, jobs.EnqueueOptions{})
+	return err
+}
+
+func generateThumbnail(ctx context.Context, p thumbnailPayload) error {
+	db := config.DB.WithContext(ctx)
+	var foto models.BuktiFoto
+	if err := db.First(&foto, p.BuktiFotoID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil // foto sudah dihapus
+		}
+		return err
+	}
+	dst := thumbnail.Path(foto.PhotoURL)
+	if err := thumbnail.Generate(foto.PhotoURL, dst, thumbnail.MaxSize); err != nil {
+		// berkas hilang, format tidak didukung atau dimensi terlalu besar tidak akan
+		// berhasil walau dicoba ulang
+		if errors.Is(err, os.ErrNotExist) || errors.Is(err, image.ErrFormat) || errors.Is(err, thumbnail.ErrTooLarge) {
+			return jobs.Permanent(err)
+		}
+		return err
+	}
+	return db.Model(&foto).Update("thumbnail_url", dst).Error
+}
+
+// GET /admin/jobs?status=dead&type=photo.thumbnail&page=1&limit=20
+// Daftar job beserta jumlah job per jenis dan status
+func GetJobs(c *gin.Context) {
+	meta := ListMeta{Page: 1, Limit: defaultPageLimit}
+	if l, err := strconv.Atoi(c.Query("limit")); err == nil && l > 0 {
+		meta.Limit = l
+	}
+	if meta.Limit > maxPageLimit {
+		meta.Limit = maxPageLimit
+	}
+	if p, err := strconv.Atoi(c.Query("page")); err == nil && p > 1 {
+		meta.Page = p
+	}
+
+	db := config.DB.Model(&models.Job{})
+	if v := splitQuery(c.Query("status")); len(v) > 0 {
+		db = db.Where("status IN ?", v)
+	}
+	if v := splitQuery(c.Query("type")); len(v) > 0 {
+		db = db.Where("type IN ?", v)
+	}
+	if err := db.Session(&gorm.Session{}).Count(&meta.Total).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": "Gagal mengambil job"})
+		return
+	}
+	var list []models.Job
+	if err := db.Order("id DESC").Offset((meta.Page - 1) * meta.Limit).Limit(meta.Limit).Find(&list).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": "Gagal mengambil job"})
+		return
+	}
+	meta.HasMore = int64(meta.Page*meta.Limit) < meta.Total
+
+	var counts []struct {
+		Type   string `json:"type"`
+		Status string `json:"status"`
+		Count  int64  `json:"count"`
+	}
+	config.DB.Model(&models.Job{}).Select("type, status, COUNT(*) AS count").Group("type, status").Order("type, status").Scan(&counts)
+
+	c.JSON(http.StatusOK, gin.H{"data": list, "meta": meta, "counts": counts})
+}
+
+// GET /admin/jobs/schedules -> job terjadwal (cron) beserta waktu berikutnya
+func GetJobSchedules(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"data": jobs.Schedules()})
+}
+
+// GET /admin/jobs/:id
+func GetJob(c *gin.Context) {
+	var job models.Job
+	if err := config.DB.First(&job, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"message": "Job tidak ditemukan"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": job})
+}
+
+// POST /admin/jobs/:id/retry -> masukkan kembali job dead ke antrean
+func RetryJob(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "ID job tidak valid"})
+		return
+	}
+	job, err := jobs.RetryDead(config.DB, uint(id))
+	if errors.Is(err, jobs.ErrNotDead) {
+		c.JSON(http.StatusConflict, gin.H{"message": "Hanya job berstatus dead yang bisa dicoba ulang"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": "Gagal mencoba ulang job"})
+		return
+	}
+	audit.SetAfter(c, gin.H{"type": job.Type, "status": job.Status})
+
+	c.JSON(http.StatusOK, gin.H{"message": "Job dimasukkan kembali ke antrean", "data": job})
+}
@@ -0,0 +1,331 @@
+package controllers
+
+import (
+	"net/http"
+	"project-backend/config"
+	"project-backend/geo"
+	"project-backend/models"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+const (
+	// Di zoom ini ke atas titik tidak lagi dikelompokkan
+	mapClusterMaxZoom = 15
+	mapMaxFeatures    = 2000
+	// Laporan anonim selalu disamarkan minimal sejauh ini (meter)
+	anonymousFuzzMeters = 250.0
+	maxFuzzMeters       = 5000.0
+)
+
+// Presisi geohash untuk pengelompokan per level zoom (indeks = zoom)
+var clusterPrecisionByZoom = []int{1, 1, 1, 2, 2, 3, 3, 3, 4, 4, 5, 5, 5, 6, 6}
+
+// GeoJSON FeatureCollection sederhana (RFC 7946)
+type geoJSONPoint struct {
+	Type        string     `json:"type"`
+	Coordinates [2]float64 `json:"coordinates"` // [lon, lat]
+}
+
+type geoJSONFeature struct {
+	Type       string                 `json:"type"`
+	ID         string                 `json:"id,omitempty"`
+	Geometry   geoJSONPoint           `json:"geometry"`
+	Properties map[string]interface{} `json:"properties"`
+}
+
+type geoJSONCollection struct {
+	Type     string           `json:"type"`
+	Features []geoJSONFeature `json:"features"`
+	BBox     []float64        `json:"bbox,omitempty"`
+}
+
+func pointFeature(id string, lat, lon float64, props map[string]interface{}) geoJSONFeature {
+	return geoJSONFeature{
+		Type:       "Feature",
+		ID:         id,
+		Geometry:   geoJSONPoint{Type: "Point", Coordinates: [2]float64{lon, lat}},
+		Properties: props,
+	}
+}
+
+// reportFuzz mengembalikan jarak penyamaran koordinat satu laporan; laporan anonim
+// selalu disamarkan minimal anonymousFuzzMeters
+func reportFuzz(anonymous bool, fuzz float64) float64 {
+	if anonymous && fuzz < anonymousFuzzMeters {
+		return anonymousFuzzMeters
+	}
+	return fuzz
+}
+
+// parseFuzzQuery membaca ?fuzz_m= (meter, dibatasi maxFuzzMeters)
+func parseFuzzQuery(c *gin.Context) (float64, bool) {
+	v := c.Query("fuzz_m")
+	if v == "" {
+		return 0, true
+	}
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil || f < 0 {
+		return 0, false
+	}
+	if f > maxFuzzMeters {
+		f = maxFuzzMeters
+	}
+	return f, true
+}
+
+// reportFeature hanya memuat properti yang aman dipublikasikan: tanpa data pelapor,
+// dan koordinat laporan anonim selalu disamarkan.
+func reportFeature(r models.Report, fuzz float64) geoJSONFeature {
+	lat, lon, fuzzed := publicCoords(r, fuzz)
+
+	props := map[string]interface{}{
+		"id":                r.ID,
+		"tracking_id":       r.TrackingID,
+		"title":             r.Title,
+		"status":            r.Status,
+		"priority":          r.Priority,
+		"category_id":       r.CategoryID,
+		"category":          r.Category.Name,
+		"wilayah":           r.Wilayah,
+		"endorsement_count": r.EndorsementCount,
+		"created_at":        r.CreatedAt.Format(time.RFC3339),
+		"fuzzed":            fuzzed,
+	}
+	return pointFeature("report-"+strconv.FormatUint(uint64(r.ID), 10), lat, lon, props)
+}
+
+// GET /reports/map?zoom=12&bbox=minLon,minLat,maxLon,maxLat&category_id=&status=&from=&to=&fuzz_m=
+// Peta publik dalam format GeoJSON. Di bawah zoom 15 titik dikelompokkan per sel
+// geohash; kelompok berisi satu laporan tetap dikirim sebagai titik laporan.
+func GetReportsMap(c *gin.Context) {
+	zoom := mapClusterMaxZoom
+	if v := c.Query("zoom"); v != "" {
+		z, err := strconv.Atoi(v)
+		if err != nil || z < 0 || z > 22 {
+			c.JSON(http.StatusBadRequest, gin.H{"message": "zoom harus bernilai 0 sampai 22"})
+			return
+		}
+		zoom = z
+	}
+
+	fuzz, ok := parseFuzzQuery(c)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "fuzz_m harus berupa angka positif"})
+		return
+	}
+
+	// laporan yang dibatalkan pelapor tidak ditampilkan di peta publik
+	query := config.DB.Model(&models.Report{}).
+		Where("reports.status <> ?", "Dibatalkan").
+		Scopes(publicReportFilters(c)).
+		Order("reports.created_at DESC, reports.id DESC")
+
+	box := worldBox
+	var bbox []float64
+	if v := c.Query("bbox"); v != "" {
+		parts := strings.Split(v, ",")
+		valid := len(parts) == 4
+		if valid {
+			vals := make([]float64, 4)
+			for i, p := range parts {
+				f, err := strconv.ParseFloat(strings.TrimSpace(p), 64)
+				if err != nil {
+					valid = false
+					break
+				}
+				vals[i] = f
+			}
+			box = geo.BoundingBox{MinLon: vals[0], MinLat: vals[1], MaxLon: vals[2], MaxLat: vals[3]}
+			valid = valid && box.MinLat <= box.MaxLat && box.MinLon <= box.MaxLon
+		}
+		if !valid {
+			c.JSON(http.StatusBadRequest, gin.H{"message": "bbox harus berformat minLon,minLat,maxLon,maxLat"})
+			return
+		}
+		bbox = []float64{box.MinLon, box.MinLat, box.MaxLon, box.MaxLat}
+	}
+
+	points, err := publicPoints(query, box, fuzz)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": "Gagal mengambil data peta"})
+		return
+	}
+	var features []geoJSONFeature
+	if zoom < mapClusterMaxZoom {
+		features, err = clusterFeatures(points, clusterPrecisionByZoom[zoom], fuzz)
+	} else {
+		features, err = reportFeatures(points, fuzz)
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": "Gagal mengambil data peta"})
+		return
+	}
+	if features == nil {
+		features = []geoJSONFeature{}
+	}
+
+	c.Header("Content-Type", "application/geo+json; charset=utf-8")
+	c.Header("Cache-Control", "public, max-age=60")
+	c.JSON(http.StatusOK, geoJSONCollection{Type: "FeatureCollection", Features: features, BBox: bbox})
+}
+
+// reportPoint adalah koordinat satu laporan untuk endpoint lokasi publik
+type reportPoint struct {
+	ID          uint
+	Latitude    float64
+	Longitude   float64
+	IsAnonymous bool
+	distance    float64
+}
+
+// Seluruh permukaan bumi, untuk peta tanpa ?bbox=
+var worldBox = geo.BoundingBox{MinLat: -90, MinLon: -180, MaxLat: 90, MaxLon: 180}
+
+// publicPoints mengambil koordinat laporan dari query (urutan query dipertahankan) lalu
+// menyaringnya dengan snapPoints. Query disaring kasar dengan box yang diperlebar sejauh
+// pergeseran maksimum penyamaran agar laporan yang koordinat samarannya masuk box ikut terambil.
+func publicPoints(query *gorm.DB, box geo.BoundingBox, fuzz float64) ([]reportPoint, error) {
+	outer := geo.SnapBox(box, reportFuzz(true, fuzz))
+	var points []reportPoint
+	if err := query.
+		Select("reports.id, reports.latitude, reports.longitude, reports.is_anonymous").
+		Where("reports.latitude BETWEEN ? AND ? AND reports.longitude BETWEEN ? AND ?",
+			outer.MinLat, outer.MaxLat, outer.MinLon, outer.MaxLon).
+		Scan(&points).Error; err != nil {
+		return nil, err
+	}
+	return snapPoints(points, box, fuzz), nil
+}
+
+// snapPoints mengganti koordinat tiap laporan dengan koordinat samarannya dan hanya
+// menyisakan laporan yang koordinat samarannya berada di dalam box. Penyaringan area, jarak
+// dan jumlah total di endpoint publik dihitung dari koordinat ini: jika dihitung dari
+// koordinat asli, mengecilkan radius atau bbox sedikit demi sedikit membuka lokasi laporan anonim.
+func snapPoints(points []reportPoint, box geo.BoundingBox, fuzz float64) []reportPoint {
+	within := points[:0]
+	for _, p := range points {
+		p.Latitude, p.Longitude = geo.Snap(p.Latitude, p.Longitude, reportFuzz(p.IsAnonymous, fuzz))
+		if box.Contains(p.Latitude, p.Longitude) {
+			within = append(within, p)
+		}
+	}
+	return within
+}
+
+// loadPointReports memuat laporan dari points, diindeks dengan ID-nya
+func loadPointReports(points []reportPoint, preloads ...string) (map[uint]models.Report, error) {
+	byID := make(map[uint]models.Report, len(points))
+	if len(points) == 0 {
+		return byID, nil
+	}
+	ids := make([]uint, len(points))
+	for i, p := range points {
+		ids[i] = p.ID
+	}
+	query := config.DB
+	for _, p := range preloads {
+		query = query.Preload(p)
+	}
+	var reports []models.Report
+	if err := query.Where("id IN ?", ids).Find(&reports).Error; err != nil {
+		return nil, err
+	}
+	for _, r := range reports {
+		byID[r.ID] = r
+	}
+	return byID, nil
+}
+
+func reportFeatures(points []reportPoint, fuzz float64) ([]geoJSONFeature, error) {
+	if len(points) > mapMaxFeatures {
+		points = points[:mapMaxFeatures]
+	}
+	byID, err := loadPointReports(points, "Category")
+	if err != nil {
+		return nil, err
+	}
+	features := make([]geoJSONFeature, 0, len(points))
+	for _, p := range points {
+		if r, ok := byID[p.ID]; ok {
+			features = append(features, reportFeature(r, fuzz))
+		}
+	}
+	return features, nil
+}
+
+type mapCluster struct {
+	Cell     string
+	Total    int64
+	Lat      float64
+	Lon      float64
+	ReportID uint
+}
+
+// clusterPoints mengelompokkan laporan per sel geohash dengan panjang precision. Sel dan
+// titik tengah kelompok dihitung dari koordinat yang sudah disamarkan (lihat snapPoints)
+// agar kelompok kecil tidak membuka lokasi asli.
+func clusterPoints(points []reportPoint, precision int) []mapCluster {
+	var clusters []mapCluster
+	index := map[string]int{}
+	for _, p := range points {
+		cell := geo.Encode(p.Latitude, p.Longitude, precision)
+		i, ok := index[cell]
+		if !ok {
+			if len(clusters) >= mapMaxFeatures {
+				continue
+			}
+			i = len(clusters)
+			index[cell] = i
+			clusters = append(clusters, mapCluster{Cell: cell, ReportID: p.ID})
+		}
+		cl := &clusters[i]
+		cl.Total++
+		cl.Lat += p.Latitude
+		cl.Lon += p.Longitude
+		if p.ID < cl.ReportID {
+			cl.ReportID = p.ID
+		}
+	}
+	for i := range clusters {
+		clusters[i].Lat /= float64(clusters[i].Total)
+		clusters[i].Lon /= float64(clusters[i].Total)
+	}
+	return clusters
+}
+
+func clusterFeatures(points []reportPoint, precision int, fuzz float64) ([]geoJSONFeature, error) {
+	clusters := clusterPoints(points, precision)
+
+	// kelompok yang hanya berisi satu laporan dikirim sebagai titik laporan biasa
+	var singles []reportPoint
+	for _, cl := range clusters {
+		if cl.Total == 1 {
+			singles = append(singles, reportPoint{ID: cl.ReportID})
+		}
+	}
+	byID, err := loadPointReports(singles, "Category")
+	if err != nil {
+		return nil, err
+	}
+
+	features := make([]geoJSONFeature, 0, len(clusters))
+	for _, cl := range clusters {
+		if cl.Total == 1 {
+			if r, ok := byID[cl.ReportID]; ok {
+				features = append(features, reportFeature(r, fuzz))
+			}
+			continue
+		}
+		features = append(features, pointFeature("cluster-"+cl.Cell, cl.Lat, cl.Lon, map[string]interface{}{
+			"cluster":     true,
+			"geohash":     cl.Cell,
+			"point_count": cl.Total,
+		}))
+	}
+	return features, nil
+}
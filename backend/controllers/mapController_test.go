@@ -0,0 +1,72 @@
+package controllers
+
+import (
+	"math"
+	"testing"
+
+	"project-backend/geo"
+)
+
+func TestClusterPointsUsesFuzzedCoordinates(t *testing.T) {
+	points := []reportPoint{
+		{ID: 7, Latitude: -7.78291, Longitude: 110.36712, IsAnonymous: true},
+		{ID: 3, Latitude: -7.78305, Longitude: 110.36698, IsAnonymous: true},
+		{ID: 9, Latitude: -7.70001, Longitude: 110.40002},
+	}
+	exact := append([]reportPoint(nil), points...)
+	clusters := clusterPoints(snapPoints(points, worldBox, 0), 5)
+	if len(clusters) != 2 {
+		t.Fatalf("len(clusters) = %d, want 2", len(clusters))
+	}
+
+	anon := clusters[0]
+	if anon.Total != 2 || anon.ReportID != 3 {
+		t.Errorf("cluster = %+v, want total 2 and report_id 3", anon)
+	}
+	lat1, lon1 := geo.Snap(exact[0].Latitude, exact[0].Longitude, anonymousFuzzMeters)
+	lat2, lon2 := geo.Snap(exact[1].Latitude, exact[1].Longitude, anonymousFuzzMeters)
+	if !near(anon.Lat, (lat1+lat2)/2) || !near(anon.Lon, (lon1+lon2)/2) {
+		t.Errorf("centroid = %v,%v, want rata-rata koordinat samaran %v,%v", anon.Lat, anon.Lon, (lat1+lat2)/2, (lon1+lon2)/2)
+	}
+	exactLat := (exact[0].Latitude + exact[1].Latitude) / 2
+	if near(anon.Lat, exactLat) {
+		t.Errorf("centroid %v sama dengan rata-rata koordinat asli", anon.Lat)
+	}
+
+	// laporan tidak anonim tanpa fuzz_m tetap di koordinat aslinya
+	if single := clusters[1]; single.Total != 1 || !near(single.Lat, exact[2].Latitude) {
+		t.Errorf("cluster = %+v, want titik asli laporan 9", single)
+	}
+}
+
+func TestSnapPointsFiltersOnFuzzedCoordinates(t *testing.T) {
+	lat, lon := -7.78291, 110.36712
+	snapLat, snapLon := geo.Snap(lat, lon, anonymousFuzzMeters)
+	const eps = 1e-7
+
+	tests := []struct {
+		name string
+		box  geo.BoundingBox
+		want int
+	}{
+		// box sempit di sekitar lokasi asli tidak boleh menemukan laporan anonim
+		{"box di lokasi asli", geo.BoundingBox{MinLat: lat - eps, MinLon: lon - eps, MaxLat: lat + eps, MaxLon: lon + eps}, 0},
+		{"box di lokasi samaran", geo.BoundingBox{MinLat: snapLat - eps, MinLon: snapLon - eps, MaxLat: snapLat + eps, MaxLon: snapLon + eps}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			points := []reportPoint{{ID: 1, Latitude: lat, Longitude: lon, IsAnonymous: true}}
+			got := snapPoints(points, tt.box, 0)
+			if len(got) != tt.want {
+				t.Fatalf("len(snapPoints) = %d, want %d", len(got), tt.want)
+			}
+			if tt.want > 0 && (!near(got[0].Latitude, snapLat) || !near(got[0].Longitude, snapLon)) {
+				t.Errorf("koordinat = %v,%v, want koordinat samaran %v,%v", got[0].Latitude, got[0].Longitude, snapLat, snapLon)
+			}
+		})
+	}
+}
+
+func near(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
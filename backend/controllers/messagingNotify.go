@@ -0,0 +1,131 @@
+package controllers
+
+import (
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"project-backend/config"
+	"project-backend/mailer"
+	"project-backend/messaging"
+	"project-backend/models"
+	"project-backend/notification"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Nama status dalam bahasa Inggris untuk pesan WhatsApp/SMS
+var statusLabelsEN = map[string]string{
+	"Diajukan":   "Submitted",
+	"Diproses":   "In progress",
+	"Selesai":    "Resolved",
+	"Ditolak":    "Rejected",
+	"Dibatalkan": "Withdrawn",
+}
+
+// Kata kunci balasan pelapor untuk berhenti/mulai menerima pesan
+var (
+	optOutKeywords = map[string]bool{"STOP": true, "BERHENTI": true, "UNSUBSCRIBE": true}
+	optInKeywords  = map[string]bool{"START": true, "MULAI": true}
+)
+
+// statusMessageText menyusun pesan singkat perubahan status dalam bahasa lang
+func statusMessageText(lang string, report models.Report, note string) string {
+	if lang == mailer.LanguageEN {
+		status := statusLabelsEN[report.Status]
+		if status == "" {
+			status = report.Status
+		}
+		text := fmt.Sprintf("[%s] Your report %s \"%s\" is now: %s.", config.AppName, report.TrackingID, report.Title, status)
+		if note != "" {
+			text += " " + note
+		}
+		return text + "\nTrack: " + config.TrackingURL(report.TrackingID) + "\nReply STOP to unsubscribe."
+	}
+	text := fmt.Sprintf("[%s] Laporan %s \"%s\" kini berstatus: %s.", config.AppName, report.TrackingID, report.Title, report.Status)
+	if note != "" {
+		text += " " + note
+	}
+	return text + "\nPantau: " + config.TrackingURL(report.TrackingID) + "\nBalas STOP untuk berhenti."
+}
+
+// messageStatusChanged mengantrekan pesan WhatsApp/SMS perubahan status ke pelapor
+// yang sudah menyetujui notifikasi lewat nomor HP. Tanpa gateway tidak ada yang diantrekan.
+func messageStatusChanged(report models.Report, note, dedupKey string) error {
+	var owner models.User
+	if err := config.DB.Select("id", "is_active", "language", "phone", "messaging_opt_in", "messaging_channel").
+		First(&owner, report.UserID).Error; err != nil {
+		return nil
+	}
+	if !messaging.Enabled() || !owner.IsActive || owner.Phone == "" || !owner.MessagingOptIn ||
+		!notification.Enabled(config.DB, owner.ID, notification.TypeStatusChanged, notification.ChannelMessaging) {
+		return nil
+	}
+	channel := owner.MessagingChannel
+	if !messaging.IsValidChannel(channel) {
+		channel = messaging.ChannelWhatsApp
+	}
+
+	err := messaging.Enqueue(config.DB, owner.ID, dedupKey, messaging.Message{
+		To:      owner.Phone,
+		Channel: channel,
+		Text:    statusMessageText(owner.Language, report, note),
+	}, messaging.Limit{Max: config.MessagingRateLimit, Window: config.MessagingRateWindow})
+	if errors.Is(err, messaging.ErrRateLimited) {
+		log.Printf("messaging: pesan laporan %d ke user %d dilewati, batas per nomor terlampaui", report.ID, owner.ID)
+		return nil
+	}
+	return err
+}
+
+// POST /messaging/inbound  header X-Webhook-Token
+// body: {"from": "+628123456789", "text": "STOP"}
+// Dipanggil gateway saat pelapor membalas pesan. STOP/BERHENTI menonaktifkan dan
+// MULAI/START mengaktifkan kembali notifikasi WhatsApp/SMS untuk nomor tersebut.
+func ReceiveInboundMessage(c *gin.Context) {
+	if config.MessagingWebhookToken == "" {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"message": "Webhook pesan masuk belum dikonfigurasi"})
+		return
+	}
+	token := c.GetHeader("X-Webhook-Token")
+	if subtle.ConstantTimeCompare([]byte(token), []byte(config.MessagingWebhookToken)) != 1 {
+		c.JSON(http.StatusUnauthorized, gin.H{"message": "Token webhook tidak valid"})
+		return
+	}
+	var input struct {
+		From string `json:"from"`
+		Text string `json:"text"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid request body"})
+		return
+	}
+	phone, err := messaging.NormalizePhone(input.From)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Nomor pengirim tidak valid"})
+		return
+	}
+
+	keyword := strings.ToUpper(strings.TrimSpace(input.Text))
+	var updates map[string]interface{}
+	switch {
+	case optOutKeywords[keyword]:
+		updates = map[string]interface{}{"messaging_opt_in": false, "messaging_opt_in_at": nil}
+	case optInKeywords[keyword]:
+		updates = map[string]interface{}{"messaging_opt_in": true, "messaging_opt_in_at": time.Now()}
+	default:
+		// balasan lain tidak diproses
+		c.JSON(http.StatusOK, gin.H{"message": "Pesan diabaikan"})
+		return
+	}
+
+	res := config.DB.Model(&models.User{}).Where("phone = ?", phone).Updates(updates)
+	if res.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": "Gagal memperbarui persetujuan"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Persetujuan diperbarui", "updated": res.RowsAffected})
+}
@@ -0,0 +1,270 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"project-backend/config"
+	"project-backend/models"
+	"project-backend/notification"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Laporan yang melewati SLA lebih lama dari ini tidak diperiksa lagi (sudah diberitahukan
+// sebelumnya, atau server mati terlalu lama)
+const slaBreachLookback = 7 * 24 * time.Hour
+
+func reportLink(reportID uint) string {
+	return "/detail/" + strconv.FormatUint(uint64(reportID), 10)
+}
+
+// reportOfficerID mengembalikan admin pemilik kategori laporan (0 jika tidak ada)
+func reportOfficerID(categoryID *uint) uint {
+	if categoryID == nil {
+		return 0
+	}
+	var cat models.Category
+	if err := config.DB.Select("id", "user_id").First(&cat, *categoryID).Error; err != nil {
+		return 0
+	}
+	return cat.UserID
+}
+
+// reportParticipants adalah pelapor dan petugas laporan, penerima notifikasi kegiatan laporan
+func reportParticipants(report models.Report) []uint {
+	return []uint{report.UserID, reportOfficerID(report.CategoryID)}
+}
+
+// Fungsi notify* di bawah menerima dedupKey (boleh kosong) agar notifikasi dari event
+// outbox yang diproses ulang tidak dibuat dua kali
+
+func notifyStatusChanged(report models.Report, actorID uint, deskripsi, dedupKey string) error {
+	return notification.Publish(notification.Event{
+		Type:       notification.TypeStatusChanged,
+		ReportID:   report.ID,
+		ActorID:    actorID,
+		Title:      fmt.Sprintf("Status laporan %s menjadi %s", report.TrackingID, report.Status),
+		Body:       deskripsi,
+		Link:       reportLink(report.ID),
+		Recipients: reportParticipants(report),
+		DedupKey:   dedupKey,
+	})
+}
+
+// notifyAssigned memberi tahu petugas bahwa laporan masuk ke kategorinya
+func notifyAssigned(report models.Report, actorID uint, dedupKey string) error {
+	officer := reportOfficerID(report.CategoryID)
+	if officer == 0 {
+		return nil
+	}
+	return notification.Publish(notification.Event{
+		Type:       notification.TypeAssigned,
+		ReportID:   report.ID,
+		ActorID:    actorID,
+		Title:      "Laporan baru ditugaskan kepada Anda: " + report.TrackingID,
+		Body:       report.Title,
+		Link:       reportLink(report.ID),
+		Recipients: []uint{officer},
+		DedupKey:   dedupKey,
+	})
+}
+
+func notifyReportActivity(typ string, report models.Report, actorID uint, title, body, dedupKey string) error {
+	return notification.Publish(notification.Event{
+		Type:       typ,
+		ReportID:   report.ID,
+		ActorID:    actorID,
+		Title:      fmt.Sprintf(title, report.TrackingID),
+		Body:       body,
+		Link:       reportLink(report.ID),
+		Recipients: reportParticipants(report),
+		DedupKey:   dedupKey,
+	})
+}
+
+// CheckSLABreaches memberi tahu petugas (atau superadmin jika laporan tidak punya petugas)
+// tentang laporan yang baru melewati batas waktu penanganan. Dipanggil berkala oleh
+// scheduler; setiap pelanggaran hanya diberitahukan sekali per laporan dan tahap SLA
+// (status) lewat DedupKey, walaupun updated_at laporan berubah karena dukungan atau komentar.
+func CheckSLABreaches(ctx context.Context, now time.Time) error {
+	db := config.DB.WithContext(ctx)
+	var superadmins []uint
+	if err := db.Model(&models.User{}).Where("role = ? AND is_active = ?", "superadmin", true).Pluck("id", &superadmins).Error; err != nil {
+		return err
+	}
+
+	cond, args := overdueCondition(now)
+	var reports []models.Report
+	return db.Model(&models.Report{}).
+		Select("id", "tracking_id", "title", "status", "category_id", "updated_at").
+		Where(cond, args...).
+		Where("reports.updated_at >= ?", now.Add(-slaBreachLookback-slaDurations["Diproses"])).
+		FindInBatches(&reports, 500, func(tx *gorm.DB, batch int) error {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			for _, r := range reports {
+				recipients := superadmins
+				if officer := reportOfficerID(r.CategoryID); officer != 0 {
+					recipients = []uint{officer}
+				}
+				days := int(now.Sub(r.UpdatedAt).Hours() / 24)
+				notification.Publish(notification.Event{
+					Type:       notification.TypeSLABreach,
+					ReportID:   r.ID,
+					Title:      "Laporan " + r.TrackingID + " melewati batas waktu penanganan",
+					Body:       fmt.Sprintf("%s: status %s sejak %d hari", r.Title, r.Status, days),
+					Link:       reportLink(r.ID),
+					Recipients: recipients,
+					DedupKey:   slaDedupKey(r.ID, r.Status),
+				})
+			}
+			return nil
+		}).Error
+}
+
+// slaDedupKey mengunci notifikasi pelanggaran SLA pada laporan dan tahapnya saja
+func slaDedupKey(reportID uint, stage string) string {
+	return fmt.Sprintf("sla:%d:%s", reportID, stage)
+}
+
+// GET /notifications?unread=true&page=1&limit=20 -> kotak masuk user login
+func GetNotifications(c *gin.Context) {
+	meta := ListMeta{Page: 1, Limit: defaultPageLimit}
+	if l, err := strconv.Atoi(c.Query("limit")); err == nil && l > 0 {
+		meta.Limit = l
+	}
+	if meta.Limit > maxPageLimit {
+		meta.Limit = maxPageLimit
+	}
+	if p, err := strconv.Atoi(c.Query("page")); err == nil && p > 1 {
+		meta.Page = p
+	}
+
+	db := config.DB.Model(&models.Notification{}).Where("user_id = ?", c.GetUint("userID"))
+	if v, ok := parseBoolQuery(c.Query("unread")); ok && v {
+		db = db.Where("read_at IS NULL")
+	}
+	if err := db.Session(&gorm.Session{}).Count(&meta.Total).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": "Gagal mengambil notifikasi"})
+		return
+	}
+
+	var notifications []models.Notification
+	if err := db.Order("created_at DESC, id DESC").
+		Offset((meta.Page - 1) * meta.Limit).Limit(meta.Limit).
+		Find(&notifications).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": "Gagal mengambil notifikasi"})
+		return
+	}
+	meta.HasMore = int64(meta.Page*meta.Limit) < meta.Total
+
+	c.JSON(http.StatusOK, gin.H{"data": notifications, "meta": meta})
+}
+
+// GET /notifications/unread-count
+func GetUnreadNotificationCount(c *gin.Context) {
+	var count int64
+	config.DB.Model(&models.Notification{}).
+		Where("user_id = ? AND read_at IS NULL", c.GetUint("userID")).
+		Count(&count)
+	c.JSON(http.StatusOK, gin.H{"unread": count})
+}
+
+// POST /notifications/:id/read
+func MarkNotificationRead(c *gin.Context) {
+	var n models.Notification
+	if err := config.DB.Where("id = ? AND user_id = ?", c.Param("id"), c.GetUint("userID")).First(&n).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"message": "Notifikasi tidak ditemukan"})
+		return
+	}
+	if n.ReadAt == nil {
+		now := time.Now()
+		n.ReadAt = &now
+		if err := config.DB.Model(&n).Update("read_at", now).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"message": "Gagal memperbarui notifikasi"})
+			return
+		}
+	}
+	c.JSON(http.StatusOK, gin.H{"data": n})
+}
+
+// POST /notifications/read-all
+func MarkAllNotificationsRead(c *gin.Context) {
+	res := config.DB.Model(&models.Notification{}).
+		Where("user_id = ? AND read_at IS NULL", c.GetUint("userID")).
+		Update("read_at", time.Now())
+	if res.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": "Gagal memperbarui notifikasi"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Semua notifikasi ditandai sudah dibaca", "updated": res.RowsAffected})
+}
+
+// notificationPreferences mengembalikan preferensi user untuk semua jenis dan kanal
+func notificationPreferences(userID uint) []gin.H {
+	var saved []models.NotificationPreference
+	config.DB.Where("user_id = ?", userID).Find(&saved)
+	enabled := map[string]bool{}
+	for _, p := range saved {
+		enabled[p.Type+"/"+p.Channel] = p.Enabled
+	}
+
+	result := make([]gin.H, 0, len(notification.Types))
+	for _, t := range notification.Types {
+		channels := gin.H{}
+		for _, ch := range notification.Channels {
+			v, ok := enabled[t.Type+"/"+ch]
+			channels[ch] = !ok || v
+		}
+		result = append(result, gin.H{"type": t.Type, "label": t.Label, "channels": channels})
+	}
+	return result
+}
+
+// GET /notifications/preferences
+func GetNotificationPreferences(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"data": notificationPreferences(c.GetUint("userID"))})
+}
+
+// PUT /notifications/preferences
+// body: {"preferences": [{"type": "comment", "channel": "in_app", "enabled": false}]}
+func UpdateNotificationPreferences(c *gin.Context) {
+	var body struct {
+		Preferences []models.NotificationPreference `json:"preferences"`
+	}
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid request body"})
+		return
+	}
+	userID := c.GetUint("userID")
+	for i := range body.Preferences {
+		p := &body.Preferences[i]
+		if p.Channel == "" {
+			p.Channel = notification.ChannelInApp
+		}
+		if !notification.IsValidType(p.Type) || !notification.IsValidChannel(p.Channel) {
+			c.JSON(http.StatusBadRequest, gin.H{"message": "Jenis atau kanal notifikasi tidak dikenal: " + p.Type + "/" + p.Channel})
+			return
+		}
+		p.ID = 0
+		p.UserID = userID
+	}
+
+	if len(body.Preferences) > 0 {
+		err := config.DB.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "user_id"}, {Name: "type"}, {Name: "channel"}},
+			DoUpdates: clause.AssignmentColumns([]string{"enabled"}),
+		}).Create(&body.Preferences).Error
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"message": "Gagal menyimpan preferensi notifikasi"})
+			return
+		}
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Preferensi notifikasi disimpan", "data": notificationPreferences(userID)})
+}
@@ -0,0 +1,171 @@
+package controllers
+
+import (
+	"errors"
+	"project-backend/config"
+	"project-backend/models"
+	"project-backend/notification"
+	"project-backend/outbox"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// Nama consumer outbox; juga dipakai sebagai bagian DedupKey
+const (
+	consumerNotification = "notification"
+	consumerEmail        = "email"
+	consumerMessaging    = "messaging"
+	consumerWebhook      = "webhook"
+)
+
+// reportEventData adalah payload event outbox laporan. Jenis event sama dengan
+// event real-time (report.created, report.status_changed, comment.created, followup.created),
+// ditambah report.assigned saat kategori laporan (dan petugasnya) diganti admin.
+type reportEventData struct {
+	ActorID    uint      `json:"actor_id"`
+	ActorRole  string    `json:"actor_role,omitempty"`
+	OldStatus  string    `json:"old_status,omitempty"`
+	Status     string    `json:"status,omitempty"`
+	CategoryID *uint     `json:"category_id,omitempty"`
+	Deskripsi  string    `json:"deskripsi,omitempty"`
+	CommentID  uint      `json:"comment_id,omitempty"`
+	FollowUpID uint      `json:"followup_id,omitempty"`
+	Text       string    `json:"text,omitempty"`
+	PhotoURL   string    `json:"photo_url,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// writeReportEvent menulis event laporan ke outbox di dalam transaksi tx
+func writeReportEvent(tx *gorm.DB, typ string, reportID uint, data reportEventData) error {
+	if data.CreatedAt.IsZero() {
+		data.CreatedAt = time.Now()
+	}
+	return outbox.Write(tx, typ, reportID, data)
+}
+
+// RegisterOutboxConsumers mendaftarkan consumer event laporan. Dipanggil saat start-up.
+func RegisterOutboxConsumers() {
+	outbox.Register(consumerNotification, consumeNotification)
+	outbox.Register(consumerEmail, consumeEmail)
+	outbox.Register(consumerMessaging, consumeMessaging)
+	outbox.Register(consumerWebhook, consumeWebhook)
+}
+
+// loadEventReport memuat laporan event beserta payloadnya. ok bernilai false jika
+// laporan sudah dihapus permanen (event dilewati).
+func loadEventReport(ev outbox.Event) (report models.Report, data reportEventData, ok bool, err error) {
+	if err = ev.Decode(&data); err != nil {
+		return report, data, false, err
+	}
+	if err = config.DB.First(&report, ev.ReportID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return report, data, false, nil
+		}
+		return report, data, false, err
+	}
+	// status dan kategori saat event terjadi, bukan yang terkini
+	if data.Status != "" {
+		report.Status = data.Status
+	}
+	if data.CategoryID != nil {
+		report.CategoryID = data.CategoryID
+	}
+	return report, data, true, nil
+}
+
+func consumeNotification(ev outbox.Event) error {
+	report, data, ok, err := loadEventReport(ev)
+	if !ok {
+		return err
+	}
+	key := ev.DedupKey(consumerNotification)
+	switch ev.Type {
+	case eventReportCreated, eventReportAssigned:
+		return notifyAssigned(report, data.ActorID, key)
+	case eventReportStatusChanged:
+		return notifyStatusChanged(report, data.ActorID, data.Deskripsi, key)
+	case eventCommentCreated:
+		return notifyReportActivity(notification.TypeComment, report, data.ActorID, "Komentar baru pada laporan %s", data.Text, key)
+	case eventFollowUpCreated:
+		return notifyReportActivity(notification.TypeFollowUp, report, data.ActorID, "Tindak lanjut baru pada laporan %s", data.Text, key)
+	}
+	return nil
+}
+
+func consumeEmail(ev outbox.Event) error {
+	report, data, ok, err := loadEventReport(ev)
+	if !ok {
+		return err
+	}
+	key := ev.DedupKey(consumerEmail)
+	switch ev.Type {
+	case eventReportCreated:
+		return emailReportCreated(report, key)
+	case eventReportStatusChanged:
+		// perubahan oleh pelapor sendiri (buka kembali, batal) tidak perlu diemailkan
+		if data.ActorID == report.UserID {
+			return nil
+		}
+		return emailStatusChanged(report, data.Deskripsi, key)
+	case eventCommentCreated:
+		if !isAdminRole(data.ActorRole) {
+			return nil
+		}
+		return emailAdminReplied(notification.TypeComment, report, data.ActorID, data.Text, key)
+	case eventFollowUpCreated:
+		return emailAdminReplied(notification.TypeFollowUp, report, data.ActorID, data.Text, key)
+	}
+	return nil
+}
+
+func consumeMessaging(ev outbox.Event) error {
+	report, data, ok, err := loadEventReport(ev)
+	if !ok {
+		return err
+	}
+	key := ev.DedupKey(consumerMessaging)
+	switch ev.Type {
+	case eventReportCreated:
+		return messageStatusChanged(report, "", key)
+	case eventReportStatusChanged:
+		if data.ActorID == report.UserID {
+			return nil
+		}
+		return messageStatusChanged(report, data.Deskripsi, key)
+	}
+	return nil
+}
+
+func consumeWebhook(ev outbox.Event) error {
+	report, data, ok, err := loadEventReport(ev)
+	if !ok {
+		return err
+	}
+	switch ev.Type {
+	case eventReportCreated:
+		return webhookReportCreated(ev, report)
+	case eventReportStatusChanged:
+		return webhookReportStatusChanged(ev, report, data.OldStatus, data.Deskripsi)
+	case eventCommentCreated:
+		return webhookReportActivity(ev, report, gin.H{
+			"comment_id":  data.CommentID,
+			"author_role": data.ActorRole,
+			"text":        data.Text,
+			"created_at":  data.CreatedAt,
+		})
+	case eventFollowUpCreated:
+		return webhookReportActivity(ev, report, gin.H{
+			"followup_id": data.FollowUpID,
+			"deskripsi":   data.Text,
+			"photo_url":   data.PhotoURL,
+			"created_at":  data.CreatedAt,
+		})
+	}
+	return nil
+}
+
+func isAdminRole(role string) bool {
+	return role == "admin" || role == "superadmin" || role == "kategori_admin"
+}
@@ -0,0 +1,285 @@
+package controllers
+
+import (
+	"bytes"
+	"net/http"
+	"project-backend/config"
+	"project-backend/models"
+	"project-backend/pdf"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// Jumlah wilayah teratas yang dicantumkan di rekap bulanan
+const recapTopRegions = 10
+
+// buildReceiptData menyusun isi bukti pengaduan dari laporan beserta User, Category dan BuktiFotos
+func buildReceiptData(report models.Report) pdf.ReceiptData {
+	data := pdf.ReceiptData{
+		AppName:     config.AppName,
+		TrackingID:  report.TrackingID,
+		TrackingURL: config.TrackingURL(report.TrackingID),
+		Title:       report.Title,
+		Category:    report.Category.Name,
+		Status:      report.Status,
+		Wilayah:     report.Wilayah,
+		KodeWilayah: report.KodeWilayah,
+		Lokasi:      report.Lokasi,
+		Latitude:    report.Latitude,
+		Longitude:   report.Longitude,
+		Description: report.Description,
+		Reporter:    report.User.Name,
+		CreatedAt:   report.CreatedAt,
+		PrintedAt:   time.Now(),
+	}
+	if report.IsAnonymous {
+		data.Reporter = "Anonim"
+	}
+	for _, f := range report.BuktiFotos {
+		data.Photos = append(data.Photos, f.PhotoURL)
+	}
+	return data
+}
+
+// GET /reports/:id/receipt -> PDF "Bukti Pengaduan" untuk pelapor (atau admin)
+func GetReportReceipt(c *gin.Context) {
+	var report models.Report
+	if err := config.DB.Preload("User").Preload("Category").Preload("BuktiFotos").
+		First(&report, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"message": "Laporan tidak ditemukan"})
+		return
+	}
+
+	role := c.GetString("role")
+	userID := c.GetUint("userID")
+	isAdmin := role == "admin" || role == "superadmin" || role == "kategori_admin"
+	if report.UserID != userID {
+		if !isAdmin {
+			c.JSON(http.StatusForbidden, gin.H{"message": "Akses ditolak"})
+			return
+		}
+		// admin hanya boleh mencetak bukti laporan dalam lingkupnya
+		var inScope int64
+		if err := config.DB.Model(&models.Report{}).Scopes(adminScope(role, userID)).
+			Where("reports.id = ?", report.ID).Count(&inScope).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"message": "Gagal memeriksa akses laporan"})
+			return
+		}
+		if inScope == 0 {
+			c.JSON(http.StatusForbidden, gin.H{"message": "Akses ditolak"})
+			return
+		}
+	}
+
+	// dokumen dibuat di buffer agar kegagalan masih bisa dibalas sebagai JSON
+	var buf bytes.Buffer
+	if err := pdf.Receipt(&buf, buildReceiptData(report)); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": "Gagal membuat bukti pengaduan"})
+		return
+	}
+	sendPDF(c, "bukti-pengaduan-"+report.TrackingID+".pdf", buf.Bytes())
+}
+
+// buildRecap menghitung isi rekap untuk laporan dalam scope yang dibuat pada [start, end).
+// Periode pembanding adalah bulan sebelumnya untuk rekap satu bulan penuh, selain itu
+// rentang dengan panjang sama tepat sebelum start.
+func buildRecap(scope func(*gorm.DB) *gorm.DB, start, end time.Time, scopeLabel string) (pdf.RecapData, error) {
+	data := pdf.RecapData{
+		AppName:   config.AppName,
+		Period:    pdf.PeriodName(start, end),
+		Scope:     scopeLabel,
+		PrintedAt: time.Now(),
+	}
+	prevStart := start.Add(-end.Sub(start))
+	if start.Day() == 1 && start.AddDate(0, 1, 0).Equal(end) {
+		prevStart = start.AddDate(0, -1, 0)
+	}
+
+	inPeriod := func(from, to time.Time) *gorm.DB {
+		return config.DB.Model(&models.Report{}).Scopes(scope).
+			Where("reports.created_at >= ? AND reports.created_at < ?", from, to)
+	}
+
+	// status
+	var byStatus []statusCount
+	if err := inPeriod(start, end).Select("reports.status, COUNT(*) AS total").Group("reports.status").Scan(&byStatus).Error; err != nil {
+		return data, err
+	}
+	counts := map[string]int64{}
+	for _, s := range byStatus {
+		counts[s.Status] = s.Total
+		data.Total += s.Total
+	}
+	for _, s := range []string{"Diajukan", "Diproses", "Selesai", "Ditolak", "Dibatalkan"} {
+		data.ByStatus = append(data.ByStatus, pdf.LabelValue{Label: s, Value: float64(counts[s])})
+	}
+	data.Resolved = counts["Selesai"]
+	if err := inPeriod(prevStart, start).Count(&data.PrevTotal).Error; err != nil {
+		return data, err
+	}
+
+	// kepuasan
+	var rating struct {
+		Average float64
+		Count   int64
+	}
+	inPeriod(start, end).Joins("JOIN report_ratings ON report_ratings.report_id = reports.id").
+		Select("COALESCE(AVG(report_ratings.stars), 0) AS average, COUNT(*) AS count").Scan(&rating)
+	data.Satisfaction, data.RatingCount = rating.Average, rating.Count
+
+	// per wilayah (nama kabupaten/kota)
+	var regions []struct {
+		Wilayah string
+		Total   int64
+	}
+	if err := inPeriod(start, end).Select("reports.wilayah, COUNT(*) AS total").
+		Where("reports.wilayah <> ''").
+		Group("reports.wilayah").Order("total DESC").Limit(recapTopRegions).
+		Scan(&regions).Error; err != nil {
+		return data, err
+	}
+	for _, r := range regions {
+		data.ByRegion = append(data.ByRegion, pdf.LabelValue{Label: r.Wilayah, Value: float64(r.Total)})
+	}
+
+	// per hari, per kategori dan durasi penanganan dari Riwayat
+	days := int(end.Sub(start).Hours()/24 + 0.5)
+	if days < 1 {
+		days = 1
+	}
+	daily := make([]int64, days)
+	overall := &metricsGroup{}
+	categories := map[string]*metricsGroup{}
+	query := inPeriod(start, end).
+		Select("reports.id, reports.created_at, reports.reopen_count, reports.category_id, categories.name AS kategori").
+		Joins("LEFT JOIN categories ON categories.id = reports.category_id")
+	err := reportBatches(query, 500, metricsRowID, func(rows []metricsRow) error {
+		ids := make([]uint, len(rows))
+		for i, r := range rows {
+			ids[i] = r.ID
+		}
+		var riwayat []models.Riwayat
+		if err := config.DB.Where("report_id IN ?", ids).Order("tanggal, id").Find(&riwayat).Error; err != nil {
+			return err
+		}
+		byReport := map[uint][]models.Riwayat{}
+		for _, r := range riwayat {
+			byReport[r.ReportID] = append(byReport[r.ReportID], r)
+		}
+		for _, r := range rows {
+			if i := int(r.CreatedAt.Sub(start).Hours() / 24); i >= 0 && i < days {
+				daily[i]++
+			}
+			tl := buildTimeline(r.CreatedAt, r.ReopenCount, byReport[r.ID])
+			overall.add(tl)
+			label := r.Kategori
+			if label == "" {
+				label = "Tanpa kategori"
+			}
+			g, ok := categories[label]
+			if !ok {
+				g = &metricsGroup{Label: label}
+				categories[label] = g
+			}
+			g.add(tl)
+		}
+		return nil
+	})
+	if err != nil {
+		return data, err
+	}
+
+	for i, n := range daily {
+		label := start.AddDate(0, 0, i).Format("02/01")
+		if days > 7 {
+			label = twoDigits(start.AddDate(0, 0, i).Day())
+		}
+		data.Daily = append(data.Daily, pdf.LabelValue{Label: label, Value: float64(n)})
+	}
+	first, resolve := summarizeDurations(overall.firstResponse), summarizeDurations(overall.toResolve)
+	data.FirstResponseMedian = first.Median
+	data.ResolveMedian, data.ResolveP90 = resolve.Median, resolve.P90
+	for _, g := range categories {
+		data.ByCategory = append(data.ByCategory, pdf.CategoryRecap{
+			Name:     g.Label,
+			Total:    int64(g.reports),
+			Resolved: int64(g.resolved),
+			Median:   summarizeDurations(g.toResolve).Median,
+		})
+	}
+	sort.Slice(data.ByCategory, func(i, j int) bool {
+		if data.ByCategory[i].Total != data.ByCategory[j].Total {
+			return data.ByCategory[i].Total > data.ByCategory[j].Total
+		}
+		return data.ByCategory[i].Name < data.ByCategory[j].Name
+	})
+	return data, nil
+}
+
+// adminScopeLabel menjelaskan cakupan adminScope untuk dicetak di dokumen
+func adminScopeLabel(role string, userID uint) string {
+	if role != "admin" && role != "kategori_admin" {
+		return "Semua laporan"
+	}
+	var categories, regions []string
+	config.DB.Model(&models.Category{}).Where("user_id = ?", userID).Order("name").Pluck("name", &categories)
+	config.DB.Table("admin_regions").
+		Joins("JOIN regions ON regions.id = admin_regions.region_id").
+		Where("admin_regions.user_id = ?", userID).
+		Order("regions.code").
+		Pluck("regions.name", &regions)
+
+	var parts []string
+	if len(categories) > 0 {
+		parts = append(parts, "Kategori "+strings.Join(categories, ", "))
+	}
+	if len(regions) > 0 {
+		parts = append(parts, "Wilayah "+strings.Join(regions, ", "))
+	}
+	if len(parts) == 0 {
+		return "Semua laporan"
+	}
+	return strings.Join(parts, "; ")
+}
+
+// GET /admin/recap/monthly?month=2025-08 -> PDF rekap bulanan (default bulan lalu),
+// dibatasi pada kategori/wilayah tugas admin
+func GetMonthlyRecap(c *gin.Context) {
+	month := time.Now().AddDate(0, -1, 0)
+	if v := c.Query("month"); v != "" {
+		m, err := time.ParseInLocation("2006-01", v, time.Local)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"message": "Format month harus YYYY-MM"})
+			return
+		}
+		month = m
+	}
+
+	start := time.Date(month.Year(), month.Month(), 1, 0, 0, 0, 0, time.Local)
+	role, adminID := c.GetString("role"), c.GetUint("userID")
+	data, err := buildRecap(adminScope(role, adminID), start, start.AddDate(0, 1, 0), adminScopeLabel(role, adminID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": "Gagal menghitung rekap bulanan"})
+		return
+	}
+	var buf bytes.Buffer
+	if err := pdf.Recap(&buf, data); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": "Gagal membuat rekap bulanan"})
+		return
+	}
+	sendPDF(c, "rekap-"+start.Format("2006-01")+".pdf", buf.Bytes())
+}
+
+// sendPDF mengirim dokumen PDF; ?download=1 memaksa unduhan alih-alih tampil di browser
+func sendPDF(c *gin.Context, filename string, body []byte) {
+	disposition := "inline"
+	if v, _ := parseBoolQuery(c.Query("download")); v {
+		disposition = "attachment"
+	}
+	c.Header("Content-Disposition", disposition+`; filename="`+filename+`"`)
+	c.Data(http.StatusOK, "application/pdf", body)
+}
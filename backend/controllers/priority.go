@@ -0,0 +1,271 @@
+package controllers
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"project-backend/audit"
+	"project-backend/config"
+	"project-backend/models"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// Tingkat prioritas dari rendah ke tinggi
+var priorityLevels = []string{"rendah", "sedang", "tinggi", "darurat"}
+
+// Kata kunci yang menandakan laporan mendesak
+var priorityKeywords = []string{"darurat", "kebakaran", "banjir", "longsor", "kecelakaan", "korban", "bahaya", "roboh"}
+
+// Batas waktu penanganan (SLA) untuk status yang masih terbuka
+var slaDurations = map[string]time.Duration{
+	"Diajukan": 2 * 24 * time.Hour,  // harus direspon maksimal 2 hari
+	"Diproses": 14 * 24 * time.Hour, // harus selesai maksimal 14 hari
+}
+
+// overdueCondition mencocokkan laporan terbuka yang statusnya sudah melewati SLA pada now.
+// Acuannya updated_at, sama seperti perhitungan prioritas.
+func overdueCondition(now time.Time) (string, []interface{}) {
+	conds := make([]string, 0, len(slaDurations))
+	args := make([]interface{}, 0, len(slaDurations)*2)
+	for status, sla := range slaDurations {
+		conds = append(conds, "(reports.status = ? AND reports.updated_at < ?)")
+		args = append(args, status, now.Add(-sla))
+	}
+	return "(" + strings.Join(conds, " OR ") + ")", args
+}
+
+// Urutan tingkat prioritas untuk ORDER BY (darurat paling atas), harus sama dengan priorityRank
+const priorityRankSQL = "CASE reports.priority WHEN 'darurat' THEN 4 WHEN 'tinggi' THEN 3 WHEN 'sedang' THEN 2 ELSE 1 END"
+
+// priorityRank adalah nilai priorityRankSQL untuk satu tingkat prioritas
+func priorityRank(level string) int {
+	for i, l := range priorityLevels {
+		if l == level {
+			return i + 1
+		}
+	}
+	return 1
+}
+
+func isValidPriority(p string) bool {
+	for _, l := range priorityLevels {
+		if l == p {
+			return true
+		}
+	}
+	return false
+}
+
+// computePriorityScore menghitung skor 0-100 dari bobot kategori, jumlah dukungan,
+// umur laporan, kedekatan dengan batas SLA dan kata kunci darurat.
+func computePriorityScore(report models.Report, severity int, now time.Time) int {
+	if severity < 1 || severity > 5 {
+		severity = 3
+	}
+	score := severity * 6 // maks 30
+
+	endorse := report.EndorsementCount * 2
+	if endorse > 20 {
+		endorse = 20
+	}
+	score += endorse
+
+	if sla, open := slaDurations[report.Status]; open {
+		age := now.Sub(report.CreatedAt)
+		days := int(age.Hours() / 24)
+		if days > 15 {
+			days = 15
+		}
+		score += days
+
+		// waktu sejak status terakhir berubah dibandingkan SLA status tersebut
+		since := now.Sub(report.UpdatedAt)
+		if report.UpdatedAt.IsZero() {
+			since = age
+		}
+		ratio := float64(since) / float64(sla)
+		if ratio > 1 {
+			ratio = 1
+		}
+		score += int(ratio * 15)
+	}
+
+	text := strings.ToLower(report.Title + " " + report.Description)
+	keywordScore := 0
+	for _, kw := range priorityKeywords {
+		if strings.Contains(text, kw) {
+			keywordScore += 10
+		}
+	}
+	if keywordScore > 20 {
+		keywordScore = 20
+	}
+	score += keywordScore
+
+	if score > 100 {
+		score = 100
+	}
+	return score
+}
+
+func priorityLevelFromScore(score int) string {
+	switch {
+	case score >= 75:
+		return "darurat"
+	case score >= 50:
+		return "tinggi"
+	case score >= 25:
+		return "sedang"
+	default:
+		return "rendah"
+	}
+}
+
+// refreshPriority menghitung ulang skor prioritas laporan dan menyimpan hasilnya.
+// Jika tingkat prioritas efektif berubah, perubahan dicatat di Riwayat.
+func refreshPriority(tx *gorm.DB, report *models.Report, alasan string) error {
+	severity := 3
+	if report.CategoryID != nil {
+		var cat models.Category
+		if err := tx.Select("id", "severity").First(&cat, *report.CategoryID).Error; err == nil {
+			severity = cat.Severity
+		}
+	}
+
+	now := time.Now()
+	score := computePriorityScore(*report, severity, now)
+	level := priorityLevelFromScore(score)
+	if report.PriorityOverride != nil && isValidPriority(*report.PriorityOverride) {
+		level = *report.PriorityOverride
+	}
+
+	oldLevel := report.Priority
+	if oldLevel == level && report.PriorityScore == score {
+		return nil
+	}
+
+	updates := map[string]interface{}{"priority_score": score}
+	if oldLevel != level {
+		updates["priority"] = level
+		updates["priority_updated_at"] = now
+	}
+	// UpdateColumns agar updated_at tidak berubah (dipakai sebagai acuan SLA)
+	if err := tx.Model(&models.Report{}).Where("id = ?", report.ID).UpdateColumns(updates).Error; err != nil {
+		return err
+	}
+	report.PriorityScore = score
+
+	if oldLevel == level {
+		return nil
+	}
+	report.Priority = level
+	report.PriorityUpdatedAt = &now
+
+	// laporan baru belum punya prioritas, tidak ada perubahan yang perlu dicatat
+	if oldLevel == "" {
+		return nil
+	}
+	deskripsi := fmt.Sprintf("Prioritas laporan diubah dari %s menjadi %s", oldLevel, level)
+	if alasan != "" {
+		deskripsi += ": " + alasan
+	}
+	return tx.Create(&models.Riwayat{
+		ReportID:  report.ID,
+		Status:    report.Status,
+		Tanggal:   now,
+		Deskripsi: deskripsi,
+	}).Error
+}
+
+// updatePriority menghitung ulang prioritas setelah perubahan laporan tersimpan.
+// Kegagalannya tidak membatalkan perubahan tersebut, cukup dicatat; skor akan
+// diperbaiki saat laporan berubah lagi atau saat admin menghitung ulang prioritas.
+func updatePriority(report *models.Report, alasan string) {
+	if err := refreshPriority(config.DB, report, alasan); err != nil {
+		log.Printf("prioritas: gagal menghitung ulang laporan %d: %v", report.ID, err)
+	}
+}
+
+// RecalculateOpenPriorities menghitung ulang prioritas semua laporan yang belum selesai,
+// karena umur laporan dan kedekatan SLA berubah seiring waktu.
+func RecalculateOpenPriorities() (int, error) {
+	var reports []models.Report
+	if err := config.DB.Where("status IN ?", []string{"Diajukan", "Diproses"}).Find(&reports).Error; err != nil {
+		return 0, err
+	}
+	for i := range reports {
+		if err := refreshPriority(config.DB, &reports[i], "dihitung ulang otomatis"); err != nil {
+			return i, err
+		}
+	}
+	return len(reports), nil
+}
+
+// PATCH /reports/admin/:id/priority -> admin menetapkan atau menghapus prioritas manual
+func SetReportPriority(c *gin.Context) {
+	var report models.Report
+	if err := config.DB.First(&report, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"message": "Laporan tidak ditemukan"})
+		return
+	}
+
+	var input struct {
+		Priority string `json:"priority"` // kosong = kembali ke skor otomatis
+		Alasan   string `json:"alasan"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid input"})
+		return
+	}
+
+	input.Priority = strings.ToLower(strings.TrimSpace(input.Priority))
+	if input.Priority != "" && !isValidPriority(input.Priority) {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Prioritas harus salah satu dari: rendah, sedang, tinggi, darurat"})
+		return
+	}
+
+	audit.SetBefore(c, gin.H{"priority": report.Priority, "priority_override": report.PriorityOverride})
+
+	var override *string
+	if input.Priority != "" {
+		override = &input.Priority
+	}
+
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.Report{}).Where("id = ?", report.ID).
+			UpdateColumn("priority_override", override).Error; err != nil {
+			return err
+		}
+		report.PriorityOverride = override
+
+		alasan := input.Alasan
+		if alasan == "" && override == nil {
+			alasan = "kembali ke prioritas otomatis"
+		} else if alasan == "" {
+			alasan = "ditetapkan manual oleh admin"
+		}
+		return refreshPriority(tx, &report, alasan)
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": "Gagal memperbarui prioritas"})
+		return
+	}
+
+	audit.SetAfter(c, gin.H{"priority": report.Priority, "priority_override": report.PriorityOverride, "alasan": input.Alasan})
+
+	c.JSON(http.StatusOK, gin.H{"message": "Prioritas diperbarui", "data": report})
+}
+
+// POST /reports/admin/priority/recalculate -> hitung ulang prioritas laporan terbuka
+func RecalculatePriorities(c *gin.Context) {
+	total, err := RecalculateOpenPriorities()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": "Gagal menghitung ulang prioritas", "error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Prioritas dihitung ulang", "total": total})
+}
@@ -0,0 +1,199 @@
+package controllers
+
+import (
+	"project-backend/geo"
+	"project-backend/models"
+	"time"
+)
+
+// publicUser adalah identitas pelapor yang boleh tampil di endpoint publik
+type publicUser struct {
+	Name string `json:"name"`
+}
+
+// publicReportUser mengembalikan nama pelapor, atau "Anonim" untuk laporan anonim
+func publicReportUser(r models.Report) publicUser {
+	if r.IsAnonymous {
+		return publicUser{Name: "Anonim"}
+	}
+	return publicUser{Name: r.User.Name}
+}
+
+// publicReportView adalah laporan lengkap untuk endpoint publik dan daftar yang bisa
+// dilihat semua user. Field ditulis satu per satu agar kolom baru di models.Report tidak
+// ikut terbuka: tanpa user_id, kode wilayah, geohash maupun penilaian, data pelapor,
+// pemberi komentar dan petugas hanya berupa nama, dan koordinatnya disamarkan seperti
+// publicReport.
+type publicReportView struct {
+	ID               uint             `json:"id"`
+	TrackingID       string           `json:"tracking_id"`
+	IsAnonymous      bool             `json:"is_anonymous"`
+	Title            string           `json:"title"`
+	Description      string           `json:"description"`
+	Wilayah          string           `json:"wilayah"`
+	Lokasi           string           `json:"lokasi"`
+	Latitude         float64          `json:"latitude"`
+	Longitude        float64          `json:"longitude"`
+	Fuzzed           bool             `json:"fuzzed"`
+	Status           string           `json:"status"`
+	Priority         string           `json:"priority"`
+	CategoryID       *uint            `json:"category_id"`
+	Category         publicCategory   `json:"category"`
+	EndorsementCount int              `json:"endorsement_count"`
+	ReopenCount      int              `json:"reopen_count"`
+	ResolvedAt       *time.Time       `json:"resolved_at"`
+	CreatedAt        time.Time        `json:"created_at"`
+	UpdatedAt        time.Time        `json:"updated_at"`
+	User             publicUser       `json:"user"`
+	Riwayat          []models.Riwayat `json:"riwayat"`
+	Comments         []publicComment  `json:"comments"`
+	FollowUps        []publicFollowUp `json:"followups"`
+	BuktiFotos       []publicPhoto    `json:"bukti_fotos"`
+}
+
+type publicCategory struct {
+	ID   uint   `json:"id"`
+	Name string `json:"name"`
+}
+
+type publicPhoto struct {
+	ID           uint      `json:"id"`
+	PhotoURL     string    `json:"photo_url"`
+	ThumbnailURL string    `json:"thumbnail_url"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+type publicComment struct {
+	ID        uint       `json:"id"`
+	ReportID  uint       `json:"report_id"`
+	Text      string     `json:"text"`
+	CreatedAt time.Time  `json:"created_at"`
+	User      publicUser `json:"user"`
+}
+
+type publicFollowUp struct {
+	models.FollowUp
+	Admin publicUser `json:"admin"`
+}
+
+// publicCoords mengembalikan koordinat laporan yang disamarkan sejauh fuzz meter
+// (laporan anonim minimal anonymousFuzzMeters)
+func publicCoords(r models.Report, fuzz float64) (lat, lon float64, fuzzed bool) {
+	fuzz = reportFuzz(r.IsAnonymous, fuzz)
+	lat, lon = geo.Snap(r.Latitude, r.Longitude, fuzz)
+	return lat, lon, fuzz > 0
+}
+
+func newPublicReportView(r models.Report) publicReportView {
+	lat, lon, fuzzed := publicCoords(r, 0)
+	v := publicReportView{
+		ID:               r.ID,
+		TrackingID:       r.TrackingID,
+		IsAnonymous:      r.IsAnonymous,
+		Title:            r.Title,
+		Description:      r.Description,
+		Wilayah:          r.Wilayah,
+		Lokasi:           r.Lokasi,
+		Latitude:         lat,
+		Longitude:        lon,
+		Fuzzed:           fuzzed,
+		Status:           r.Status,
+		Priority:         r.Priority,
+		CategoryID:       r.CategoryID,
+		Category:         publicCategory{ID: r.Category.ID, Name: r.Category.Name},
+		EndorsementCount: r.EndorsementCount,
+		ReopenCount:      r.ReopenCount,
+		ResolvedAt:       r.ResolvedAt,
+		CreatedAt:        r.CreatedAt,
+		UpdatedAt:        r.UpdatedAt,
+		User:             publicReportUser(r),
+		Riwayat:          r.Riwayat,
+	}
+	v.Comments = newPublicComments(r.Comments, anonymousReporter(r))
+	v.FollowUps = newPublicFollowUps(r.FollowUps)
+	v.BuktiFotos = make([]publicPhoto, len(r.BuktiFotos))
+	for i, f := range r.BuktiFotos {
+		v.BuktiFotos[i] = publicPhoto{ID: f.ID, PhotoURL: f.PhotoURL, ThumbnailURL: f.ThumbnailURL, CreatedAt: f.CreatedAt}
+	}
+	return v
+}
+
+func newPublicReportViews(reports []models.Report) []publicReportView {
+	views := make([]publicReportView, len(reports))
+	for i, r := range reports {
+		views[i] = newPublicReportView(r)
+	}
+	return views
+}
+
+// anonymousReporter mengembalikan ID pelapor laporan anonim (0 jika tidak anonim);
+// komentar pelapor itu ditampilkan sebagai "Anonim"
+func anonymousReporter(r models.Report) uint {
+	if r.IsAnonymous {
+		return r.UserID
+	}
+	return 0
+}
+
+func newPublicComments(comments []models.Comment, anonymousUserID uint) []publicComment {
+	views := make([]publicComment, len(comments))
+	for i, cm := range comments {
+		name := cm.User.Name
+		if anonymousUserID != 0 && cm.UserID == anonymousUserID {
+			name = "Anonim"
+		}
+		views[i] = publicComment{ID: cm.ID, ReportID: cm.ReportID, Text: cm.Text, CreatedAt: cm.CreatedAt, User: publicUser{Name: name}}
+	}
+	return views
+}
+
+func newPublicFollowUps(followUps []models.FollowUp) []publicFollowUp {
+	views := make([]publicFollowUp, len(followUps))
+	for i, f := range followUps {
+		views[i] = publicFollowUp{FollowUp: f, Admin: publicUser{Name: f.Admin.Name}}
+	}
+	return views
+}
+
+// publicReport adalah ringkasan laporan untuk endpoint lokasi publik: tanpa data
+// kontak pelapor, dan koordinatnya sudah disamarkan (lihat reportFuzz)
+type publicReport struct {
+	ID               uint       `json:"id"`
+	TrackingID       string     `json:"tracking_id"`
+	Title            string     `json:"title"`
+	Description      string     `json:"description"`
+	Status           string     `json:"status"`
+	Priority         string     `json:"priority"`
+	CategoryID       *uint      `json:"category_id"`
+	Category         string     `json:"category"`
+	Wilayah          string     `json:"wilayah"`
+	Latitude         float64    `json:"latitude"`
+	Longitude        float64    `json:"longitude"`
+	Fuzzed           bool       `json:"fuzzed"`
+	EndorsementCount int        `json:"endorsement_count"`
+	CreatedAt        time.Time  `json:"created_at"`
+	User             publicUser `json:"user"`
+}
+
+// newPublicReport menyusun publicReport dengan koordinat yang disamarkan sejauh fuzz meter
+// (laporan anonim minimal anonymousFuzzMeters). Report harus dimuat bersama User dan Category.
+func newPublicReport(r models.Report, fuzz float64) publicReport {
+	lat, lon, fuzzed := publicCoords(r, fuzz)
+	return publicReport{
+		ID:               r.ID,
+		TrackingID:       r.TrackingID,
+		Title:            r.Title,
+		Description:      r.Description,
+		Status:           r.Status,
+		Priority:         r.Priority,
+		CategoryID:       r.CategoryID,
+		Category:         r.Category.Name,
+		Wilayah:          r.Wilayah,
+		Latitude:         lat,
+		Longitude:        lon,
+		Fuzzed:           fuzzed,
+		EndorsementCount: r.EndorsementCount,
+		CreatedAt:        r.CreatedAt,
+		User:             publicReportUser(r),
+	}
+}
@@ -0,0 +1,67 @@
+package controllers
+
+import (
+	"encoding/json"
+	"project-backend/models"
+	"strings"
+	"testing"
+)
+
+func TestPublicReportViewHidesContactData(t *testing.T) {
+	owner := models.User{ID: 3, Name: "Sari", Email: "sari@example.com", Phone: "+6281234567890", MessagingOptIn: true}
+	admin := models.User{ID: 9, Name: "Petugas", Email: "petugas@example.com", Role: "admin"}
+	report := models.Report{
+		Title:       "Jalan berlubang",
+		Latitude:    -7.782913,
+		Longitude:   110.367121,
+		Geohash:     "qqgu3bv6x",
+		KodeWilayah: "34.71.01.1001",
+		UserID:      owner.ID,
+		User:        owner,
+		Ratings:     []models.ReportRating{{Stars: 2}},
+		Comments:    []models.Comment{{Text: "segera", UserID: owner.ID, User: owner}},
+		FollowUps:   []models.FollowUp{{Deskripsi: "ditinjau", Admin: admin}},
+	}
+
+	tests := []struct {
+		name      string
+		anonymous bool
+		wantName  string
+	}{
+		{"laporan biasa", false, `"user":{"name":"Sari"}`},
+		{"laporan anonim", true, `"user":{"name":"Anonim"}`},
+	}
+	countName := func(s string) int { return strings.Count(s, `"name":"Sari"`) }
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := report
+			r.IsAnonymous = tt.anonymous
+			body, err := json.Marshal(newPublicReportView(r))
+			if err != nil {
+				t.Fatal(err)
+			}
+			s := string(body)
+			leaks := []string{"sari@example.com", "petugas@example.com", "+6281234567890", "messaging_opt_in", `"phone"`,
+				`"user_id"`, "kode_wilayah", "34.71.01.1001", "qqgu3bv6x", "ratings"}
+			if tt.anonymous {
+				leaks = append(leaks, "-7.782913", "110.367121")
+			}
+			for _, leak := range leaks {
+				if strings.Contains(s, leak) {
+					t.Errorf("respons publik memuat %s: %s", leak, s)
+				}
+			}
+			if tt.anonymous && countName(s) > 0 {
+				t.Errorf("nama pelapor anonim muncul di respons publik: %s", s)
+			}
+			if !tt.anonymous && countName(s) != 2 {
+				t.Errorf("nama pelapor harus muncul di laporan dan komentarnya: %s", s)
+			}
+			for _, want := range []string{tt.wantName, `"admin":{"name":"Petugas"}`, `"title":"Jalan berlubang"`} {
+				if !strings.Contains(s, want) {
+					t.Errorf("respons publik tidak memuat %s: %s", want, s)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package controllers
+
+import (
+	"net/http"
+	"project-backend/config"
+	"project-backend/models"
+	"project-backend/qr"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GET /reports/qr/:tracking_id?format=png|svg&size=256
+// QR code berisi tautan halaman pelacakan publik (/?tracking_id=...), yang di frontend
+// dicari lewat /reports/search. Untuk dicetak di loket, bukti pengaduan dan email.
+func GetTrackingQR(c *gin.Context) {
+	trackingID := c.Param("tracking_id")
+	var count int64
+	config.DB.Model(&models.Report{}).Where("tracking_id = ?", trackingID).Count(&count)
+	if count == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"message": "Laporan tidak ditemukan dengan Tracking ID tersebut"})
+		return
+	}
+
+	format := c.DefaultQuery("format", qr.FormatPNG)
+	size, _ := strconv.Atoi(c.Query("size"))
+	body, err := qr.Encode(config.TrackingURL(trackingID), format, size)
+	if err == qr.ErrUnknownFormat {
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": "Gagal membuat QR code"})
+		return
+	}
+
+	// tautan pelacakan tidak pernah berubah, jadi gambar boleh di-cache lama
+	c.Header("Cache-Control", "public, max-age=86400")
+	if v, _ := parseBoolQuery(c.Query("download")); v {
+		c.Header("Content-Disposition", `attachment; filename="qr-`+trackingID+"."+format+`"`)
+	}
+	c.Data(http.StatusOK, qr.ContentType(format), body)
+}
@@ -0,0 +1,204 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"project-backend/config"
+	"project-backend/models"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// errAlreadyReopened dikembalikan dari dalam transaksi jika status laporan sudah bukan
+// Selesai sejak dibaca di awal request
+var errAlreadyReopened = errors.New("Laporan sudah dibuka kembali atau statusnya berubah")
+
+// Batas waktu pelapor dapat menilai atau membuka kembali laporan setelah Selesai
+const resolutionFeedbackWindow = 14 * 24 * time.Hour
+
+// loadOwnResolvedReport mengambil laporan milik user login yang berstatus Selesai
+// dan masih dalam batas waktu penilaian.
+func loadOwnResolvedReport(c *gin.Context, report *models.Report) bool {
+	if err := config.DB.First(report, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"message": "Laporan tidak ditemukan"})
+		return false
+	}
+	if report.UserID != c.GetUint("userID") {
+		c.JSON(http.StatusForbidden, gin.H{"message": "Hanya pelapor yang dapat melakukan aksi ini"})
+		return false
+	}
+	if report.Status != "Selesai" || report.ResolvedAt == nil {
+		c.JSON(http.StatusConflict, gin.H{"message": "Laporan belum berstatus Selesai"})
+		return false
+	}
+	if time.Since(*report.ResolvedAt) > resolutionFeedbackWindow {
+		c.JSON(http.StatusConflict, gin.H{"message": "Batas waktu penilaian sudah lewat"})
+		return false
+	}
+	return true
+}
+
+// POST /reports/:id/rating -> pelapor menilai penyelesaian laporan (1-5 bintang + komentar)
+func RateReport(c *gin.Context) {
+	var report models.Report
+	if !loadOwnResolvedReport(c, &report) {
+		return
+	}
+
+	var input struct {
+		Stars   int    `json:"stars"`
+		Comment string `json:"comment"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil || input.Stars < 1 || input.Stars > 5 {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Nilai bintang harus antara 1 sampai 5"})
+		return
+	}
+
+	rating := models.ReportRating{
+		ReportID:   report.ID,
+		UserID:     report.UserID,
+		AdminID:    report.ResolvedBy,
+		Stars:      input.Stars,
+		Comment:    strings.TrimSpace(input.Comment),
+		ResolvedAt: *report.ResolvedAt,
+	}
+	// indeks unik (report_id, user_id, resolved_at) mencegah penilaian ganda walau dikirim bersamaan
+	res := config.DB.Clauses(clause.OnConflict{DoNothing: true}).Create(&rating)
+	if res.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": "Gagal menyimpan penilaian"})
+		return
+	}
+	if res.RowsAffected == 0 {
+		c.JSON(http.StatusConflict, gin.H{"message": "Penyelesaian laporan ini sudah dinilai"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Terima kasih atas penilaian Anda", "data": rating})
+}
+
+// POST /reports/:id/reopen -> pelapor membuka kembali laporan yang ternyata belum tuntas
+func ReopenReport(c *gin.Context) {
+	var report models.Report
+	if !loadOwnResolvedReport(c, &report) {
+		return
+	}
+
+	var input struct {
+		Alasan string `json:"alasan"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil || strings.TrimSpace(input.Alasan) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Alasan membuka kembali laporan wajib diisi"})
+		return
+	}
+
+	old := report
+	now := time.Now()
+	deskripsi := fmt.Sprintf("Pengaduan dibuka kembali oleh pelapor: %s", strings.TrimSpace(input.Alasan))
+	report.Status = "Diproses"
+	report.ResolvedAt = nil
+	report.ResolvedBy = nil
+	report.ReopenCount++
+
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		// hanya laporan yang masih Selesai; dari dua request bersamaan hanya satu yang berhasil
+		res := tx.Model(&models.Report{}).Where("id = ? AND status = ?", report.ID, "Selesai").Updates(map[string]interface{}{
+			"status":       report.Status,
+			"resolved_at":  nil,
+			"resolved_by":  nil,
+			"reopen_count": report.ReopenCount,
+		})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return errAlreadyReopened
+		}
+		if err := recordRevision(tx, report.ID, c.GetUint("userID"), c.GetString("role"), "reopen", diffReport(old, report)); err != nil {
+			return err
+		}
+		if err := tx.Create(&models.Riwayat{
+			ReportID:  report.ID,
+			Status:    report.Status,
+			Tanggal:   now,
+			Deskripsi: deskripsi,
+		}).Error; err != nil {
+			return err
+		}
+		return writeReportEvent(tx, eventReportStatusChanged, report.ID, reportEventData{
+			ActorID:   c.GetUint("userID"),
+			ActorRole: c.GetString("role"),
+			OldStatus: old.Status,
+			Status:    report.Status,
+			Deskripsi: deskripsi,
+			CreatedAt: now,
+		})
+	})
+	if err == errAlreadyReopened {
+		c.JSON(http.StatusConflict, gin.H{"message": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": "Gagal membuka kembali laporan"})
+		return
+	}
+
+	config.DB.First(&report, report.ID)
+	updatePriority(&report, "")
+	pushReportStatusChanged(report, old.Status)
+
+	c.JSON(http.StatusOK, gin.H{"message": "Laporan dibuka kembali dan dikembalikan ke petugas", "data": report})
+}
+
+// satisfactionStats menghitung rata-rata kepuasan pelapor per kategori dan per admin
+// yang menyelesaikan laporan
+func satisfactionStats(role string, adminID uint) gin.H {
+	type overallRow struct {
+		Average float64 `json:"average"`
+		Count   int64   `json:"count"`
+	}
+	type categoryRow struct {
+		CategoryID *uint   `json:"category_id"`
+		Kategori   string  `json:"kategori"`
+		Average    float64 `json:"average"`
+		Count      int64   `json:"count"`
+	}
+	type adminRow struct {
+		AdminID uint    `json:"admin_id"`
+		Admin   string  `json:"admin"`
+		Average float64 `json:"average"`
+		Count   int64   `json:"count"`
+	}
+
+	base := func() *gorm.DB {
+		return config.DB.Table("report_ratings").
+			Joins("JOIN reports ON reports.id = report_ratings.report_id").
+			Scopes(adminScope(role, adminID))
+	}
+
+	var overall overallRow
+	base().Select("COALESCE(AVG(report_ratings.stars), 0) as average, COUNT(*) as count").Scan(&overall)
+
+	var byCategory []categoryRow
+	base().Select("reports.category_id, categories.name as kategori, AVG(report_ratings.stars) as average, COUNT(*) as count").
+		Joins("LEFT JOIN categories ON categories.id = reports.category_id").
+		Group("reports.category_id, categories.name").
+		Scan(&byCategory)
+
+	var byAdmin []adminRow
+	base().Select("users.id as admin_id, users.name as admin, AVG(report_ratings.stars) as average, COUNT(*) as count").
+		Joins("JOIN users ON users.id = report_ratings.admin_id").
+		Group("users.id, users.name").
+		Scan(&byAdmin)
+
+	return gin.H{
+		"average":     overall.Average,
+		"count":       overall.Count,
+		"by_category": byCategory,
+		"by_admin":    byAdmin,
+	}
+}
@@ -0,0 +1,100 @@
+package controllers
+
+import (
+	"net/http"
+	"project-backend/config"
+	"project-backend/middleware"
+	"project-backend/models"
+	"project-backend/realtime"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Jenis event real-time
+const (
+	eventReportCreated       = "report.created"
+	eventReportStatusChanged = "report.status_changed"
+	eventReportAssigned      = "report.assigned" // hanya event outbox, tidak dikirim real-time
+	eventCommentCreated      = "comment.created"
+	eventFollowUpCreated     = "followup.created"
+	eventNotification        = "notification"
+)
+
+// POST /events/ticket -> tiket berumur pendek untuk membuka stream lewat EventSource,
+// yang tidak bisa mengirim header Authorization
+func CreateStreamTicket(c *gin.Context) {
+	ticket, exp, err := middleware.IssueStreamTicket(c.GetUint("userID"), c.GetString("role"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": "Gagal membuat tiket stream"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"ticket": ticket, "expires_at": exp})
+}
+
+// GET /events/stream -> Server-Sent Events untuk user login. Selain header Authorization,
+// EventSource bisa memakai ?ticket= dari POST /events/ticket. Event yang terlewat dikirim ulang berdasarkan Last-Event-ID;
+// event "reset" berarti klien perlu memuat ulang datanya.
+func StreamEvents(c *gin.Context) {
+	realtime.Default().Serve(c.Writer, c.Request, c.GetUint("userID"))
+}
+
+// scopedAdmins mengembalikan admin aktif yang lingkup tugasnya mencakup laporan
+func scopedAdmins(report models.Report) []uint {
+	var admins []models.User
+	config.DB.Preload("Categories").Preload("Regions").
+		Where("role IN ? AND is_active = ?", []string{"admin", "kategori_admin", "superadmin"}, true).
+		Find(&admins)
+	var ids []uint
+	for _, a := range admins {
+		if adminCoversReport(a, report) {
+			ids = append(ids, a.ID)
+		}
+	}
+	return ids
+}
+
+// reportWatchers adalah pelapor dan warga yang mendukung laporan ("saya juga")
+func reportWatchers(report models.Report) []uint {
+	var ids []uint
+	config.DB.Model(&models.Endorsement{}).Where("report_id = ?", report.ID).Pluck("user_id", &ids)
+	return append(ids, report.UserID)
+}
+
+// reportEventPayload hanya berisi data laporan tanpa identitas pelapor
+func reportEventPayload(report models.Report) gin.H {
+	return gin.H{
+		"report_id":   report.ID,
+		"tracking_id": report.TrackingID,
+		"title":       report.Title,
+		"status":      report.Status,
+		"category_id": report.CategoryID,
+		"priority":    report.Priority,
+	}
+}
+
+// pushReportCreated memberi tahu admin yang lingkupnya mencakup laporan baru
+func pushReportCreated(report models.Report) {
+	realtime.Publish(eventReportCreated, reportEventPayload(report), scopedAdmins(report))
+}
+
+// pushReportStatusChanged memberi tahu pelapor, pendukung dan admin terkait
+func pushReportStatusChanged(report models.Report, oldStatus string) {
+	data := reportEventPayload(report)
+	data["old_status"] = oldStatus
+	realtime.Publish(eventReportStatusChanged, data, append(reportWatchers(report), scopedAdmins(report)...))
+}
+
+// pushReportActivity mengirim event komentar/tindak lanjut ke pelapor, pendukung dan admin terkait
+func pushReportActivity(eventType string, reportID uint, data gin.H) {
+	var report models.Report
+	if err := config.DB.Select("id", "user_id", "category_id", "kode_wilayah").First(&report, reportID).Error; err != nil {
+		return
+	}
+	data["report_id"] = reportID
+	realtime.Publish(eventType, data, append(reportWatchers(report), scopedAdmins(report)...))
+}
+
+// PushNotification meneruskan notifikasi in-app baru ke koneksi real-time pemiliknya
+func PushNotification(n models.Notification) {
+	realtime.Publish(eventNotification, n, []uint{n.UserID})
+}
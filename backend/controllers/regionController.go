@@ -0,0 +1,275 @@
+package controllers
+
+import (
+	"net/http"
+	"project-backend/audit"
+	"project-backend/config"
+	"project-backend/geo"
+	"project-backend/models"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SyncRegions menyalin kode dan nama wilayah dari data batas wilayah ke tabel regions
+func SyncRegions(idx *geo.BoundaryIndex) (int, error) {
+	if idx == nil {
+		return 0, nil
+	}
+	var regions []models.Region
+	for _, b := range idx.All() {
+		regions = append(regions, models.Region{
+			Code:       b.Code,
+			Name:       b.Name,
+			Level:      string(b.Level),
+			ParentCode: geo.ParentCode(b.Code),
+		})
+	}
+	if len(regions) == 0 {
+		return 0, nil
+	}
+	err := config.DB.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "code"}},
+		DoUpdates: clause.AssignmentColumns([]string{"name", "level", "parent_code", "updated_at"}),
+	}).CreateInBatches(&regions, 200).Error
+	return len(regions), err
+}
+
+// regionIDByCode mengembalikan ID wilayah untuk kode tertentu (nil jika belum terdaftar)
+func regionIDByCode(code string) *uint {
+	if code == "" {
+		return nil
+	}
+	var region models.Region
+	if err := config.DB.Select("id").Where("code = ?", code).First(&region).Error; err != nil {
+		return nil
+	}
+	return &region.ID
+}
+
+// adminRegionCodes mengembalikan kode wilayah yang ditugaskan ke admin
+func adminRegionCodes(userID uint) []string {
+	var codes []string
+	config.DB.Table("admin_regions").
+		Joins("JOIN regions ON regions.id = admin_regions.region_id").
+		Where("admin_regions.user_id = ?", userID).
+		Pluck("regions.code", &codes)
+	return codes
+}
+
+// adminScope membatasi laporan sesuai lingkup admin: kategori miliknya dan wilayah
+// yang ditugaskan kepadanya (termasuk wilayah di bawahnya). Jika keduanya diisi,
+// laporan harus memenuhi keduanya. Admin tanpa kategori dan wilayah melihat semua laporan.
+// Laporan tanpa kode wilayah (di luar data batas wilayah) jatuh ke admin kategorinya;
+// jika admin wilayah tidak punya kategori, laporan itu masuk antrean superadmin
+// (?kode_wilayah=none).
+func adminScope(role string, userID uint) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if role != "admin" && role != "kategori_admin" {
+			return db
+		}
+		var categoryIDs []uint
+		if err := config.DB.Model(&models.Category{}).Where("user_id = ?", userID).Pluck("id", &categoryIDs).Error; err != nil {
+			return db // jangan filter kalau error
+		}
+		if len(categoryIDs) > 0 {
+			db = db.Where("reports.category_id IN ?", categoryIDs)
+		}
+		if codes := adminRegionCodes(userID); len(codes) > 0 {
+			cond, args := kodeWilayahCondition(codes)
+			if len(categoryIDs) > 0 {
+				cond = "(" + cond + " OR reports.kode_wilayah = '')"
+			}
+			db = db.Where(cond, args...)
+		}
+		return db
+	}
+}
+
+// adminCoversReport adalah versi adminScope untuk satu laporan; admin harus sudah
+// dimuat bersama Categories dan Regions
+func adminCoversReport(admin models.User, report models.Report) bool {
+	if admin.Role == "superadmin" {
+		return true
+	}
+	if admin.Role != "admin" && admin.Role != "kategori_admin" {
+		return false
+	}
+	if len(admin.Categories) > 0 {
+		owned := false
+		for _, cat := range admin.Categories {
+			if report.CategoryID != nil && cat.ID == *report.CategoryID {
+				owned = true
+				break
+			}
+		}
+		if !owned {
+			return false
+		}
+	}
+	if len(admin.Regions) > 0 {
+		if report.KodeWilayah == "" {
+			return len(admin.Categories) > 0
+		}
+		for _, r := range admin.Regions {
+			if report.KodeWilayah == r.Code || strings.HasPrefix(report.KodeWilayah, r.Code+".") {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// GET /regions?level=kecamatan&parent=34.04&q=gamping
+func GetRegions(c *gin.Context) {
+	db := config.DB.Model(&models.Region{})
+	if v := c.Query("level"); v != "" {
+		db = db.Where("level = ?", v)
+	}
+	if v := c.Query("parent"); v != "" {
+		db = db.Where("parent_code = ?", geo.NormalizeCode(v))
+	}
+	if v := strings.TrimSpace(c.Query("q")); v != "" {
+		db = db.Where("name LIKE ?", "%"+v+"%")
+	}
+
+	var regions []models.Region
+	if err := db.Order("code").Find(&regions).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": "Gagal mengambil wilayah"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": regions})
+}
+
+// POST /regions (superadmin) -> tambah wilayah yang tidak ada di data batas wilayah
+func CreateRegion(c *gin.Context) {
+	var body struct {
+		Code string `json:"code" binding:"required"`
+		Name string `json:"name" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Kode dan nama wilayah wajib diisi"})
+		return
+	}
+
+	code := geo.NormalizeCode(body.Code)
+	level := geo.LevelOfCode(code)
+	if level == "" || strings.Trim(code, "0123456789.") != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Format kode wilayah tidak valid"})
+		return
+	}
+	region := models.Region{
+		Code:       code,
+		Name:       strings.TrimSpace(body.Name),
+		Level:      string(level),
+		ParentCode: geo.ParentCode(code),
+	}
+	if region.ParentCode != "" && regionIDByCode(region.ParentCode) == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Wilayah induk " + region.ParentCode + " belum terdaftar"})
+		return
+	}
+	if err := config.DB.Create(&region).Error; err != nil {
+		c.JSON(http.StatusConflict, gin.H{"message": "Kode wilayah sudah terdaftar"})
+		return
+	}
+	audit.SetTargetID(c, region.ID)
+	audit.SetAfter(c, region)
+
+	c.JSON(http.StatusCreated, gin.H{"message": "Wilayah berhasil ditambahkan", "data": region})
+}
+
+// PUT /admin/users/:id/regions (superadmin) -> tetapkan wilayah tugas admin
+// body: {"region_codes": ["34.04.05", "34.04.06"]}, daftar kosong menghapus semua
+func SetAdminRegions(c *gin.Context) {
+	var body struct {
+		RegionCodes []string `json:"region_codes"`
+	}
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid request body"})
+		return
+	}
+
+	var admin models.User
+	if err := config.DB.Preload("Regions").First(&admin, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"message": "User not found"})
+		return
+	}
+	if admin.Role != "admin" && admin.Role != "kategori_admin" {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Wilayah hanya dapat ditetapkan untuk admin"})
+		return
+	}
+
+	codes := make([]string, 0, len(body.RegionCodes))
+	for _, code := range body.RegionCodes {
+		codes = append(codes, geo.NormalizeCode(code))
+	}
+	var regions []models.Region
+	if len(codes) > 0 {
+		if err := config.DB.Where("code IN ?", codes).Find(&regions).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"message": "Gagal mengambil wilayah"})
+			return
+		}
+		if len(regions) != len(codes) {
+			c.JSON(http.StatusBadRequest, gin.H{"message": "Sebagian kode wilayah tidak terdaftar"})
+			return
+		}
+	}
+
+	before := make([]string, 0, len(admin.Regions))
+	for _, r := range admin.Regions {
+		before = append(before, r.Code)
+	}
+	audit.SetBefore(c, gin.H{"region_codes": before})
+
+	if err := config.DB.Model(&admin).Association("Regions").Replace(regions); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": "Gagal menyimpan wilayah admin"})
+		return
+	}
+	audit.SetAfter(c, gin.H{"region_codes": codes})
+
+	c.JSON(http.StatusOK, gin.H{"message": "Wilayah admin berhasil diperbarui", "data": regions})
+}
+
+// POST /regions/resolve-reports (superadmin) -> isi ulang kode wilayah laporan dari koordinatnya,
+// mis. setelah data batas wilayah diperbarui
+func ResolveReportRegions(c *gin.Context) {
+	if geo.Boundaries() == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"message": "Data batas wilayah belum tersedia"})
+		return
+	}
+
+	var reports []models.Report
+	updated, outside := 0, 0
+	err := config.DB.Select("id", "latitude", "longitude", "wilayah", "kode_wilayah", "region_id").
+		FindInBatches(&reports, 200, func(tx *gorm.DB, batch int) error {
+			for _, r := range reports {
+				before := r
+				if err := applyRegion(&r); err != nil {
+					outside++
+					continue
+				}
+				if r.KodeWilayah == before.KodeWilayah && r.Wilayah == before.Wilayah &&
+					formatUintPtr(r.RegionID) == formatUintPtr(before.RegionID) {
+					continue
+				}
+				if err := config.DB.Model(&models.Report{}).Where("id = ?", r.ID).UpdateColumns(map[string]interface{}{
+					"kode_wilayah": r.KodeWilayah,
+					"wilayah":      r.Wilayah,
+					"region_id":    r.RegionID,
+				}).Error; err != nil {
+					return err
+				}
+				updated++
+			}
+			return nil
+		}).Error
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": "Gagal memperbarui wilayah laporan"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Wilayah laporan diperbarui", "updated": updated, "outside": outside})
+}
@@ -0,0 +1,47 @@
+package controllers
+
+import (
+	"project-backend/models"
+	"testing"
+)
+
+func TestAdminCoversReport(t *testing.T) {
+	cat := func(id uint) *uint { return &id }
+	sleman := []models.Region{{Code: "34.04"}}
+	kebersihan := []models.Category{{ID: 1}}
+
+	tests := []struct {
+		name   string
+		admin  models.User
+		report models.Report
+		want   bool
+	}{
+		{"superadmin melihat semua", models.User{Role: "superadmin"}, models.Report{}, true},
+		{"warga tidak tercakup", models.User{Role: "user"}, models.Report{}, false},
+		{"admin tanpa lingkup", models.User{Role: "admin"}, models.Report{KodeWilayah: "34.71"}, true},
+		{"wilayah di bawahnya", models.User{Role: "admin", Regions: sleman}, models.Report{KodeWilayah: "34.04.05"}, true},
+		{"wilayah lain", models.User{Role: "admin", Regions: sleman}, models.Report{KodeWilayah: "34.71"}, false},
+		{"awalan kode bukan induk", models.User{Role: "admin", Regions: sleman}, models.Report{KodeWilayah: "34.041"}, false},
+		{"tanpa kode wilayah, admin wilayah saja", models.User{Role: "admin", Regions: sleman}, models.Report{}, false},
+		{"tanpa kode wilayah, kategori cocok", models.User{Role: "kategori_admin", Regions: sleman, Categories: kebersihan}, models.Report{CategoryID: cat(1)}, true},
+		{"tanpa kode wilayah, kategori lain", models.User{Role: "kategori_admin", Regions: sleman, Categories: kebersihan}, models.Report{CategoryID: cat(2)}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := adminCoversReport(tt.admin, tt.report); got != tt.want {
+				t.Errorf("adminCoversReport = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestKodeWilayahConditionNone(t *testing.T) {
+	cond, args := kodeWilayahCondition([]string{"none", "3404"})
+	want := "(reports.kode_wilayah = '' OR reports.kode_wilayah = ? OR reports.kode_wilayah LIKE ?)"
+	if cond != want {
+		t.Errorf("cond = %q, want %q", cond, want)
+	}
+	if len(args) != 2 || args[0] != "34.04" || args[1] != "34.04.%" {
+		t.Errorf("args = %v", args)
+	}
+}
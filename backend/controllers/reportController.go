@@ -3,12 +3,14 @@ package controllers
 import (
 	"fmt"
 	"net/http"
+	"project-backend/audit"
 	"project-backend/config"
 	"project-backend/models"
 	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
 func generateTrackingID() string {
@@ -37,7 +39,7 @@ func SearchReportByTrackingID(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"data": report})
+	c.JSON(http.StatusOK, gin.H{"data": newPublicReportView(report)})
 }
 
 func CreateReport(c *gin.Context) {
@@ -65,6 +67,16 @@ func CreateReport(c *gin.Context) {
 		return
 	}
 
+	// kode wilayah diambil dari batas wilayah resmi; nama kabupaten/kota menggantikan isian bebas
+	kodeWilayah, namaWilayah, err := resolveRegion(latitude, longitude)
+	if err != nil {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"message": err.Error()})
+		return
+	}
+	if namaWilayah != "" {
+		wilayah = namaWilayah
+	}
+
 	// handle file upload
 	form, err := c.MultipartForm()
 	if err != nil {
@@ -99,6 +111,8 @@ func CreateReport(c *gin.Context) {
 		IsAnonymous: isAnonymous,
 		Title:       title,
 		Wilayah:     wilayah,
+		KodeWilayah: kodeWilayah,
+		RegionID:    regionIDByCode(kodeWilayah),
 		Lokasi:      lokasi,
 		Latitude:    latitude,
 		Longitude:   longitude,
@@ -108,35 +122,54 @@ func CreateReport(c *gin.Context) {
 		CategoryID:  catID,
 	}
 
-	if err := config.DB.Create(&report).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to create report", "error": err.Error()})
-		return
-	}
-
-	// simpan foto ke tabel bukti_fotos
+	// foto disimpan ke disk dulu; jika transaksi gagal berkasnya dihapus lagi
+	var photoPaths []string
 	for _, file := range files {
 		filename := time.Now().Format("20060102150405") + "_" + file.Filename
 		photoPath := "bukti_foto/" + filename
 		if err := c.SaveUploadedFile(file, photoPath); err != nil {
+			removeFiles(photoPaths)
 			c.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to save photo", "error": err.Error()})
 			return
 		}
-
-		bukti := models.BuktiFoto{
-			ReportID: report.ID,
-			PhotoURL: photoPath,
-		}
-		config.DB.Create(&bukti)
+		photoPaths = append(photoPaths, photoPath)
 	}
 
-	// buat riwayat awal otomatis
-	riwayat := models.Riwayat{
-		ReportID:  report.ID,
-		Status:    "Diajukan",
-		Tanggal:   time.Now(),
-		Deskripsi: fmt.Sprintf("Pengaduan telah diterima dan terdaftar dalam sistem oleh Pemerintah wilayah %s", wilayah),
+	// laporan, foto bukti beserta job thumbnailnya, riwayat awal dan event outbox disimpan bersama
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&report).Error; err != nil {
+			return err
+		}
+		for _, path := range photoPaths {
+			bukti := models.BuktiFoto{ReportID: report.ID, PhotoURL: path}
+			if err := tx.Create(&bukti).Error; err != nil {
+				return err
+			}
+			if err := enqueueThumbnail(tx, bukti.ID); err != nil {
+				return err
+			}
+		}
+		if err := tx.Create(&models.Riwayat{
+			ReportID:  report.ID,
+			Status:    "Diajukan",
+			Tanggal:   time.Now(),
+			Deskripsi: fmt.Sprintf("Pengaduan telah diterima dan terdaftar dalam sistem oleh Pemerintah wilayah %s", wilayah),
+		}).Error; err != nil {
+			return err
+		}
+		if err := enqueueSearchIndex(tx, report.ID); err != nil {
+			return err
+		}
+		return writeReportEvent(tx, eventReportCreated, report.ID, reportEventData{ActorID: userID, Status: report.Status})
+	})
+	if err != nil {
+		removeFiles(photoPaths)
+		c.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to create report", "error": err.Error()})
+		return
 	}
-	config.DB.Create(&riwayat)
+	// hitung prioritas awal
+	updatePriority(&report, "")
+	pushReportCreated(report)
 
 	c.JSON(http.StatusOK, gin.H{"message": "Report created", "data": report})
 }
@@ -146,37 +179,42 @@ func GetMyReports(c *gin.Context) {
 	userID, _ := c.Get("userID")
 	var reports []models.Report
 
-	if err := config.DB.
-		Where("user_id = ?", userID).
-		Preload("User").
-		Preload("BuktiFotos").
-		Order("created_at DESC").
-		Find(&reports).Error; err != nil {
+	meta, err := paginateReports(c, config.DB.Where("reports.user_id = ?", userID), &reports, "User", "BuktiFotos")
+	if err == errInvalidCursor {
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"message": "Gagal mengambil laporan"})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"data": reports})
+	c.JSON(http.StatusOK, gin.H{"data": reports, "meta": meta})
 }
 
 // User melihat semua laporan publik (status apapun)
 func GetAllReports(c *gin.Context) {
 	var reports []models.Report
 
-	if err := config.DB.Preload("User").Preload("BuktiFotos").Order("created_at desc").Find(&reports).Error; err != nil {
+	meta, err := paginateReports(c, config.DB, &reports, "User", "BuktiFotos")
+	if err == errInvalidCursor {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil data laporan"})
 		return
 	}
 
-	// Jika laporan anonim, sembunyikan identitas user
-	for i := range reports {
-		if reports[i].IsAnonymous {
-			reports[i].User.Name = "Anonim"
-			reports[i].User.Email = ""
-		}
-	}
+	// identitas pelapor hanya berupa nama ("Anonim" untuk laporan anonim)
+	views := newPublicReportViews(reports)
 
-	c.JSON(http.StatusOK, reports)
+	// tanpa parameter paginasi respon tetap berupa array seperti semula
+	if !pagingRequested(c) {
+		c.JSON(http.StatusOK, views)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": views, "meta": meta})
 }
 
 func GetLatestReports(c *gin.Context) {
@@ -184,13 +222,13 @@ func GetLatestReports(c *gin.Context) {
 	if err := config.DB.
 		Preload("User").
 		Preload("BuktiFotos").
-		Order("created_at DESC").
+		Scopes(reportListQuery(c)).
 		Limit(8).
 		Find(&reports).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"message": "Gagal mengambil laporan terbaru"})
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"data": reports})
+	c.JSON(http.StatusOK, gin.H{"data": newPublicReportViews(reports)})
 }
 
 // Admin melihat semua laporan
@@ -207,19 +245,22 @@ func GetReportsAdmin(c *gin.Context) {
 		return
 	}
 
-	db := config.DB.Preload("User").Order("created_at DESC")
-
 	if role != "superadmin" && role != "kategori_admin" && role != "admin" {
 		c.JSON(http.StatusForbidden, gin.H{"message": "Unauthorized"})
 		return
 	}
 
-	if err := db.Find(&reports).Error; err != nil {
+	meta, err := paginateReports(c, config.DB, &reports, "User")
+	if err == errInvalidCursor {
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"message": "Gagal mengambil laporan"})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"data": reports})
+	c.JSON(http.StatusOK, gin.H{"data": reports, "meta": meta})
 }
 
 // Admin update status laporan
@@ -259,12 +300,17 @@ func UpdateReportStatus(c *gin.Context) {
 	}
 
 	// Update status laporan
+	old := report
 	report.Status = input.Status
-	if err := config.DB.Save(&report).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"message": "Failed update report status"})
-		return
+	if input.Status == "Selesai" {
+		now := time.Now()
+		adminID := c.GetUint("userID")
+		report.ResolvedAt = &now
+		report.ResolvedBy = &adminID
+	} else {
+		report.ResolvedAt = nil
+		report.ResolvedBy = nil
 	}
-
 	// Deskripsi otomatis jika admin tidak mengisi
 	var deskripsi string
 	if input.Deskripsi != "" {
@@ -284,15 +330,43 @@ func UpdateReportStatus(c *gin.Context) {
 		}
 	}
 
-	// Simpan riwayat baru
-	riwayat := models.Riwayat{
-		ReportID:  report.ID,
-		Status:    input.Status,
-		Tanggal:   time.Now(),
-		Deskripsi: deskripsi,
+	// Status, revisi, riwayat baru dan event outbox disimpan dalam satu transaksi. Hanya
+	// kolom status yang ditulis agar dukungan dan prioritas yang berubah sejak laporan
+	// dibaca tidak tertimpa.
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&report).Select("status", "resolved_at", "resolved_by").Updates(&report).Error; err != nil {
+			return err
+		}
+		if err := recordRevision(tx, report.ID, c.GetUint("userID"), c.GetString("role"), "status", diffReport(old, report)); err != nil {
+			return err
+		}
+		if err := tx.Create(&models.Riwayat{
+			ReportID:  report.ID,
+			Status:    input.Status,
+			Tanggal:   time.Now(),
+			Deskripsi: deskripsi,
+		}).Error; err != nil {
+			return err
+		}
+		return writeReportEvent(tx, eventReportStatusChanged, report.ID, reportEventData{
+			ActorID:   c.GetUint("userID"),
+			ActorRole: c.GetString("role"),
+			OldStatus: old.Status,
+			Status:    report.Status,
+			Deskripsi: deskripsi,
+		})
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": "Failed update report status"})
+		return
 	}
 
-	config.DB.Create(&riwayat)
+	// status baru mengubah acuan SLA, hitung ulang prioritas
+	updatePriority(&report, "")
+	pushReportStatusChanged(report, old.Status)
+
+	audit.SetBefore(c, gin.H{"status": old.Status})
+	audit.SetAfter(c, gin.H{"status": report.Status, "deskripsi": deskripsi})
 
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Status & riwayat updated",
@@ -304,7 +378,7 @@ func GetReportsFiltered(c *gin.Context) {
 	var reports []models.Report
 	filter := c.Query("filter")
 	monthParam := c.Query("month")
-	db := config.DB.Preload("User").Preload("Category") //tambhan ini
+	db := config.DB
 
 	roleVal, _ := c.Get("role")
 	role := roleVal.(string)
@@ -325,12 +399,12 @@ func GetReportsFiltered(c *gin.Context) {
 			return
 		}
 		end := start.AddDate(0, 1, 0)
-		db = db.Where("created_at >= ? AND created_at < ?", start, end)
+		db = db.Where("reports.created_at >= ? AND reports.created_at < ?", start, end)
 	} else {
 		switch filter {
 		case "today":
 			start := time.Now().Truncate(24 * time.Hour)
-			db = db.Where("created_at >= ?", start)
+			db = db.Where("reports.created_at >= ?", start)
 		case "week":
 			now := time.Now()
 			weekday := int(now.Weekday())
@@ -338,38 +412,28 @@ func GetReportsFiltered(c *gin.Context) {
 				weekday = 7
 			}
 			start := now.AddDate(0, 0, -weekday+1).Truncate(24 * time.Hour)
-			db = db.Where("created_at >= ?", start)
+			db = db.Where("reports.created_at >= ?", start)
 		case "month":
 			now := time.Now()
 			start := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
-			db = db.Where("created_at >= ?", start)
+			db = db.Where("reports.created_at >= ?", start)
 		}
 	}
 
-	if role == "admin" {
-		var categories []models.Category
-		if err := config.DB.Where("user_id = ?", admin.ID).Find(&categories).Error; err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"message": "Gagal mengambil kategori admin"})
-			return
-		}
-
-		var categoryIDs []uint
-		for _, cat := range categories {
-			categoryIDs = append(categoryIDs, cat.ID)
-		}
+	// admin hanya melihat laporan dalam kategori/wilayah tugasnya
+	db = db.Scopes(adminScope(role, admin.ID))
 
-		if len(categoryIDs) > 0 {
-			db = db.Where("category_id IN ?", categoryIDs)
-		} else {
-		}
+	meta, err := paginateReports(c, db, &reports, "User", "Category")
+	if err == errInvalidCursor {
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
 	}
-
-	if err := db.Order("created_at DESC").Find(&reports).Error; err != nil {
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"message": "Gagal mengambil laporan"})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"data": reports})
+	c.JSON(http.StatusOK, gin.H{"data": reports, "meta": meta})
 }
 
 // controllers/report.go
@@ -381,6 +445,7 @@ func GetReportByID(c *gin.Context) {
 		Preload("BuktiFotos").
 		Preload("Category").
 		Preload("Riwayat"). // ← INI YANG PENTING!
+		Preload("Ratings").
 		First(&report, id).Error; err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"message": "Laporan tidak ditemukan"})
 		return
@@ -403,13 +468,8 @@ func GetPublicReportByID(c *gin.Context) {
 		return
 	}
 
-	// Sembunyikan identitas jika anonim
-	if report.IsAnonymous {
-		report.User.Name = "Anonim"
-		report.User.Email = ""
-	}
-
-	c.JSON(http.StatusOK, gin.H{"data": report})
+	// identitas pelapor hanya berupa nama, "Anonim" jika anonim
+	c.JSON(http.StatusOK, gin.H{"data": newPublicReportView(report)})
 }
 
 func UpdateReportAdmin(c *gin.Context) {
@@ -427,6 +487,7 @@ func UpdateReportAdmin(c *gin.Context) {
 		c.JSON(http.StatusNotFound, gin.H{"message": "Report not found"})
 		return
 	}
+	old := report
 
 	// Body request (opsional semua)
 	var body struct {
@@ -465,12 +526,49 @@ func UpdateReportAdmin(c *gin.Context) {
 	if body.Longitude != nil {
 		report.Longitude = *body.Longitude
 	}
+	if report.Latitude != old.Latitude || report.Longitude != old.Longitude {
+		if err := applyRegion(&report); err != nil {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"message": err.Error()})
+			return
+		}
+	}
 
-	// Save ke DB
-	if err := config.DB.Save(&report).Error; err != nil {
+	// Save ke DB sekaligus simpan revisi (nilai lama -> baru); pindah kategori berarti
+	// pindah petugas, diberitahukan lewat event outbox dalam transaksi yang sama
+	changes := diffReport(old, report)
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		if len(changes) == 0 {
+			return nil
+		}
+		// hanya kolom yang diubah (lihat changedColumns), agar dukungan dan prioritas
+		// yang berubah sejak laporan dibaca tidak tertimpa
+		if err := tx.Model(&report).Select(changedColumns(changes)).Updates(&report).Error; err != nil {
+			return err
+		}
+		if err := recordRevision(tx, report.ID, c.GetUint("userID"), c.GetString("role"), "edit", changes); err != nil {
+			return err
+		}
+		if err := enqueueSearchIndex(tx, report.ID); err != nil {
+			return err
+		}
+		if formatUintPtr(old.CategoryID) == formatUintPtr(report.CategoryID) {
+			return nil
+		}
+		return writeReportEvent(tx, eventReportAssigned, report.ID, reportEventData{
+			ActorID:    c.GetUint("userID"),
+			ActorRole:  c.GetString("role"),
+			CategoryID: report.CategoryID,
+		})
+	})
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to update report"})
 		return
 	}
 
+	// kategori, judul atau deskripsi bisa mengubah skor prioritas
+	updatePriority(&report, "")
+
+	audit.SetAfter(c, changes)
+
 	c.JSON(http.StatusOK, gin.H{"message": "Report updated successfully", "data": report})
 }
@@ -0,0 +1,339 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"project-backend/config"
+	"project-backend/models"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// errNotDiajukan dikembalikan dari dalam transaksi jika status laporan sudah berubah
+// (mis. diproses admin) sejak dibaca di awal request
+var errNotDiajukan = errors.New("Laporan hanya dapat diubah selama status masih Diajukan")
+
+// Kolom tabel reports yang ikut berubah untuk tiap field hasil diffReport.
+// Koordinat juga menentukan geohash dan wilayah hasil lookup.
+var editableColumns = map[string][]string{
+	"title":        {"title"},
+	"description":  {"description"},
+	"wilayah":      {"wilayah"},
+	"lokasi":       {"lokasi"},
+	"latitude":     {"latitude", "geohash", "kode_wilayah", "region_id"},
+	"longitude":    {"longitude", "geohash", "kode_wilayah", "region_id"},
+	"category_id":  {"category_id"},
+	"is_anonymous": {"is_anonymous"},
+}
+
+// changedColumns mengembalikan kolom yang perlu ditulis untuk daftar perubahan
+func changedColumns(changes models.FieldChanges) []string {
+	seen := map[string]bool{}
+	var cols []string
+	for _, ch := range changes {
+		for _, col := range editableColumns[ch.Field] {
+			if !seen[col] {
+				seen[col] = true
+				cols = append(cols, col)
+			}
+		}
+	}
+	return cols
+}
+
+// lockReportDiajukan mengunci baris laporan sampai transaksi selesai dan memastikan
+// statusnya masih Diajukan
+func lockReportDiajukan(tx *gorm.DB, reportID uint) error {
+	var current models.Report
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id", "status").First(&current, reportID).Error; err != nil {
+		return err
+	}
+	if current.Status != "Diajukan" {
+		return errNotDiajukan
+	}
+	return nil
+}
+
+// removeFiles menghapus file upload yang tidak jadi dipakai
+func removeFiles(paths []string) {
+	for _, path := range paths {
+		os.Remove(path)
+	}
+}
+
+// loadOwnReportDiajukan mengambil laporan milik user login yang masih berstatus Diajukan.
+// Mengirim respon error dan mengembalikan false jika tidak memenuhi syarat.
+func loadOwnReportDiajukan(c *gin.Context, report *models.Report) bool {
+	if err := config.DB.Preload("BuktiFotos").First(report, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"message": "Laporan tidak ditemukan"})
+		return false
+	}
+	if report.UserID != c.GetUint("userID") {
+		c.JSON(http.StatusForbidden, gin.H{"message": "Hanya pelapor yang dapat mengubah laporan ini"})
+		return false
+	}
+	if report.Status != "Diajukan" {
+		c.JSON(http.StatusConflict, gin.H{"message": errNotDiajukan.Error()})
+		return false
+	}
+	return true
+}
+
+// PUT /reports/:id -> pelapor memperbaiki laporannya (field dan foto) selama masih Diajukan.
+// Field yang tidak dikirim tidak diubah. Foto baru dikirim lewat "photo", foto yang dihapus
+// lewat "remove_photo_ids" (boleh dipisah koma).
+func UpdateMyReport(c *gin.Context) {
+	var report models.Report
+	if !loadOwnReportDiajukan(c, &report) {
+		return
+	}
+	old := report
+
+	if v, ok := c.GetPostForm("title"); ok {
+		report.Title = v
+	}
+	if v, ok := c.GetPostForm("description"); ok {
+		report.Description = v
+	}
+	if v, ok := c.GetPostForm("wilayah"); ok {
+		report.Wilayah = v
+	}
+	if v, ok := c.GetPostForm("lokasi"); ok {
+		report.Lokasi = v
+	}
+	if v, ok := c.GetPostForm("is_anonymous"); ok {
+		report.IsAnonymous = v == "true" || v == "1"
+	}
+	if v, ok := c.GetPostForm("latitude"); ok {
+		lat, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid latitude or longitude"})
+			return
+		}
+		report.Latitude = lat
+	}
+	if v, ok := c.GetPostForm("longitude"); ok {
+		lon, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid latitude or longitude"})
+			return
+		}
+		report.Longitude = lon
+	}
+	if report.Latitude != old.Latitude || report.Longitude != old.Longitude {
+		if err := applyRegion(&report); err != nil {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"message": err.Error()})
+			return
+		}
+	}
+	if v, ok := c.GetPostForm("category_id"); ok {
+		if v == "" {
+			report.CategoryID = nil
+		} else {
+			parsed, err := strconv.ParseUint(v, 10, 64)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"message": "category_id tidak valid"})
+				return
+			}
+			tmp := uint(parsed)
+			report.CategoryID = &tmp
+		}
+	}
+
+	// foto yang akan dihapus
+	removeIDs := map[uint]bool{}
+	for _, raw := range c.PostFormArray("remove_photo_ids") {
+		for _, part := range strings.Split(raw, ",") {
+			part = strings.TrimSpace(part)
+			if part == "" {
+				continue
+			}
+			id, err := strconv.ParseUint(part, 10, 64)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"message": "remove_photo_ids tidak valid"})
+				return
+			}
+			removeIDs[uint(id)] = true
+		}
+	}
+	var removed []models.BuktiFoto
+	for _, foto := range report.BuktiFotos {
+		if removeIDs[foto.ID] {
+			removed = append(removed, foto)
+			delete(removeIDs, foto.ID)
+		}
+	}
+	if len(removeIDs) > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Foto yang akan dihapus bukan milik laporan ini"})
+		return
+	}
+
+	// foto baru (opsional)
+	var newFiles []*multipart.FileHeader
+	if form, err := c.MultipartForm(); err == nil {
+		newFiles = form.File["photo"]
+	}
+	total := len(report.BuktiFotos) - len(removed) + len(newFiles)
+	if total < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Minimal 1 foto wajib diupload"})
+		return
+	}
+	if total > 3 {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Maximum 3 photos allowed"})
+		return
+	}
+
+	changes := diffReport(old, report)
+	if len(changes) == 0 && len(removed) == 0 && len(newFiles) == 0 {
+		c.JSON(http.StatusOK, gin.H{"message": "Tidak ada perubahan", "data": report})
+		return
+	}
+
+	// simpan file baru lebih dulu, baru kemudian tulis ke database
+	var newPaths []string
+	for _, file := range newFiles {
+		filename := time.Now().Format("20060102150405") + "_" + file.Filename
+		photoPath := "bukti_foto/" + filename
+		if err := c.SaveUploadedFile(file, photoPath); err != nil {
+			removeFiles(newPaths)
+			c.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to save photo", "error": err.Error()})
+			return
+		}
+		newPaths = append(newPaths, photoPath)
+	}
+
+	userID := c.GetUint("userID")
+	role := c.GetString("role")
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := lockReportDiajukan(tx, report.ID); err != nil {
+			return err
+		}
+		if len(changes) > 0 {
+			// hanya kolom yang diubah pelapor, agar perubahan lain (status, dukungan,
+			// prioritas) yang terjadi sejak laporan dibaca tidak tertimpa
+			res := tx.Model(&report).Where("status = ?", "Diajukan").Select(changedColumns(changes)).Updates(&report)
+			if res.Error != nil {
+				return res.Error
+			}
+			if res.RowsAffected == 0 {
+				return errNotDiajukan
+			}
+			if err := recordRevision(tx, report.ID, userID, role, "edit", changes); err != nil {
+				return err
+			}
+		}
+
+		var photoChanges models.FieldChanges
+		for _, foto := range removed {
+			if err := tx.Delete(&foto).Error; err != nil {
+				return err
+			}
+			photoChanges = append(photoChanges, models.FieldChange{Field: "bukti_foto", Old: foto.PhotoURL})
+		}
+		if len(photoChanges) > 0 {
+			if err := recordRevision(tx, report.ID, userID, role, "photo_remove", photoChanges); err != nil {
+				return err
+			}
+		}
+
+		photoChanges = nil
+		for _, path := range newPaths {
+			foto := models.BuktiFoto{ReportID: report.ID, PhotoURL: path}
+			if err := tx.Create(&foto).Error; err != nil {
+				return err
+			}
+			if err := enqueueThumbnail(tx, foto.ID); err != nil {
+				return err
+			}
+			photoChanges = append(photoChanges, models.FieldChange{Field: "bukti_foto", New: path})
+		}
+		if len(photoChanges) > 0 {
+			if err := recordRevision(tx, report.ID, userID, role, "photo_add", photoChanges); err != nil {
+				return err
+			}
+		}
+		return enqueueSearchIndex(tx, report.ID)
+	})
+	if err != nil {
+		removeFiles(newPaths)
+		if errors.Is(err, errNotDiajukan) {
+			c.JSON(http.StatusConflict, gin.H{"message": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"message": "Gagal memperbarui laporan"})
+		return
+	}
+
+	updatePriority(&report, "")
+	config.DB.Preload("BuktiFotos").First(&report, report.ID)
+
+	c.JSON(http.StatusOK, gin.H{"message": "Laporan berhasil diperbarui", "data": report})
+}
+
+// POST /reports/:id/withdraw -> pelapor membatalkan laporannya selama masih Diajukan
+func WithdrawMyReport(c *gin.Context) {
+	var report models.Report
+	if !loadOwnReportDiajukan(c, &report) {
+		return
+	}
+
+	var input struct {
+		Alasan string `json:"alasan"`
+	}
+	c.ShouldBindJSON(&input)
+
+	old := report
+	report.Status = "Dibatalkan"
+
+	deskripsi := "Pengaduan dibatalkan oleh pelapor"
+	if strings.TrimSpace(input.Alasan) != "" {
+		deskripsi = fmt.Sprintf("%s: %s", deskripsi, strings.TrimSpace(input.Alasan))
+	}
+
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&models.Report{}).Where("id = ? AND status = ?", report.ID, "Diajukan").Update("status", report.Status)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return errNotDiajukan
+		}
+		if err := recordRevision(tx, report.ID, c.GetUint("userID"), c.GetString("role"), "withdraw", diffReport(old, report)); err != nil {
+			return err
+		}
+		if err := tx.Create(&models.Riwayat{
+			ReportID:  report.ID,
+			Status:    report.Status,
+			Tanggal:   time.Now(),
+			Deskripsi: deskripsi,
+		}).Error; err != nil {
+			return err
+		}
+		return writeReportEvent(tx, eventReportStatusChanged, report.ID, reportEventData{
+			ActorID:   c.GetUint("userID"),
+			ActorRole: c.GetString("role"),
+			OldStatus: old.Status,
+			Status:    report.Status,
+			Deskripsi: deskripsi,
+		})
+	})
+	if errors.Is(err, errNotDiajukan) {
+		c.JSON(http.StatusConflict, gin.H{"message": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": "Gagal membatalkan laporan"})
+		return
+	}
+	pushReportStatusChanged(report, old.Status)
+
+	c.JSON(http.StatusOK, gin.H{"message": "Laporan berhasil dibatalkan", "data": report})
+}
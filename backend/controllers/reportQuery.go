@@ -0,0 +1,420 @@
+package controllers
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"project-backend/geo"
+	"project-backend/models"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultPageLimit = 20
+	maxPageLimit     = 100
+)
+
+// Field yang boleh dipakai untuk ?sort= beserta ekspresi urutannya. reports.id selalu
+// ditambahkan sebagai urutan terakhir agar hasil (dan cursor) stabil.
+var reportSortFields = map[string][]string{
+	"created_at":   {"reports.created_at"},
+	"updated_at":   {"reports.updated_at"},
+	"endorsements": {"reports.endorsement_count"},
+	// tingkat prioritas dulu agar prioritas manual admin ikut menentukan urutan
+	"priority": {priorityRankSQL, "reports.priority_score"},
+	"id":       nil,
+}
+
+// ListMeta adalah informasi paginasi yang dikirim bersama data
+type ListMeta struct {
+	Total      int64  `json:"total"`
+	Page       int    `json:"page,omitempty"`
+	Limit      int    `json:"limit"`
+	HasMore    bool   `json:"has_more"`
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// listCursor menyimpan posisi baris terakhir (nilai ekspresi sort + id) untuk paginasi cursor
+type listCursor struct {
+	Values []interface{} `json:"v"`
+	ID     uint          `json:"id"`
+}
+
+var errInvalidCursor = errors.New("cursor tidak valid")
+
+func encodeCursor(cur listCursor) string {
+	b, _ := json.Marshal(cur)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func decodeCursor(s string) (listCursor, error) {
+	var cur listCursor
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return cur, errInvalidCursor
+	}
+	if err := json.Unmarshal(b, &cur); err != nil {
+		return cur, errInvalidCursor
+	}
+	return cur, nil
+}
+
+func splitQuery(v string) []string {
+	var out []string
+	for _, part := range strings.Split(v, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}
+
+func parseBoolQuery(v string) (bool, bool) {
+	switch strings.ToLower(v) {
+	case "true", "1", "yes":
+		return true, true
+	case "false", "0", "no":
+		return false, true
+	}
+	return false, false
+}
+
+// kodeWilayahCondition mencocokkan laporan pada wilayah codes. Kode bertingkat,
+// jadi "34.04" juga mencakup semua kecamatan/desa di bawahnya. Kode "none"
+// mencocokkan laporan yang belum punya kode wilayah.
+func kodeWilayahCondition(codes []string) (string, []interface{}) {
+	conds := make([]string, 0, len(codes))
+	args := make([]interface{}, 0, len(codes)*2)
+	for _, code := range codes {
+		if code == "none" {
+			conds = append(conds, "reports.kode_wilayah = ''")
+			continue
+		}
+		code = geo.NormalizeCode(code)
+		conds = append(conds, "reports.kode_wilayah = ? OR reports.kode_wilayah LIKE ?")
+		args = append(args, code, code+".%")
+	}
+	return "(" + strings.Join(conds, " OR ") + ")", args
+}
+
+// reportFilters menerapkan filter yang bisa digabung pada query daftar laporan:
+//
+//	?status=Diajukan,Diproses   ?category_id=1,2     ?wilayah=Sleman
+//	?kode_wilayah=34.04,34.71.01 (none = tanpa kode wilayah)
+//	?from=2025-08-01&to=2025-08-31                   ?assignee=<id admin kategori>
+//	?is_anonymous=true|false    ?has_photo=true|false
+//	?priority=tinggi,darurat    ?min_priority_score=50    ?min_endorsements=5
+func reportFilters(c *gin.Context) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if v := c.Query("from"); v != "" {
+			if from, err := time.ParseInLocation("2006-01-02", v, time.Local); err == nil {
+				db = db.Where("reports.created_at >= ?", from)
+			}
+		}
+		if v := c.Query("to"); v != "" {
+			if to, err := time.ParseInLocation("2006-01-02", v, time.Local); err == nil {
+				db = db.Where("reports.created_at < ?", to.AddDate(0, 0, 1))
+			}
+		}
+		return reportAttributeFilters(c)(db)
+	}
+}
+
+// publicReportFilters adalah reportFilters untuk endpoint lokasi publik. Filter
+// is_anonymous dan assignee diabaikan agar laporan anonim tidak bisa dipilah lalu
+// dilacak lokasinya dari endpoint itu.
+func publicReportFilters(c *gin.Context) func(db *gorm.DB) *gorm.DB {
+	q := c.Request.URL.Query()
+	q.Del("is_anonymous")
+	q.Del("assignee")
+	return reportFilters(savedFilterContext(q.Encode()))
+}
+
+// reportAttributeFilters adalah reportFilters tanpa rentang tanggal from/to,
+// untuk query yang menentukan rentang waktunya sendiri (mis. baseline analitik)
+func reportAttributeFilters(c *gin.Context) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if v := splitQuery(c.Query("status")); len(v) > 0 {
+			db = db.Where("reports.status IN ?", v)
+		}
+		if v := splitQuery(c.Query("category_id")); len(v) > 0 {
+			db = db.Where("reports.category_id IN ?", v)
+		}
+		if v := strings.TrimSpace(c.Query("wilayah")); v != "" {
+			db = db.Where("reports.wilayah LIKE ?", "%"+v+"%")
+		}
+		if v := splitQuery(c.Query("kode_wilayah")); len(v) > 0 {
+			cond, args := kodeWilayahCondition(v)
+			db = db.Where(cond, args...)
+		}
+		if v := c.Query("assignee"); v != "" {
+			// petugas = admin pemilik kategori laporan
+			db = db.Where("reports.category_id IN (SELECT id FROM categories WHERE user_id = ?)", v)
+		}
+		if v, ok := parseBoolQuery(c.Query("is_anonymous")); ok {
+			db = db.Where("reports.is_anonymous = ?", v)
+		}
+		if v, ok := parseBoolQuery(c.Query("has_photo")); ok {
+			exists := "EXISTS (SELECT 1 FROM bukti_fotos WHERE bukti_fotos.report_id = reports.id AND bukti_fotos.deleted_at IS NULL)"
+			if v {
+				db = db.Where(exists)
+			} else {
+				db = db.Where("NOT " + exists)
+			}
+		}
+
+		if minStr := c.Query("min_endorsements"); minStr != "" {
+			if min, err := strconv.Atoi(minStr); err == nil && min > 0 {
+				db = db.Where("reports.endorsement_count >= ?", min)
+			}
+		}
+		if p := c.Query("priority"); p != "" {
+			var levels []string
+			for _, l := range splitQuery(p) {
+				l = strings.ToLower(l)
+				if isValidPriority(l) {
+					levels = append(levels, l)
+				}
+			}
+			if len(levels) > 0 {
+				db = db.Where("reports.priority IN ?", levels)
+			}
+		}
+		if minStr := c.Query("min_priority_score"); minStr != "" {
+			if min, err := strconv.Atoi(minStr); err == nil {
+				db = db.Where("reports.priority_score >= ?", min)
+			}
+		}
+		return db
+	}
+}
+
+// Parameter reportAttributeFilters yang boleh disimpan (mis. di langganan laporan)
+var savedFilterKeys = map[string]bool{
+	"status": true, "category_id": true, "wilayah": true, "kode_wilayah": true, "assignee": true,
+	"is_anonymous": true, "has_photo": true, "min_endorsements": true, "priority": true,
+	"min_priority_score": true,
+}
+
+// parseSavedFilters memeriksa query string filter yang akan disimpan dan mengembalikannya
+// dalam bentuk baku
+func parseSavedFilters(raw string) (string, error) {
+	values, err := url.ParseQuery(strings.TrimPrefix(strings.TrimSpace(raw), "?"))
+	if err != nil {
+		return "", errors.New("format filter tidak valid")
+	}
+	for key := range values {
+		if !savedFilterKeys[key] {
+			return "", errors.New("filter " + key + " tidak didukung")
+		}
+	}
+	return values.Encode(), nil
+}
+
+// savedFilterContext membuat gin.Context dari query string tersimpan agar
+// reportFilters/reportAttributeFilters bisa dipakai di luar request HTTP
+func savedFilterContext(raw string) *gin.Context {
+	return &gin.Context{Request: &http.Request{URL: &url.URL{RawQuery: raw}}}
+}
+
+// reportSort membaca ?sort= dan ?order= (asc/desc, default desc)
+func reportSort(c *gin.Context) (sortKey string, desc bool) {
+	sortKey = "created_at"
+	if _, ok := reportSortFields[c.Query("sort")]; ok {
+		sortKey = c.Query("sort")
+	}
+	return sortKey, strings.ToLower(c.Query("order")) != "asc"
+}
+
+func orderClause(sortKey string, desc bool) string {
+	dir := " DESC"
+	if !desc {
+		dir = " ASC"
+	}
+	var parts []string
+	for _, expr := range reportSortFields[sortKey] {
+		parts = append(parts, expr+dir)
+	}
+	return strings.Join(append(parts, "reports.id"+dir), ", ")
+}
+
+// cursorValues mengambil nilai ekspresi sort dari baris terakhir sebuah halaman
+func cursorValues(sortKey string, r models.Report) []interface{} {
+	switch sortKey {
+	case "created_at":
+		return []interface{}{r.CreatedAt.Format(time.RFC3339Nano)}
+	case "updated_at":
+		return []interface{}{r.UpdatedAt.Format(time.RFC3339Nano)}
+	case "endorsements":
+		return []interface{}{r.EndorsementCount}
+	case "priority":
+		return []interface{}{priorityRank(r.Priority), r.PriorityScore}
+	}
+	return nil
+}
+
+// parseCursorValues memeriksa nilai cursor hasil decode JSON dan mengubahnya ke tipe
+// yang sesuai dengan ekspresi sort
+func parseCursorValues(sortKey string, cur listCursor) ([]interface{}, error) {
+	if len(cur.Values) != len(reportSortFields[sortKey]) {
+		return nil, errInvalidCursor
+	}
+	values := make([]interface{}, len(cur.Values))
+	for i, v := range cur.Values {
+		switch sortKey {
+		case "created_at", "updated_at":
+			str, _ := v.(string)
+			t, err := time.Parse(time.RFC3339Nano, str)
+			if err != nil {
+				return nil, errInvalidCursor
+			}
+			values[i] = t
+		default:
+			n, ok := v.(float64)
+			if !ok || n != float64(int64(n)) {
+				return nil, errInvalidCursor
+			}
+			values[i] = int64(n)
+		}
+	}
+	return values, nil
+}
+
+// keysetCondition membuat kondisi "baris sesudah cursor" untuk urutan exprs + reports.id,
+// yaitu perbandingan leksikografis (e1 < v1) OR (e1 = v1 AND e2 < v2) OR ...
+func keysetCondition(exprs []string, values []interface{}, id uint, desc bool) (string, []interface{}) {
+	op := " < ?"
+	if !desc {
+		op = " > ?"
+	}
+	exprs = append(append([]string{}, exprs...), "reports.id")
+	values = append(append([]interface{}{}, values...), id)
+
+	var conds []string
+	var args []interface{}
+	for i := range exprs {
+		var parts []string
+		for j := 0; j < i; j++ {
+			parts = append(parts, exprs[j]+" = ?")
+			args = append(args, values[j])
+		}
+		parts = append(parts, exprs[i]+op)
+		args = append(args, values[i])
+		conds = append(conds, "("+strings.Join(parts, " AND ")+")")
+	}
+	return "(" + strings.Join(conds, " OR ") + ")", args
+}
+
+// reportBatches membaca hasil query laporan per batch berisi size baris, berurutan
+// menurut reports.id dengan keyset "reports.id > id terakhir batch sebelumnya",
+// lalu memanggil fn untuk tiap batch. idOf mengambil ID laporan dari satu baris.
+func reportBatches[T any](query *gorm.DB, size int, idOf func(T) uint, fn func(rows []T) error) error {
+	var lastID uint
+	for {
+		var rows []T
+		if err := query.Session(&gorm.Session{}).
+			Where("reports.id > ?", lastID).
+			Order("reports.id").
+			Limit(size).
+			Scan(&rows).Error; err != nil {
+			return err
+		}
+		if len(rows) == 0 {
+			return nil
+		}
+		if err := fn(rows); err != nil {
+			return err
+		}
+		if len(rows) < size {
+			return nil
+		}
+		lastID = idOf(rows[len(rows)-1])
+	}
+}
+
+// pagingRequested bernilai true jika klien mengirim parameter paginasi. Tanpa parameter
+// itu daftar laporan dikirim utuh seperti sebelum ada paginasi, agar klien lama tetap jalan.
+func pagingRequested(c *gin.Context) bool {
+	return c.Query("page") != "" || c.Query("limit") != "" || c.Query("cursor") != ""
+}
+
+// reportListQuery menerapkan filter dan urutan tanpa paginasi (mis. untuk GetLatestReports)
+func reportListQuery(c *gin.Context) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		sortKey, desc := reportSort(c)
+		return db.Scopes(reportFilters(c)).Order(orderClause(sortKey, desc))
+	}
+}
+
+// paginateReports menjalankan query daftar laporan dengan filter, urutan dan paginasi.
+// Mendukung ?page=&limit= atau ?cursor=&limit= (cursor diambil dari meta.next_cursor);
+// tanpa parameter paginasi semua laporan dikembalikan (lihat pagingRequested).
+// db hanya berisi kondisi dasar; relasi yang perlu dimuat dikirim lewat preloads.
+func paginateReports(c *gin.Context, db *gorm.DB, reports *[]models.Report, preloads ...string) (ListMeta, error) {
+	db = db.Model(&models.Report{}).Scopes(reportFilters(c))
+	sortKey, desc := reportSort(c)
+	query := db.Order(orderClause(sortKey, desc))
+	for _, p := range preloads {
+		query = query.Preload(p)
+	}
+
+	if !pagingRequested(c) {
+		if err := query.Find(reports).Error; err != nil {
+			return ListMeta{}, err
+		}
+		n := len(*reports)
+		return ListMeta{Total: int64(n), Page: 1, Limit: n}, nil
+	}
+
+	meta := ListMeta{Limit: defaultPageLimit}
+	if l, err := strconv.Atoi(c.Query("limit")); err == nil && l > 0 {
+		meta.Limit = l
+	}
+	if meta.Limit > maxPageLimit {
+		meta.Limit = maxPageLimit
+	}
+	if err := db.Session(&gorm.Session{}).Count(&meta.Total).Error; err != nil {
+		return meta, err
+	}
+
+	if cursorStr := c.Query("cursor"); cursorStr != "" {
+		cur, err := decodeCursor(cursorStr)
+		if err != nil {
+			return meta, err
+		}
+		values, err := parseCursorValues(sortKey, cur)
+		if err != nil {
+			return meta, err
+		}
+		cond, args := keysetCondition(reportSortFields[sortKey], values, cur.ID, desc)
+		query = query.Where(cond, args...)
+	} else {
+		meta.Page = 1
+		if p, err := strconv.Atoi(c.Query("page")); err == nil && p > 1 {
+			meta.Page = p
+		}
+		query = query.Offset((meta.Page - 1) * meta.Limit)
+	}
+
+	// ambil satu baris lebih untuk mengetahui apakah masih ada halaman berikutnya
+	if err := query.Limit(meta.Limit + 1).Find(reports).Error; err != nil {
+		return meta, err
+	}
+	if len(*reports) > meta.Limit {
+		meta.HasMore = true
+		*reports = (*reports)[:meta.Limit]
+	}
+
+	if meta.HasMore && len(*reports) > 0 {
+		last := (*reports)[len(*reports)-1]
+		meta.NextCursor = encodeCursor(listCursor{Values: cursorValues(sortKey, last), ID: last.ID})
+	}
+	return meta, nil
+}
@@ -0,0 +1,159 @@
+package controllers
+
+import (
+	"encoding/base64"
+	"reflect"
+	"testing"
+	"time"
+
+	"project-backend/models"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	created := time.Date(2025, 8, 12, 10, 30, 0, 123456789, time.UTC)
+	report := models.Report{
+		ID:               42,
+		CreatedAt:        created,
+		UpdatedAt:        created.Add(time.Hour),
+		EndorsementCount: 7,
+		Priority:         "tinggi",
+		PriorityScore:    63,
+	}
+
+	tests := []struct {
+		sort string
+		want []interface{}
+	}{
+		{"created_at", []interface{}{created}},
+		{"updated_at", []interface{}{created.Add(time.Hour)}},
+		{"endorsements", []interface{}{int64(7)}},
+		{"priority", []interface{}{int64(3), int64(63)}},
+		{"id", []interface{}{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.sort, func(t *testing.T) {
+			encoded := encodeCursor(listCursor{Values: cursorValues(tt.sort, report), ID: report.ID})
+			cur, err := decodeCursor(encoded)
+			if err != nil {
+				t.Fatalf("decodeCursor: %v", err)
+			}
+			if cur.ID != report.ID {
+				t.Errorf("ID = %d, want %d", cur.ID, report.ID)
+			}
+			got, err := parseCursorValues(tt.sort, cur)
+			if err != nil {
+				t.Fatalf("parseCursorValues: %v", err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("values = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if gt, ok := got[i].(time.Time); ok {
+					if !gt.Equal(tt.want[i].(time.Time)) {
+						t.Errorf("value[%d] = %v, want %v", i, gt, tt.want[i])
+					}
+				} else if got[i] != tt.want[i] {
+					t.Errorf("value[%d] = %v (%T), want %v", i, got[i], got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestDecodeCursorInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		sort string
+		raw  string
+	}{
+		{"bukan base64", "created_at", "***"},
+		{"bukan json", "created_at", base64.RawURLEncoding.EncodeToString([]byte("not-json"))},
+		{"jumlah nilai salah", "priority", encodeCursor(listCursor{Values: []interface{}{3}, ID: 1})},
+		{"tanggal rusak", "created_at", encodeCursor(listCursor{Values: []interface{}{"kemarin"}, ID: 1})},
+		{"angka berupa teks", "endorsements", encodeCursor(listCursor{Values: []interface{}{"5"}, ID: 1})},
+		{"angka pecahan", "endorsements", encodeCursor(listCursor{Values: []interface{}{1.5}, ID: 1})},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cur, err := decodeCursor(tt.raw)
+			if err == nil {
+				_, err = parseCursorValues(tt.sort, cur)
+			}
+			if err != errInvalidCursor {
+				t.Errorf("err = %v, want errInvalidCursor", err)
+			}
+		})
+	}
+}
+
+func TestKeysetCondition(t *testing.T) {
+	tests := []struct {
+		name     string
+		exprs    []string
+		values   []interface{}
+		desc     bool
+		wantSQL  string
+		wantArgs []interface{}
+	}{
+		{
+			name:     "hanya id",
+			desc:     true,
+			wantSQL:  "((reports.id < ?))",
+			wantArgs: []interface{}{uint(9)},
+		},
+		{
+			name:     "satu kolom naik",
+			exprs:    []string{"reports.endorsement_count"},
+			values:   []interface{}{int64(5)},
+			wantSQL:  "((reports.endorsement_count > ?) OR (reports.endorsement_count = ? AND reports.id > ?))",
+			wantArgs: []interface{}{int64(5), int64(5), uint(9)},
+		},
+		{
+			name:   "prioritas",
+			exprs:  []string{"rank", "reports.priority_score"},
+			values: []interface{}{int64(4), int64(80)},
+			desc:   true,
+			wantSQL: "((rank < ?) OR (rank = ? AND reports.priority_score < ?) OR " +
+				"(rank = ? AND reports.priority_score = ? AND reports.id < ?))",
+			wantArgs: []interface{}{int64(4), int64(4), int64(80), int64(4), int64(80), uint(9)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql, args := keysetCondition(tt.exprs, tt.values, 9, tt.desc)
+			if sql != tt.wantSQL {
+				t.Errorf("sql = %q\nwant  %q", sql, tt.wantSQL)
+			}
+			if !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("args = %v, want %v", args, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestOrderClause(t *testing.T) {
+	tests := []struct {
+		sort string
+		desc bool
+		want string
+	}{
+		{"created_at", true, "reports.created_at DESC, reports.id DESC"},
+		{"endorsements", false, "reports.endorsement_count ASC, reports.id ASC"},
+		{"priority", true, priorityRankSQL + " DESC, reports.priority_score DESC, reports.id DESC"},
+		{"id", false, "reports.id ASC"},
+	}
+	for _, tt := range tests {
+		if got := orderClause(tt.sort, tt.desc); got != tt.want {
+			t.Errorf("orderClause(%q, %v) = %q, want %q", tt.sort, tt.desc, got, tt.want)
+		}
+	}
+}
+
+func TestPriorityRank(t *testing.T) {
+	tests := map[string]int{"darurat": 4, "tinggi": 3, "sedang": 2, "rendah": 1, "": 1}
+	for level, want := range tests {
+		if got := priorityRank(level); got != want {
+			t.Errorf("priorityRank(%q) = %d, want %d", level, got, want)
+		}
+	}
+}
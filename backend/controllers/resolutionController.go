@@ -0,0 +1,301 @@
+package controllers
+
+import (
+	"math"
+	"net/http"
+	"project-backend/config"
+	"project-backend/models"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Status akhir: setelah status ini laporan tidak lagi menunggu penanganan
+var terminalStatuses = map[string]bool{"Selesai": true, "Ditolak": true, "Dibatalkan": true}
+
+// reportTimeline adalah durasi-durasi satu laporan yang dihitung dari Riwayat
+type reportTimeline struct {
+	FirstResponse *time.Duration           // dibuat -> status pertama selain Diajukan
+	ToResolve     *time.Duration           // dibuat -> pertama kali Selesai
+	InStatus      map[string]time.Duration // lama tiap status (hanya segmen yang sudah berakhir)
+	Resolved      bool
+	Reopened      bool
+}
+
+// buildTimeline menyusun durasi dari riwayat yang sudah diurutkan menurut waktu.
+// Baris riwayat yang statusnya sama dengan sebelumnya (mis. perubahan prioritas)
+// tidak dianggap perpindahan status.
+func buildTimeline(createdAt time.Time, reopenCount int, riwayat []models.Riwayat) reportTimeline {
+	tl := reportTimeline{InStatus: map[string]time.Duration{}, Reopened: reopenCount > 0}
+
+	status, since := "Diajukan", createdAt
+	for _, r := range riwayat {
+		if r.Status == status || r.Status == "" {
+			continue
+		}
+		if r.Tanggal.After(since) {
+			tl.InStatus[status] += r.Tanggal.Sub(since)
+		}
+		if tl.FirstResponse == nil && status == "Diajukan" {
+			d := r.Tanggal.Sub(createdAt)
+			tl.FirstResponse = &d
+		}
+		if tl.ToResolve == nil && r.Status == "Selesai" {
+			d := r.Tanggal.Sub(createdAt)
+			tl.ToResolve = &d
+			tl.Resolved = true
+		}
+		status, since = r.Status, r.Tanggal
+	}
+	return tl
+}
+
+// durationStats adalah ringkasan durasi dalam jam
+type durationStats struct {
+	Count  int     `json:"count"`
+	Median float64 `json:"median_hours"`
+	P90    float64 `json:"p90_hours"`
+	Avg    float64 `json:"avg_hours"`
+}
+
+// percentile dengan interpolasi linear; values harus sudah terurut
+func percentile(values []float64, p float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	pos := p * float64(len(values)-1)
+	lo := int(math.Floor(pos))
+	hi := int(math.Ceil(pos))
+	return values[lo] + (values[hi]-values[lo])*(pos-float64(lo))
+}
+
+func summarizeDurations(ds []time.Duration) durationStats {
+	if len(ds) == 0 {
+		return durationStats{}
+	}
+	hours := make([]float64, len(ds))
+	var sum float64
+	for i, d := range ds {
+		hours[i] = d.Hours()
+		sum += hours[i]
+	}
+	sort.Float64s(hours)
+	round := func(v float64) float64 { return math.Round(v*100) / 100 }
+	return durationStats{
+		Count:  len(hours),
+		Median: round(percentile(hours, 0.5)),
+		P90:    round(percentile(hours, 0.9)),
+		Avg:    round(sum / float64(len(hours))),
+	}
+}
+
+// metricsGroup mengumpulkan durasi laporan dalam satu kelompok
+type metricsGroup struct {
+	Key   string
+	Label string
+
+	reports       int
+	resolved      int
+	reopened      int
+	firstResponse []time.Duration
+	toResolve     []time.Duration
+	inStatus      map[string][]time.Duration
+}
+
+func (g *metricsGroup) add(tl reportTimeline) {
+	g.reports++
+	if tl.FirstResponse != nil {
+		g.firstResponse = append(g.firstResponse, *tl.FirstResponse)
+	}
+	if tl.Resolved {
+		g.resolved++
+		g.toResolve = append(g.toResolve, *tl.ToResolve)
+		if tl.Reopened {
+			g.reopened++
+		}
+	}
+	if g.inStatus == nil {
+		g.inStatus = map[string][]time.Duration{}
+	}
+	for status, d := range tl.InStatus {
+		g.inStatus[status] = append(g.inStatus[status], d)
+	}
+}
+
+func (g *metricsGroup) result() gin.H {
+	inStatus := gin.H{}
+	for status, ds := range g.inStatus {
+		inStatus[status] = summarizeDurations(ds)
+	}
+	reopenRate := 0.0
+	if g.resolved > 0 {
+		reopenRate = math.Round(float64(g.reopened)/float64(g.resolved)*10000) / 10000
+	}
+	return gin.H{
+		"key":             g.Key,
+		"label":           g.Label,
+		"reports":         g.reports,
+		"resolved":        g.resolved,
+		"reopened":        g.reopened,
+		"reopen_rate":     reopenRate,
+		"first_response":  summarizeDurations(g.firstResponse),
+		"time_to_resolve": summarizeDurations(g.toResolve),
+		"time_in_status":  inStatus,
+	}
+}
+
+// metricsRow adalah kolom laporan yang dibutuhkan untuk pengelompokan
+type metricsRow struct {
+	ID          uint
+	CreatedAt   time.Time
+	ReopenCount int
+	CategoryID  *uint
+	Kategori    string
+	KodeWilayah string
+	OfficerID   *uint
+	Officer     string
+}
+
+func metricsRowID(r metricsRow) uint { return r.ID }
+
+// GET /admin/analytics/resolution?group_by=category|region|officer|period&period=month&region_level=kecamatan
+// Metrik kecepatan penanganan dari perpindahan status di Riwayat: waktu respons pertama,
+// lama di tiap status, waktu hingga Selesai dan tingkat pembukaan kembali, dengan median
+// dan p90 (jam). Petugas adalah admin pemilik kategori laporan. Filter daftar laporan
+// berlaku dan rentang from/to mengacu pada tanggal laporan dibuat.
+func GetResolutionMetrics(c *gin.Context) {
+	groupBy := c.DefaultQuery("group_by", "category")
+	if groupBy != "category" && groupBy != "region" && groupBy != "officer" && groupBy != "period" {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "group_by harus category, region, officer atau period"})
+		return
+	}
+	period := c.DefaultQuery("period", "month")
+	if period != "week" && period != "month" {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid period"})
+		return
+	}
+	regionSegments := 2 // kabupaten/kota
+	switch c.DefaultQuery("region_level", "kabupaten") {
+	case "provinsi":
+		regionSegments = 1
+	case "kecamatan":
+		regionSegments = 3
+	case "desa":
+		regionSegments = 4
+	}
+
+	keyOf := func(r metricsRow) (string, string) {
+		switch groupBy {
+		case "region":
+			if r.KodeWilayah == "" {
+				return "", "Tanpa wilayah"
+			}
+			parts := strings.Split(r.KodeWilayah, ".")
+			if len(parts) > regionSegments {
+				parts = parts[:regionSegments]
+			}
+			return strings.Join(parts, "."), ""
+		case "officer":
+			if r.OfficerID == nil {
+				return "", "Tanpa petugas"
+			}
+			return strconv.FormatUint(uint64(*r.OfficerID), 10), r.Officer
+		case "period":
+			if period == "week" {
+				y, w := r.CreatedAt.ISOWeek()
+				return strconv.Itoa(y) + "-W" + twoDigits(w), ""
+			}
+			return r.CreatedAt.Format("2006-01"), ""
+		}
+		if r.CategoryID == nil {
+			return "", "Tanpa kategori"
+		}
+		return strconv.FormatUint(uint64(*r.CategoryID), 10), r.Kategori
+	}
+
+	overall := &metricsGroup{Key: "all", Label: "Semua laporan"}
+	groups := map[string]*metricsGroup{}
+
+	query := config.DB.Model(&models.Report{}).
+		Select("reports.id, reports.created_at, reports.reopen_count, reports.category_id, categories.name AS kategori, reports.kode_wilayah, categories.user_id AS officer_id, users.name AS officer").
+		Joins("LEFT JOIN categories ON categories.id = reports.category_id").
+		Joins("LEFT JOIN users ON users.id = categories.user_id").
+		Scopes(adminScope(c.GetString("role"), c.GetUint("userID")), reportFilters(c))
+
+	err := reportBatches(query, 500, metricsRowID, func(rows []metricsRow) error {
+		ids := make([]uint, len(rows))
+		for i, r := range rows {
+			ids[i] = r.ID
+		}
+		var riwayat []models.Riwayat
+		if err := config.DB.Where("report_id IN ?", ids).Order("tanggal, id").Find(&riwayat).Error; err != nil {
+			return err
+		}
+		byReport := map[uint][]models.Riwayat{}
+		for _, r := range riwayat {
+			byReport[r.ReportID] = append(byReport[r.ReportID], r)
+		}
+
+		for _, r := range rows {
+			tl := buildTimeline(r.CreatedAt, r.ReopenCount, byReport[r.ID])
+			overall.add(tl)
+			key, label := keyOf(r)
+			g, ok := groups[key]
+			if !ok {
+				g = &metricsGroup{Key: key, Label: label}
+				groups[key] = g
+			}
+			g.add(tl)
+		}
+		return nil
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": "Gagal menghitung metrik penanganan"})
+		return
+	}
+
+	// nama wilayah diambil dari tabel regions
+	if groupBy == "region" {
+		var codes []string
+		for key := range groups {
+			if key != "" {
+				codes = append(codes, key)
+			}
+		}
+		var regions []models.Region
+		if len(codes) > 0 {
+			config.DB.Where("code IN ?", codes).Find(&regions)
+		}
+		for _, r := range regions {
+			groups[r.Code].Label = r.Name
+		}
+	}
+
+	keys := make([]string, 0, len(groups))
+	for key := range groups {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	result := make([]gin.H, 0, len(keys))
+	for _, key := range keys {
+		result = append(result, groups[key].result())
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": gin.H{
+			"group_by": groupBy,
+			"overall":  overall.result(),
+			"groups":   result,
+		},
+	})
+}
+
+func twoDigits(n int) string {
+	if n < 10 {
+		return "0" + strconv.Itoa(n)
+	}
+	return strconv.Itoa(n)
+}
@@ -0,0 +1,264 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"project-backend/audit"
+	"project-backend/config"
+	"project-backend/models"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+func formatUintPtr(v *uint) string {
+	if v == nil {
+		return ""
+	}
+	return strconv.FormatUint(uint64(*v), 10)
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+// diffReport membandingkan field laporan yang bisa diubah dan mengembalikan daftar perubahannya
+func diffReport(old, updated models.Report) models.FieldChanges {
+	var changes models.FieldChanges
+	add := func(field, o, n string) {
+		if o != n {
+			changes = append(changes, models.FieldChange{Field: field, Old: o, New: n})
+		}
+	}
+
+	add("title", old.Title, updated.Title)
+	add("description", old.Description, updated.Description)
+	add("wilayah", old.Wilayah, updated.Wilayah)
+	add("lokasi", old.Lokasi, updated.Lokasi)
+	add("latitude", formatFloat(old.Latitude), formatFloat(updated.Latitude))
+	add("longitude", formatFloat(old.Longitude), formatFloat(updated.Longitude))
+	add("category_id", formatUintPtr(old.CategoryID), formatUintPtr(updated.CategoryID))
+	add("is_anonymous", strconv.FormatBool(old.IsAnonymous), strconv.FormatBool(updated.IsAnonymous))
+	add("status", old.Status, updated.Status)
+	return changes
+}
+
+// setReportField mengisi satu field laporan dari nilai string yang tersimpan di revisi
+func setReportField(report *models.Report, field, value string) error {
+	switch field {
+	case "title":
+		report.Title = value
+	case "description":
+		report.Description = value
+	case "wilayah":
+		report.Wilayah = value
+	case "lokasi":
+		report.Lokasi = value
+	case "latitude", "longitude":
+		v, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return err
+		}
+		if field == "latitude" {
+			report.Latitude = v
+		} else {
+			report.Longitude = v
+		}
+	case "category_id":
+		if value == "" {
+			report.CategoryID = nil
+			return nil
+		}
+		v, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			return err
+		}
+		id := uint(v)
+		report.CategoryID = &id
+	case "is_anonymous":
+		report.IsAnonymous = value == "true"
+	default:
+		return fmt.Errorf("field %s tidak dapat dikembalikan", field)
+	}
+	return nil
+}
+
+// recordRevision menyimpan satu versi baru laporan. Tidak menyimpan apa pun jika tidak ada perubahan.
+func recordRevision(tx *gorm.DB, reportID, editorID uint, editorRole, action string, changes models.FieldChanges) error {
+	if len(changes) == 0 {
+		return nil
+	}
+
+	// kunci baris laporan agar dua perubahan bersamaan tidak mendapat nomor versi
+	// yang sama; indeks unik (report_id, version) menjadi pengaman terakhir
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&models.Report{}, reportID).Error; err != nil {
+		return err
+	}
+	var last int
+	if err := tx.Model(&models.ReportRevision{}).Where("report_id = ?", reportID).
+		Select("COALESCE(MAX(version), 0)").Scan(&last).Error; err != nil {
+		return err
+	}
+
+	return tx.Create(&models.ReportRevision{
+		ReportID:   reportID,
+		Version:    last + 1,
+		EditorID:   editorID,
+		EditorRole: editorRole,
+		Action:     action,
+		Changes:    changes,
+	}).Error
+}
+
+// GET /reports/admin/:id/revisions -> admin melihat seluruh versi perubahan laporan
+// GET /reports/:id/revisions       -> pelapor melihat perubahan laporannya sendiri
+func GetReportRevisions(c *gin.Context) {
+	var report models.Report
+	if err := config.DB.Select("id", "user_id").First(&report, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"message": "Laporan tidak ditemukan"})
+		return
+	}
+
+	role := c.GetString("role")
+	isAdmin := role == "admin" || role == "superadmin" || role == "kategori_admin"
+	if !isAdmin && report.UserID != c.GetUint("userID") {
+		c.JSON(http.StatusForbidden, gin.H{"message": "Akses ditolak"})
+		return
+	}
+
+	var revisions []models.ReportRevision
+	if err := config.DB.Preload("Editor").
+		Where("report_id = ?", report.ID).
+		Order("version DESC").
+		Find(&revisions).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": "Gagal mengambil riwayat perubahan"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": revisions})
+}
+
+var errNothingToRevert = errors.New("tidak ada perubahan setelah versi tersebut")
+
+// POST /reports/admin/:id/revisions/:version/revert -> superadmin mengembalikan isi laporan
+// ke keadaan setelah versi tertentu. Status laporan tidak ikut dikembalikan karena
+// perubahan status punya alurnya sendiri (Riwayat).
+func RevertReportRevision(c *gin.Context) {
+	if c.GetString("role") != "superadmin" {
+		c.JSON(http.StatusForbidden, gin.H{"message": "Superadmin access required"})
+		return
+	}
+
+	version, err := strconv.Atoi(c.Param("version"))
+	if err != nil || version < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Versi tidak valid"})
+		return
+	}
+
+	var report models.Report
+	if err := config.DB.First(&report, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"message": "Laporan tidak ditemukan"})
+		return
+	}
+
+	if version > 0 {
+		var target int64
+		config.DB.Model(&models.ReportRevision{}).Where("report_id = ? AND version = ?", report.ID, version).Count(&target)
+		if target == 0 {
+			c.JSON(http.StatusNotFound, gin.H{"message": "Versi laporan tidak ditemukan"})
+			return
+		}
+	}
+
+	// revisi yang dibuat setelah versi tujuan, dibatalkan dari yang terbaru
+	var later []models.ReportRevision
+	config.DB.Where("report_id = ? AND version > ?", report.ID, version).Order("version DESC").Find(&later)
+
+	old := report
+	var restorePhotos, removePhotos []string
+	for _, rev := range later {
+		for _, ch := range rev.Changes {
+			switch ch.Field {
+			case "status":
+				continue
+			case "bukti_foto":
+				if ch.New != "" {
+					removePhotos = append(removePhotos, ch.New)
+				} else {
+					restorePhotos = append(restorePhotos, ch.Old)
+				}
+			default:
+				if err := setReportField(&report, ch.Field, ch.Old); err != nil {
+					c.JSON(http.StatusInternalServerError, gin.H{"message": "Gagal membaca revisi", "error": err.Error()})
+					return
+				}
+			}
+		}
+	}
+
+	// kode wilayah mengikuti koordinat hasil revert; koordinat lama tetap dipulihkan
+	// meskipun kini di luar wilayah layanan
+	_ = applyRegion(&report)
+
+	changes := diffReport(old, report)
+	for _, path := range removePhotos {
+		changes = append(changes, models.FieldChange{Field: "bukti_foto", Old: path})
+	}
+	for _, path := range restorePhotos {
+		changes = append(changes, models.FieldChange{Field: "bukti_foto", New: path})
+	}
+
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		if len(changes) == 0 {
+			return errNothingToRevert
+		}
+		if cols := changedColumns(changes); len(cols) > 0 {
+			if err := tx.Model(&report).Select(cols).Updates(&report).Error; err != nil {
+				return err
+			}
+		}
+		if len(removePhotos) > 0 {
+			if err := tx.Where("report_id = ? AND photo_url IN ?", report.ID, removePhotos).Delete(&models.BuktiFoto{}).Error; err != nil {
+				return err
+			}
+		}
+		if len(restorePhotos) > 0 {
+			if err := tx.Unscoped().Model(&models.BuktiFoto{}).
+				Where("report_id = ? AND photo_url IN ?", report.ID, restorePhotos).
+				Update("deleted_at", nil).Error; err != nil {
+				return err
+			}
+		}
+		if err := recordRevision(tx, report.ID, c.GetUint("userID"), c.GetString("role"), "revert", changes); err != nil {
+			return err
+		}
+		if err := enqueueSearchIndex(tx, report.ID); err != nil {
+			return err
+		}
+		return tx.Create(&models.Riwayat{
+			ReportID:  report.ID,
+			Status:    report.Status,
+			Tanggal:   time.Now(),
+			Deskripsi: fmt.Sprintf("Isi laporan dikembalikan ke versi %d oleh superadmin", version),
+		}).Error
+	})
+	if err == errNothingToRevert {
+		c.JSON(http.StatusOK, gin.H{"message": "Laporan sudah sesuai dengan versi tersebut", "data": report})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": "Gagal mengembalikan laporan"})
+		return
+	}
+
+	audit.SetAfter(c, gin.H{"version": version, "changes": changes})
+
+	updatePriority(&report, "")
+	config.DB.Preload("BuktiFotos").First(&report, report.ID)
+
+	c.JSON(http.StatusOK, gin.H{"message": fmt.Sprintf("Laporan dikembalikan ke versi %d", version), "data": report})
+}
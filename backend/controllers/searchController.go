@@ -0,0 +1,128 @@
+package controllers
+
+import (
+	"net/http"
+	"project-backend/config"
+	"project-backend/models"
+	"project-backend/search"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Panjang potongan teks di kiri/kanan kata yang cocok
+const snippetRadius = 80
+
+// GET /reports/admin/search?q=jalan+berlubang&page=1&limit=20
+// Pencarian kata kunci di judul, deskripsi, lokasi, komentar dan tindak lanjut laporan.
+func SearchReports(c *gin.Context) {
+	q := strings.TrimSpace(c.Query("q"))
+	if q == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Kata kunci pencarian tidak boleh kosong"})
+		return
+	}
+	if search.Default() == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"message": "Pencarian belum tersedia"})
+		return
+	}
+
+	terms := search.Analyze(q)
+	if len(terms) == 0 {
+		c.JSON(http.StatusOK, gin.H{"data": []interface{}{}, "meta": ListMeta{Limit: defaultPageLimit}})
+		return
+	}
+
+	meta := ListMeta{Page: 1, Limit: defaultPageLimit}
+	if p, err := strconv.Atoi(c.Query("page")); err == nil && p > 1 {
+		meta.Page = p
+	}
+	if l, err := strconv.Atoi(c.Query("limit")); err == nil && l > 0 && l <= maxPageLimit {
+		meta.Limit = l
+	}
+
+	hits, total, err := search.Default().Search(search.Query{
+		Terms:  terms,
+		Scope:  adminScope(c.GetString("role"), c.GetUint("userID")),
+		Limit:  meta.Limit,
+		Offset: (meta.Page - 1) * meta.Limit,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": "Gagal melakukan pencarian", "error": err.Error()})
+		return
+	}
+	meta.Total = total
+	meta.HasMore = int64(meta.Page*meta.Limit) < total
+
+	ids := make([]uint, len(hits))
+	for i, h := range hits {
+		ids[i] = h.ReportID
+	}
+	var reports []models.Report
+	config.DB.Preload("User").Preload("Category").Preload("Comments").Preload("FollowUps").
+		Where("id IN ?", ids).Find(&reports)
+	byID := make(map[uint]models.Report, len(reports))
+	for _, r := range reports {
+		byID[r.ID] = r
+	}
+
+	type result struct {
+		Report     models.Report     `json:"report"`
+		Score      float64           `json:"score"`
+		Highlights map[string]string `json:"highlights"`
+		Matches    []string          `json:"matched_discussion,omitempty"`
+	}
+	results := make([]result, 0, len(hits))
+	for _, h := range hits {
+		report, ok := byID[h.ReportID]
+		if !ok {
+			continue
+		}
+		res := result{Score: h.Score, Highlights: map[string]string{}}
+		if s, ok := search.Highlight(report.Title, terms, 0); ok {
+			res.Highlights["title"] = s
+		}
+		if s, ok := search.Highlight(report.Description, terms, snippetRadius); ok {
+			res.Highlights["description"] = s
+		}
+		if s, ok := search.Highlight(report.Lokasi, terms, snippetRadius); ok {
+			res.Highlights["lokasi"] = s
+		}
+		for _, cm := range report.Comments {
+			if s, ok := search.Highlight(cm.Text, terms, snippetRadius); ok {
+				res.Matches = append(res.Matches, s)
+			}
+		}
+		for _, f := range report.FollowUps {
+			if s, ok := search.Highlight(f.Deskripsi, terms, snippetRadius); ok {
+				res.Matches = append(res.Matches, s)
+			}
+		}
+
+		// detail diskusi tidak perlu ikut dikirim, cukup potongan yang cocok
+		report.Comments = nil
+		report.FollowUps = nil
+		if report.IsAnonymous {
+			report.User.Name = "Anonim"
+			report.User.Email = ""
+		}
+		res.Report = report
+		results = append(results, res)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": results, "meta": meta, "terms": terms})
+}
+
+// POST /reports/admin/search/reindex -> superadmin membangun ulang indeks pencarian
+func ReindexSearch(c *gin.Context) {
+	if c.GetString("role") != "superadmin" {
+		c.JSON(http.StatusForbidden, gin.H{"message": "Superadmin access required"})
+		return
+	}
+	total, err := search.ReindexAll()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": "Gagal membangun ulang indeks", "error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Indeks pencarian dibangun ulang", "total": total})
+}
@@ -0,0 +1,421 @@
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	htmltemplate "html/template"
+	"net/http"
+	"net/mail"
+	"project-backend/audit"
+	"project-backend/config"
+	"project-backend/export"
+	"project-backend/mailer"
+	"project-backend/models"
+	"project-backend/pdf"
+	"project-backend/scheduler"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// Jumlah laporan melewati SLA yang dicantumkan di badan email
+const subscriptionOverdueList = 20
+
+var subscriptionFormats = map[string]bool{export.FormatCSV: true, export.FormatXLSX: true, "pdf": true}
+
+var frequencyNames = map[string]string{
+	scheduler.Daily:   "harian",
+	scheduler.Weekly:  "mingguan",
+	scheduler.Monthly: "bulanan",
+}
+
+var errOwnerInactive = errors.New("pemilik langganan sudah tidak aktif atau bukan admin")
+
+func subscriptionSchedule(sub models.ReportSubscription) scheduler.Schedule {
+	return scheduler.Schedule{Frequency: sub.Frequency, Hour: sub.Hour, Weekday: sub.Weekday, Day: sub.DayOfMonth}
+}
+
+type subscriptionInput struct {
+	Name       string `json:"name" binding:"required"`
+	Frequency  string `json:"frequency" binding:"required"`
+	Hour       int    `json:"hour"`
+	Weekday    int    `json:"weekday"`
+	DayOfMonth int    `json:"day_of_month"`
+	Format     string `json:"format"`
+	Filters    string `json:"filters"`
+	Recipients string `json:"recipients"`
+	Active     *bool  `json:"active"`
+}
+
+// apply memvalidasi input dan menyalinnya ke sub; pesan error siap dikirim ke klien
+func (in subscriptionInput) apply(sub *models.ReportSubscription) error {
+	sub.Name = strings.TrimSpace(in.Name)
+	sub.Frequency = in.Frequency
+	sub.Hour, sub.Weekday, sub.DayOfMonth = in.Hour, in.Weekday, in.DayOfMonth
+	if sub.Frequency == scheduler.Monthly && sub.DayOfMonth == 0 {
+		sub.DayOfMonth = 1
+	}
+	if err := subscriptionSchedule(*sub).Validate(); err != nil {
+		return err
+	}
+
+	sub.Format = strings.ToLower(in.Format)
+	if sub.Format == "" {
+		sub.Format = export.FormatCSV
+	}
+	if !subscriptionFormats[sub.Format] {
+		return errors.New("format harus csv, xlsx atau pdf")
+	}
+
+	filters, err := parseSavedFilters(in.Filters)
+	if err != nil {
+		return err
+	}
+	sub.Filters = filters
+
+	var recipients []string
+	for _, r := range splitQuery(in.Recipients) {
+		addr, err := mail.ParseAddress(r)
+		if err != nil {
+			return errors.New("alamat email penerima tidak valid: " + r)
+		}
+		recipients = append(recipients, addr.Address)
+	}
+	sub.Recipients = strings.Join(recipients, ",")
+
+	if in.Active != nil {
+		sub.Active = *in.Active
+	}
+	return nil
+}
+
+// findOwnSubscription memuat langganan milik admin yang login (superadmin boleh semua)
+func findOwnSubscription(c *gin.Context) (models.ReportSubscription, bool) {
+	var sub models.ReportSubscription
+	if err := config.DB.First(&sub, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"message": "Langganan tidak ditemukan"})
+		return sub, false
+	}
+	if c.GetString("role") != "superadmin" && sub.UserID != c.GetUint("userID") {
+		c.JSON(http.StatusForbidden, gin.H{"message": "Akses ditolak"})
+		return sub, false
+	}
+	return sub, true
+}
+
+// GET /admin/subscriptions -> langganan milik admin (superadmin melihat semua)
+func GetSubscriptions(c *gin.Context) {
+	db := config.DB.Preload("User")
+	if c.GetString("role") != "superadmin" {
+		db = db.Where("user_id = ?", c.GetUint("userID"))
+	}
+	var subs []models.ReportSubscription
+	if err := db.Order("id").Find(&subs).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": "Gagal mengambil langganan"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": subs})
+}
+
+// POST /admin/subscriptions
+// body: {"name": "Mingguan Jalan", "frequency": "weekly", "weekday": 1, "hour": 7,
+// "format": "pdf", "filters": "category_id=2", "recipients": "kadis@example.go.id"}
+func CreateSubscription(c *gin.Context) {
+	var input subscriptionInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Nama dan frekuensi wajib diisi"})
+		return
+	}
+	sub := models.ReportSubscription{UserID: c.GetUint("userID"), Active: true}
+	if err := input.apply(&sub); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+	sub.NextRunAt = subscriptionSchedule(sub).Next(time.Now())
+
+	if err := config.DB.Create(&sub).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": "Gagal menyimpan langganan"})
+		return
+	}
+	audit.SetTargetID(c, sub.ID)
+	audit.SetAfter(c, sub)
+
+	c.JSON(http.StatusCreated, gin.H{"message": "Langganan berhasil dibuat", "data": sub})
+}
+
+// PUT /admin/subscriptions/:id
+func UpdateSubscription(c *gin.Context) {
+	sub, ok := findOwnSubscription(c)
+	if !ok {
+		return
+	}
+	var input subscriptionInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Nama dan frekuensi wajib diisi"})
+		return
+	}
+	audit.SetBefore(c, sub)
+	if err := input.apply(&sub); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+	sub.NextRunAt = subscriptionSchedule(sub).Next(time.Now())
+
+	if err := config.DB.Save(&sub).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": "Gagal menyimpan langganan"})
+		return
+	}
+	audit.SetAfter(c, sub)
+
+	c.JSON(http.StatusOK, gin.H{"message": "Langganan berhasil diperbarui", "data": sub})
+}
+
+// DELETE /admin/subscriptions/:id
+func DeleteSubscription(c *gin.Context) {
+	sub, ok := findOwnSubscription(c)
+	if !ok {
+		return
+	}
+	audit.SetBefore(c, sub)
+	if err := config.DB.Delete(&sub).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": "Gagal menghapus langganan"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Langganan berhasil dihapus"})
+}
+
+// POST /admin/subscriptions/:id/send -> kirim sekarang untuk periode yang berakhir hari ini,
+// tanpa mengubah jadwal berikutnya
+func SendSubscriptionNow(c *gin.Context) {
+	sub, ok := findOwnSubscription(c)
+	if !ok {
+		return
+	}
+	if err := deliverSubscription(sub, time.Now()); err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{"message": "Gagal mengirim langganan: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Langganan berhasil dikirim"})
+}
+
+// RunDueSubscriptions mengirim semua langganan yang jadwalnya sudah lewat. Dipanggil
+// berkala oleh scheduler. Setiap langganan diklaim dengan menggeser next_run_at secara
+// atomik sehingga tidak terkirim dua kali walau ada beberapa proses server.
+func RunDueSubscriptions(ctx context.Context, now time.Time) error {
+	db := config.DB.WithContext(ctx)
+	var subs []models.ReportSubscription
+	if err := db.Where("active = ? AND next_run_at <= ?", true, now).Find(&subs).Error; err != nil {
+		return err
+	}
+	for _, sub := range subs {
+		// batas waktu job habis: langganan yang belum diklaim tetap jatuh tempo untuk run berikutnya
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		next := subscriptionSchedule(sub).Next(now)
+		claim := db.Model(&models.ReportSubscription{}).
+			Where("id = ? AND next_run_at = ?", sub.ID, sub.NextRunAt).
+			Update("next_run_at", next)
+		if claim.Error != nil {
+			return claim.Error
+		}
+		if claim.RowsAffected == 0 {
+			continue
+		}
+
+		// periode mengikuti jadwal yang seharusnya, bukan waktu tick, agar kiriman
+		// yang terlambat tetap merangkum periode yang benar
+		updates := map[string]interface{}{"last_run_at": now, "last_error": ""}
+		err := deliverSubscription(sub, sub.NextRunAt)
+		if err != nil {
+			updates["last_error"] = err.Error()
+			if errors.Is(err, errOwnerInactive) {
+				updates["active"] = false
+			}
+		}
+		// hasil tetap dicatat walaupun ctx sudah dibatalkan
+		config.DB.Model(&models.ReportSubscription{}).Where("id = ?", sub.ID).UpdateColumns(updates)
+	}
+	return nil
+}
+
+// subscriptionSummary adalah isi badan email langganan
+type subscriptionSummary struct {
+	AppName   string
+	Name      string
+	Frequency string
+	Period    string
+	Scope     string
+	New       int64
+	Resolved  int64
+	Overdue   int64
+	Items     []overdueItem
+	More      int64
+	Format    string
+	ManageURL string
+}
+
+type overdueItem struct {
+	TrackingID string
+	Title      string
+	Status     string
+	Days       int
+	URL        string
+}
+
+// deliverSubscription menyusun rekap untuk periode yang berakhir pada at lalu mengirimnya
+func deliverSubscription(sub models.ReportSubscription, at time.Time) error {
+	var owner models.User
+	if err := config.DB.First(&owner, sub.UserID).Error; err != nil || !owner.IsActive ||
+		(owner.Role != "admin" && owner.Role != "superadmin" && owner.Role != "kategori_admin") {
+		return errOwnerInactive
+	}
+	recipients := splitQuery(sub.Recipients)
+	if len(recipients) == 0 {
+		recipients = []string{owner.Email}
+	}
+
+	now := time.Now()
+	from, to := subscriptionSchedule(sub).Window(at)
+	filters := savedFilterContext(sub.Filters)
+	scope := func(db *gorm.DB) *gorm.DB {
+		return db.Scopes(adminScope(owner.Role, owner.ID), reportAttributeFilters(filters))
+	}
+	reports := func() *gorm.DB { return config.DB.Model(&models.Report{}).Scopes(scope) }
+	overdueCond, overdueArgs := overdueCondition(now)
+
+	summary := subscriptionSummary{
+		AppName:   config.AppName,
+		Name:      sub.Name,
+		Frequency: frequencyNames[sub.Frequency],
+		Period:    pdf.PeriodName(from, to),
+		Scope:     adminScopeLabel(owner.Role, owner.ID),
+		Format:    strings.ToUpper(sub.Format),
+		ManageURL: config.FrontendURL + "/admin",
+	}
+	if err := reports().Where("reports.created_at >= ? AND reports.created_at < ?", from, to).Count(&summary.New).Error; err != nil {
+		return err
+	}
+	if err := reports().Where("reports.status = ? AND reports.resolved_at >= ? AND reports.resolved_at < ?", "Selesai", from, to).
+		Count(&summary.Resolved).Error; err != nil {
+		return err
+	}
+	if err := reports().Where(overdueCond, overdueArgs...).Count(&summary.Overdue).Error; err != nil {
+		return err
+	}
+	var overdue []models.Report
+	reports().Where(overdueCond, overdueArgs...).Order("reports.updated_at").Limit(subscriptionOverdueList).Find(&overdue)
+	for _, r := range overdue {
+		summary.Items = append(summary.Items, overdueItem{
+			TrackingID: r.TrackingID,
+			Title:      r.Title,
+			Status:     r.Status,
+			Days:       int(now.Sub(r.UpdatedAt).Hours() / 24),
+			URL:        config.FrontendURL + "/detail/" + strconv.FormatUint(uint64(r.ID), 10),
+		})
+	}
+	summary.More = summary.Overdue - int64(len(summary.Items))
+
+	attachment, err := subscriptionAttachment(sub.Format, scope, from, to, now, summary.Scope)
+	if err != nil {
+		return err
+	}
+
+	msg := mailer.Message{
+		To:          recipients,
+		Subject:     fmt.Sprintf("[%s] Rekap %s: %s (%s)", config.AppName, summary.Frequency, sub.Name, summary.Period),
+		Attachments: []mailer.Attachment{attachment},
+	}
+	var text, html bytes.Buffer
+	if err := subscriptionTextTemplate.Execute(&text, summary); err != nil {
+		return err
+	}
+	if err := subscriptionHTMLTemplate.Execute(&html, summary); err != nil {
+		return err
+	}
+	msg.Text, msg.HTML = text.String(), html.String()
+	return mailer.Send(msg)
+}
+
+// subscriptionAttachment membuat lampiran: rekap PDF, atau daftar laporan (CSV/XLSX) yang
+// baru masuk, selesai, atau melewati SLA pada periode tersebut
+func subscriptionAttachment(format string, scope func(*gorm.DB) *gorm.DB, from, to, now time.Time, scopeLabel string) (mailer.Attachment, error) {
+	name := "laporan-" + from.Format("20060102") + "-" + to.AddDate(0, 0, -1).Format("20060102")
+	var buf bytes.Buffer
+
+	if format == "pdf" {
+		data, err := buildRecap(scope, from, to, scopeLabel)
+		if err != nil {
+			return mailer.Attachment{}, err
+		}
+		if err := pdf.Recap(&buf, data); err != nil {
+			return mailer.Attachment{}, err
+		}
+		return mailer.Attachment{Filename: "rekap-" + name + ".pdf", ContentType: "application/pdf", Data: buf.Bytes()}, nil
+	}
+
+	w, err := export.New(format, &buf)
+	if err != nil {
+		return mailer.Attachment{}, err
+	}
+	overdueCond, overdueArgs := overdueCondition(now)
+	query := config.DB.Scopes(scope).Where(
+		"((reports.created_at >= ? AND reports.created_at < ?) OR (reports.resolved_at >= ? AND reports.resolved_at < ?) OR "+overdueCond+")",
+		append([]interface{}{from, to, from, to}, overdueArgs...)...)
+	if err := writeReportExport(w, query, orderClause("created_at", true)); err != nil {
+		return mailer.Attachment{}, err
+	}
+	if err := w.Close(); err != nil {
+		return mailer.Attachment{}, err
+	}
+	return mailer.Attachment{Filename: name + "." + format, ContentType: export.ContentType(format), Data: buf.Bytes()}, nil
+}
+
+var subscriptionTextTemplate = template.Must(template.New("text").Parse(`Rekap {{.Frequency}} "{{.Name}}"
+Periode: {{.Period}}
+Cakupan: {{.Scope}}
+
+Laporan baru              : {{.New}}
+Laporan selesai           : {{.Resolved}}
+Melewati batas waktu (SLA): {{.Overdue}}
+{{if .Items}}
+Laporan yang melewati batas waktu:
+{{range .Items}}- {{.TrackingID}} | {{.Title}} | {{.Status}} sejak {{.Days}} hari
+  {{.URL}}
+{{end}}{{if gt .More 0}}... dan {{.More}} laporan lainnya
+{{end}}{{end}}
+Rincian terlampir dalam format {{.Format}}.
+
+--
+Email ini dikirim otomatis oleh {{.AppName}}. Atur langganan di {{.ManageURL}}
+`))
+
+var subscriptionHTMLTemplate = htmltemplate.Must(htmltemplate.New("html").Parse(`<!DOCTYPE html>
+<html><body style="font-family:Arial,sans-serif;color:#111827;max-width:640px">
+<h2 style="color:#b91c1c;margin-bottom:4px">Rekap {{.Frequency}}: {{.Name}}</h2>
+<p style="color:#6b7280;margin-top:0">{{.Period}} &middot; {{.Scope}}</p>
+<table cellpadding="8" style="border-collapse:collapse;width:100%">
+<tr>
+<td style="border:1px solid #e5e7eb"><div style="color:#6b7280;font-size:12px">Laporan baru</div><div style="font-size:22px;font-weight:bold">{{.New}}</div></td>
+<td style="border:1px solid #e5e7eb"><div style="color:#6b7280;font-size:12px">Selesai</div><div style="font-size:22px;font-weight:bold">{{.Resolved}}</div></td>
+<td style="border:1px solid #e5e7eb"><div style="color:#6b7280;font-size:12px">Melewati SLA</div><div style="font-size:22px;font-weight:bold;color:#b91c1c">{{.Overdue}}</div></td>
+</tr>
+</table>
+{{if .Items}}
+<h3>Laporan yang melewati batas waktu</h3>
+<table cellpadding="6" style="border-collapse:collapse;width:100%;font-size:13px">
+<tr style="background:#f3f4f6"><th align="left">Tracking ID</th><th align="left">Judul</th><th align="left">Status</th><th align="right">Hari</th></tr>
+{{range .Items}}<tr style="border-top:1px solid #e5e7eb"><td><a href="{{.URL}}">{{.TrackingID}}</a></td><td>{{.Title}}</td><td>{{.Status}}</td><td align="right">{{.Days}}</td></tr>
+{{end}}</table>
+{{if gt .More 0}}<p style="color:#6b7280">... dan {{.More}} laporan lainnya</p>{{end}}
+{{end}}
+<p>Rincian terlampir dalam format {{.Format}}.</p>
+<p style="color:#6b7280;font-size:12px">Email ini dikirim otomatis oleh {{.AppName}}. <a href="{{.ManageURL}}">Atur langganan</a></p>
+</body></html>
+`))
@@ -2,6 +2,7 @@ package controllers
 
 import (
 	"net/http"
+	"project-backend/audit"
 	"project-backend/config"
 	"project-backend/models"
 	"strconv"
@@ -12,7 +13,7 @@ import (
 
 func GetAllUsers(c *gin.Context) {
 	var users []models.User
-	if err := config.DB.Preload("Reports").Preload("Categories").Find(&users).Error; err != nil {
+	if err := config.DB.Preload("Reports").Preload("Categories").Preload("Regions").Find(&users).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, models.Response{
 			Status:  500,
 			Message: "Error fetching users",
@@ -30,7 +31,7 @@ func GetAllUsers(c *gin.Context) {
 func GetUserByID(c *gin.Context) {
 	id := c.Param("id")
 	var user models.User
-	result := config.DB.Preload("Reports").Preload("Categories").First(&user, id)
+	result := config.DB.Preload("Reports").Preload("Categories").Preload("Regions").First(&user, id)
 	if result.RowsAffected == 0 {
 		c.JSON(http.StatusNotFound, models.Response{
 			Status:  404,
@@ -83,6 +84,8 @@ func UpdateUser(c *gin.Context) {
 		return
 	}
 
+	audit.SetBefore(c, gin.H{"name": user.Name, "email": user.Email, "role": user.Role})
+
 	// update field umum
 	user.Name = input.Name
 	user.Email = input.Email
@@ -102,6 +105,8 @@ func UpdateUser(c *gin.Context) {
 		return
 	}
 
+	audit.SetAfter(c, gin.H{"name": user.Name, "email": user.Email, "role": user.Role})
+
 	c.JSON(http.StatusOK, gin.H{"message": "User updated", "user": user})
 }
 
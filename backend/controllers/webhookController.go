@@ -0,0 +1,266 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"project-backend/audit"
+	"project-backend/config"
+	"project-backend/models"
+	"project-backend/outbox"
+	"project-backend/webhook"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type webhookInput struct {
+	Name         string   `json:"name" binding:"required"`
+	URL          string   `json:"url" binding:"required"`
+	Events       []string `json:"events"`
+	CategoryIDs  []uint   `json:"category_ids"`
+	Active       *bool    `json:"active"`
+	RotateSecret bool     `json:"rotate_secret"`
+}
+
+// apply memvalidasi input dan menyalinnya ke w; pesan error siap dikirim ke klien
+func (in webhookInput) apply(w *models.Webhook) error {
+	w.Name = strings.TrimSpace(in.Name)
+	u, err := url.Parse(strings.TrimSpace(in.URL))
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return errors.New("URL webhook harus berupa alamat http(s) yang lengkap")
+	}
+	w.URL = u.String()
+
+	if len(in.Events) == 0 {
+		return errors.New("pilih minimal satu event")
+	}
+	for _, e := range in.Events {
+		if !webhook.IsValidEvent(e) {
+			return errors.New("event tidak dikenal: " + e)
+		}
+	}
+	w.Events = strings.Join(in.Events, ",")
+
+	ids := make([]string, 0, len(in.CategoryIDs))
+	if len(in.CategoryIDs) > 0 {
+		var count int64
+		config.DB.Model(&models.Category{}).Where("id IN ?", in.CategoryIDs).Count(&count)
+		if int(count) != len(in.CategoryIDs) {
+			return errors.New("kategori tidak ditemukan")
+		}
+		for _, id := range in.CategoryIDs {
+			ids = append(ids, strconv.FormatUint(uint64(id), 10))
+		}
+	}
+	w.CategoryIDs = strings.Join(ids, ",")
+
+	if in.Active != nil {
+		w.Active = *in.Active
+	}
+	return nil
+}
+
+// GET /admin/webhooks
+func GetWebhooks(c *gin.Context) {
+	var hooks []models.Webhook
+	if err := config.DB.Order("id").Find(&hooks).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": "Gagal mengambil webhook"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": hooks, "events": webhook.Events})
+}
+
+// POST /admin/webhooks
+// body: {"name": "Command Center", "url": "https://cc.example.go.id/hook",
+// "events": ["report.created"], "category_ids": [2, 5]}
+// Secret hanya ditampilkan sekali di respons ini (dan saat rotate_secret).
+func CreateWebhook(c *gin.Context) {
+	var input webhookInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Nama dan URL wajib diisi"})
+		return
+	}
+	w := models.Webhook{Active: true, Secret: webhook.NewSecret(), CreatedBy: c.GetUint("userID")}
+	if err := input.apply(&w); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+	if err := config.DB.Create(&w).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": "Gagal menyimpan webhook"})
+		return
+	}
+	audit.SetTargetID(c, w.ID)
+	audit.SetAfter(c, w)
+
+	c.JSON(http.StatusCreated, gin.H{"message": "Webhook berhasil dibuat", "data": w, "secret": w.Secret})
+}
+
+// PUT /admin/webhooks/:id
+func UpdateWebhook(c *gin.Context) {
+	var w models.Webhook
+	if err := config.DB.First(&w, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"message": "Webhook tidak ditemukan"})
+		return
+	}
+	var input webhookInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Nama dan URL wajib diisi"})
+		return
+	}
+	audit.SetBefore(c, w)
+	if err := input.apply(&w); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+	if input.RotateSecret {
+		w.Secret = webhook.NewSecret()
+	}
+	if err := config.DB.Save(&w).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": "Gagal menyimpan webhook"})
+		return
+	}
+	audit.SetAfter(c, gin.H{"webhook": w, "secret_rotated": input.RotateSecret})
+
+	resp := gin.H{"message": "Webhook berhasil diperbarui", "data": w}
+	if input.RotateSecret {
+		resp["secret"] = w.Secret
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// DELETE /admin/webhooks/:id -> hapus webhook beserta log pengirimannya
+func DeleteWebhook(c *gin.Context) {
+	var w models.Webhook
+	if err := config.DB.First(&w, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"message": "Webhook tidak ditemukan"})
+		return
+	}
+	audit.SetBefore(c, w)
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("webhook_id = ?", w.ID).Delete(&models.WebhookDelivery{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&w).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": "Gagal menghapus webhook"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Webhook berhasil dihapus"})
+}
+
+// GET /admin/webhooks/:id/deliveries?status=failed&event=report.created&page=1&limit=20
+func GetWebhookDeliveries(c *gin.Context) {
+	meta := ListMeta{Page: 1, Limit: defaultPageLimit}
+	if l, err := strconv.Atoi(c.Query("limit")); err == nil && l > 0 {
+		meta.Limit = l
+	}
+	if meta.Limit > maxPageLimit {
+		meta.Limit = maxPageLimit
+	}
+	if p, err := strconv.Atoi(c.Query("page")); err == nil && p > 1 {
+		meta.Page = p
+	}
+
+	db := config.DB.Model(&models.WebhookDelivery{}).Where("webhook_id = ?", c.Param("id"))
+	if v := c.Query("status"); v != "" {
+		db = db.Where("status = ?", v)
+	}
+	if v := c.Query("event"); v != "" {
+		db = db.Where("event = ?", v)
+	}
+	if err := db.Session(&gorm.Session{}).Count(&meta.Total).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": "Gagal mengambil log pengiriman"})
+		return
+	}
+
+	var deliveries []models.WebhookDelivery
+	if err := db.Order("created_at DESC, id DESC").
+		Offset((meta.Page - 1) * meta.Limit).Limit(meta.Limit).
+		Find(&deliveries).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": "Gagal mengambil log pengiriman"})
+		return
+	}
+	meta.HasMore = int64(meta.Page*meta.Limit) < meta.Total
+
+	c.JSON(http.StatusOK, gin.H{"data": deliveries, "meta": meta})
+}
+
+// POST /admin/webhooks/deliveries/:id/redeliver -> kirim ulang payload yang sama
+// sekarang juga; hasilnya dicatat sebagai pengiriman baru
+func RedeliverWebhook(c *gin.Context) {
+	var d models.WebhookDelivery
+	if err := config.DB.First(&d, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"message": "Pengiriman tidak ditemukan"})
+		return
+	}
+	var w models.Webhook
+	if err := config.DB.First(&w, d.WebhookID).Error; err != nil || !w.Active {
+		c.JSON(http.StatusConflict, gin.H{"message": "Webhook sudah dihapus atau dinonaktifkan"})
+		return
+	}
+	audit.SetTargetID(c, w.ID)
+
+	retry, err := webhook.Redeliver(config.DB, d, time.Now())
+	if errors.Is(err, webhook.ErrRedeliveryPending) {
+		c.JSON(http.StatusConflict, gin.H{"message": "Event ini masih menunggu di antrean pengiriman"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": "Gagal mengirim ulang"})
+		return
+	}
+	audit.SetAfter(c, gin.H{"delivery_id": retry.ID, "redelivery_of": d.ID, "status": retry.Status})
+
+	msg := "Pengiriman ulang berhasil"
+	if retry.Status != models.DeliverySuccess {
+		msg = "Pengiriman ulang gagal: " + retry.LastError
+	}
+	c.JSON(http.StatusOK, gin.H{"message": msg, "data": retry})
+}
+
+// webhookReportPayload adalah data laporan untuk webhook (tanpa identitas pelapor)
+func webhookReportPayload(report models.Report) gin.H {
+	data := reportEventPayload(report)
+	data["description"] = report.Description
+	data["wilayah"] = report.Wilayah
+	data["kode_wilayah"] = report.KodeWilayah
+	data["lokasi"] = report.Lokasi
+	data["latitude"] = report.Latitude
+	data["longitude"] = report.Longitude
+	data["tracking_url"] = config.TrackingURL(report.TrackingID)
+	data["created_at"] = report.CreatedAt
+	data["updated_at"] = report.UpdatedAt
+	return data
+}
+
+// webhookEventID adalah ID event webhook untuk event outbox; sama untuk setiap
+// percobaan sehingga penerima bisa mengabaikan duplikat
+func webhookEventID(ev outbox.Event) string {
+	return "evt_" + strconv.FormatUint(uint64(ev.ID), 10)
+}
+
+func webhookReportCreated(ev outbox.Event, report models.Report) error {
+	_, err := webhook.Enqueue(config.DB, webhookEventID(ev), webhook.EventReportCreated, report.CategoryID, ev.CreatedAt,
+		webhookReportPayload(report))
+	return err
+}
+
+func webhookReportStatusChanged(ev outbox.Event, report models.Report, oldStatus, deskripsi string) error {
+	data := webhookReportPayload(report)
+	data["old_status"] = oldStatus
+	data["deskripsi"] = deskripsi
+	_, err := webhook.Enqueue(config.DB, webhookEventID(ev), webhook.EventReportStatusChanged, report.CategoryID, ev.CreatedAt, data)
+	return err
+}
+
+// webhookReportActivity mengirim event komentar/tindak lanjut beserta ringkasan laporannya
+func webhookReportActivity(ev outbox.Event, report models.Report, data gin.H) error {
+	data["report"] = reportEventPayload(report)
+	_, err := webhook.Enqueue(config.DB, webhookEventID(ev), ev.Type, report.CategoryID, ev.CreatedAt, data)
+	return err
+}
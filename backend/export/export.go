@@ -0,0 +1,240 @@
+// Package export menulis data tabular ke CSV atau XLSX secara bertahap (baris per
+// baris) sehingga data besar tidak perlu ditampung seluruhnya di memori.
+package export
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+)
+
+const (
+	FormatCSV  = "csv"
+	FormatXLSX = "xlsx"
+)
+
+var ErrUnknownFormat = errors.New("format ekspor harus csv atau xlsx")
+
+// Writer menulis satu atau beberapa tabel. Di XLSX setiap tabel menjadi sheet,
+// di CSV tabel berikutnya dipisah baris kosong dan diberi baris judul.
+type Writer interface {
+	// Sheet memulai tabel baru dengan judul kolom header
+	Sheet(name string, header []string) error
+	// Row menulis satu baris; nilai time.Time ditulis sebagai tanggal
+	Row(values ...interface{}) error
+	// Close menyelesaikan berkas; wajib dipanggil sebelum respons ditutup
+	Close() error
+}
+
+// New membuat Writer sesuai format
+func New(format string, w io.Writer) (Writer, error) {
+	switch format {
+	case FormatCSV:
+		return newCSVWriter(w), nil
+	case FormatXLSX:
+		return newXLSXWriter(w)
+	}
+	return nil, ErrUnknownFormat
+}
+
+// ContentType mengembalikan MIME type untuk format
+func ContentType(format string) string {
+	if format == FormatXLSX {
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	return "text/csv; charset=utf-8"
+}
+
+const dateLayout = "2006-01-02 15:04"
+
+// csvWriter menulis CSV UTF-8 dengan BOM agar terbaca benar di Excel
+type csvWriter struct {
+	w      *csv.Writer
+	sheets int
+	rows   int
+}
+
+func newCSVWriter(w io.Writer) *csvWriter {
+	io.WriteString(w, "\ufeff")
+	return &csvWriter{w: csv.NewWriter(w)}
+}
+
+func (cw *csvWriter) Sheet(name string, header []string) error {
+	if cw.sheets > 0 {
+		if err := cw.w.Write([]string{}); err != nil {
+			return err
+		}
+		if err := cw.w.Write([]string{name}); err != nil {
+			return err
+		}
+	}
+	cw.sheets++
+	return cw.w.Write(header)
+}
+
+func (cw *csvWriter) Row(values ...interface{}) error {
+	record := make([]string, len(values))
+	for i, v := range values {
+		record[i] = formatValue(v)
+	}
+	if err := cw.w.Write(record); err != nil {
+		return err
+	}
+	// kirim ke klien secara berkala
+	cw.rows++
+	if cw.rows%200 == 0 {
+		cw.w.Flush()
+		return cw.w.Error()
+	}
+	return nil
+}
+
+func (cw *csvWriter) Close() error {
+	cw.w.Flush()
+	return cw.w.Error()
+}
+
+// escapeFormula mendahului teks yang diawali =, +, -, @, tab atau CR dengan tanda kutip
+// agar isian warga (judul, deskripsi, lokasi, nama) tidak dijalankan sebagai formula
+// saat berkas dibuka di Excel/LibreOffice
+func escapeFormula(s string) string {
+	if s != "" && strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
+
+func formatValue(v interface{}) string {
+	switch x := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return escapeFormula(x)
+	case time.Time:
+		if x.IsZero() {
+			return ""
+		}
+		return x.Format(dateLayout)
+	case *time.Time:
+		if x == nil {
+			return ""
+		}
+		return formatValue(*x)
+	case float64:
+		return strconv.FormatFloat(x, 'f', -1, 64)
+	case bool:
+		if x {
+			return "Ya"
+		}
+		return "Tidak"
+	}
+	return fmt.Sprint(v)
+}
+
+// xlsxWriter memakai StreamWriter excelize: baris ditulis ke berkas sementara
+// lalu dikirim sebagai satu berkas .xlsx saat Close
+type xlsxWriter struct {
+	out       io.Writer
+	f         *excelize.File
+	sw        *excelize.StreamWriter
+	row       int
+	dateStyle int
+	headStyle int
+}
+
+func newXLSXWriter(w io.Writer) (*xlsxWriter, error) {
+	f := excelize.NewFile()
+	dateFmt := "yyyy-mm-dd hh:mm"
+	dateStyle, err := f.NewStyle(&excelize.Style{CustomNumFmt: &dateFmt})
+	if err != nil {
+		return nil, err
+	}
+	headStyle, err := f.NewStyle(&excelize.Style{Font: &excelize.Font{Bold: true}})
+	if err != nil {
+		return nil, err
+	}
+	return &xlsxWriter{out: w, f: f, dateStyle: dateStyle, headStyle: headStyle}, nil
+}
+
+func (xw *xlsxWriter) Sheet(name string, header []string) error {
+	if xw.sw != nil {
+		if err := xw.sw.Flush(); err != nil {
+			return err
+		}
+		if _, err := xw.f.NewSheet(name); err != nil {
+			return err
+		}
+	} else if err := xw.f.SetSheetName("Sheet1", name); err != nil {
+		return err
+	}
+
+	sw, err := xw.f.NewStreamWriter(name)
+	if err != nil {
+		return err
+	}
+	xw.sw = sw
+	xw.row = 1
+
+	cells := make([]interface{}, len(header))
+	for i, h := range header {
+		cells[i] = excelize.Cell{StyleID: xw.headStyle, Value: h}
+	}
+	if err := sw.SetPanes(&excelize.Panes{Freeze: true, YSplit: 1, TopLeftCell: "A2", ActivePane: "bottomLeft"}); err != nil {
+		return err
+	}
+	return xw.writeRow(cells)
+}
+
+func (xw *xlsxWriter) Row(values ...interface{}) error {
+	if xw.sw == nil {
+		return errors.New("export: Sheet harus dipanggil sebelum Row")
+	}
+	cells := make([]interface{}, len(values))
+	for i, v := range values {
+		switch x := v.(type) {
+		case time.Time:
+			if x.IsZero() {
+				cells[i] = nil
+			} else {
+				cells[i] = excelize.Cell{StyleID: xw.dateStyle, Value: x}
+			}
+		case *time.Time:
+			if x == nil || x.IsZero() {
+				cells[i] = nil
+			} else {
+				cells[i] = excelize.Cell{StyleID: xw.dateStyle, Value: *x}
+			}
+		case bool, string:
+			cells[i] = formatValue(x)
+		default:
+			cells[i] = v
+		}
+	}
+	return xw.writeRow(cells)
+}
+
+func (xw *xlsxWriter) writeRow(cells []interface{}) error {
+	cell, err := excelize.CoordinatesToCellName(1, xw.row)
+	if err != nil {
+		return err
+	}
+	xw.row++
+	return xw.sw.SetRow(cell, cells)
+}
+
+func (xw *xlsxWriter) Close() error {
+	defer xw.f.Close()
+	if xw.sw != nil {
+		if err := xw.sw.Flush(); err != nil {
+			return err
+		}
+	}
+	_, err := xw.f.WriteTo(xw.out)
+	return err
+}
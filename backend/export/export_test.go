@@ -0,0 +1,55 @@
+package export
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFormatValueEscapesFormulas(t *testing.T) {
+	tests := []struct {
+		name  string
+		value interface{}
+		want  string
+	}{
+		{"teks biasa", "Jalan berlubang", "Jalan berlubang"},
+		{"formula", "=HYPERLINK(\"http://x\")", "'=HYPERLINK(\"http://x\")"},
+		{"plus", "+62 812", "'+62 812"},
+		{"minus", "-1+2", "'-1+2"},
+		{"at", "@SUM(A1)", "'@SUM(A1)"},
+		{"tab", "\t=1", "'\t=1"},
+		{"carriage return", "\r=1", "'\r=1"},
+		{"teks kosong", "", ""},
+		// angka bukan isian teks, tetap ditulis apa adanya
+		{"angka negatif", -7.5, "-7.5"},
+		{"waktu", time.Date(2025, 8, 1, 9, 30, 0, 0, time.UTC), "2025-08-01 09:30"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := formatValue(tt.value); got != tt.want {
+				t.Errorf("formatValue(%q) = %q, want %q", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCSVWriterEscapesFormulas(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := New(FormatCSV, &buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Sheet("Laporan", []string{"judul", "skor"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Row("=1+1", -3); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if got := buf.String(); !strings.Contains(got, "'=1+1,-3\n") {
+		t.Errorf("csv = %q, want baris '=1+1,-3", got)
+	}
+}
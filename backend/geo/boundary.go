@@ -0,0 +1,338 @@
+package geo
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode"
+)
+
+// Level adalah tingkat wilayah administrasi
+type Level string
+
+const (
+	LevelProvinsi  Level = "provinsi"
+	LevelKabupaten Level = "kabupaten"
+	LevelKecamatan Level = "kecamatan"
+	LevelDesa      Level = "desa"
+)
+
+// Levels berurutan dari yang paling luas
+var Levels = []Level{LevelProvinsi, LevelKabupaten, LevelKecamatan, LevelDesa}
+
+// Point adalah satu titik [lon, lat] sesuai urutan GeoJSON/Shapefile
+type Point [2]float64
+
+// Boundary adalah batas satu wilayah administrasi. Rings berisi semua ring (luar
+// maupun lubang, dari semua bagian multipolygon); titik dianggap di dalam jika
+// memotong ring sebanyak ganjil kali.
+type Boundary struct {
+	Level Level
+	Code  string
+	Name  string
+	Rings [][]Point
+	bbox  BoundingBox
+}
+
+func newBoundary(level Level, code, name string, rings [][]Point) *Boundary {
+	b := &Boundary{Level: level, Code: NormalizeCode(code), Name: strings.TrimSpace(name), Rings: rings}
+	b.bbox = BoundingBox{MinLat: 90, MinLon: 180, MaxLat: -90, MaxLon: -180}
+	for _, ring := range rings {
+		for _, p := range ring {
+			b.bbox.MinLon = minFloat(b.bbox.MinLon, p[0])
+			b.bbox.MaxLon = maxFloat(b.bbox.MaxLon, p[0])
+			b.bbox.MinLat = minFloat(b.bbox.MinLat, p[1])
+			b.bbox.MaxLat = maxFloat(b.bbox.MaxLat, p[1])
+		}
+	}
+	return b
+}
+
+// Contains memeriksa apakah titik berada di dalam batas wilayah (aturan even-odd)
+func (b *Boundary) Contains(lat, lon float64) bool {
+	if !b.bbox.Contains(lat, lon) {
+		return false
+	}
+	inside := false
+	for _, ring := range b.Rings {
+		for i, j := 0, len(ring)-1; i < len(ring); j, i = i, i+1 {
+			xi, yi := ring[i][0], ring[i][1]
+			xj, yj := ring[j][0], ring[j][1]
+			if (yi > lat) != (yj > lat) && lon < (xj-xi)*(lat-yi)/(yj-yi)+xi {
+				inside = !inside
+			}
+		}
+	}
+	return inside
+}
+
+// NormalizeCode mengubah kode wilayah ke format Kemendagri bertitik
+// ("3404052001" atau "34.04.05.2001"). Kode dengan panjang lain dikembalikan apa adanya.
+func NormalizeCode(code string) string {
+	digits := strings.Map(func(r rune) rune {
+		if unicode.IsDigit(r) {
+			return r
+		}
+		return -1
+	}, code)
+	switch len(digits) {
+	case 2:
+		return digits
+	case 4:
+		return digits[:2] + "." + digits[2:]
+	case 6:
+		return digits[:2] + "." + digits[2:4] + "." + digits[4:]
+	case 10:
+		return digits[:2] + "." + digits[2:4] + "." + digits[4:6] + "." + digits[6:]
+	}
+	return strings.TrimSpace(code)
+}
+
+// RegionRef adalah kode dan nama satu wilayah hasil lookup
+type RegionRef struct {
+	Code string `json:"code"`
+	Name string `json:"name"`
+}
+
+// Location adalah hasil lookup koordinat ke hierarki wilayah. Tingkat yang
+// datanya tidak dimuat atau tidak ditemukan bernilai nil.
+type Location struct {
+	Provinsi  *RegionRef `json:"provinsi,omitempty"`
+	Kabupaten *RegionRef `json:"kabupaten,omitempty"`
+	Kecamatan *RegionRef `json:"kecamatan,omitempty"`
+	Desa      *RegionRef `json:"desa,omitempty"`
+}
+
+// Code mengembalikan kode wilayah paling rinci yang ditemukan
+func (l Location) Code() string {
+	for _, r := range []*RegionRef{l.Desa, l.Kecamatan, l.Kabupaten, l.Provinsi} {
+		if r != nil {
+			return r.Code
+		}
+	}
+	return ""
+}
+
+func (l *Location) set(level Level, ref *RegionRef) {
+	switch level {
+	case LevelProvinsi:
+		l.Provinsi = ref
+	case LevelKabupaten:
+		l.Kabupaten = ref
+	case LevelKecamatan:
+		l.Kecamatan = ref
+	case LevelDesa:
+		l.Desa = ref
+	}
+}
+
+// BoundaryIndex menyimpan batas wilayah per tingkat untuk lookup koordinat
+type BoundaryIndex struct {
+	levels map[Level][]*Boundary
+}
+
+// NewBoundaryIndex membuat indeks kosong
+func NewBoundaryIndex() *BoundaryIndex {
+	return &BoundaryIndex{levels: map[Level][]*Boundary{}}
+}
+
+// Add menambahkan batas wilayah ke indeks
+func (idx *BoundaryIndex) Add(b *Boundary) {
+	idx.levels[b.Level] = append(idx.levels[b.Level], b)
+}
+
+// Empty bernilai true jika belum ada batas wilayah di tingkat mana pun
+func (idx *BoundaryIndex) Empty() bool {
+	for _, list := range idx.levels {
+		if len(list) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Count mengembalikan jumlah batas wilayah per tingkat
+func (idx *BoundaryIndex) Count() map[Level]int {
+	out := map[Level]int{}
+	for level, list := range idx.levels {
+		out[level] = len(list)
+	}
+	return out
+}
+
+// Lookup mencari wilayah yang memuat koordinat di setiap tingkat. ok bernilai false
+// jika koordinat berada di luar wilayah layanan, yaitu di luar semua batas pada
+// tingkat paling luas yang dimuat. Indeks kosong tidak menolak koordinat apa pun
+// (ok true dengan Location kosong).
+func (idx *BoundaryIndex) Lookup(lat, lon float64) (loc Location, ok bool) {
+	if idx.Empty() {
+		return loc, true
+	}
+	outermost := true
+	for _, level := range Levels {
+		list := idx.levels[level]
+		if len(list) == 0 {
+			continue
+		}
+		var found *Boundary
+		for _, b := range list {
+			if b.Contains(lat, lon) {
+				found = b
+				break
+			}
+		}
+		if outermost {
+			if found == nil {
+				return loc, false
+			}
+			outermost = false
+		}
+		if found != nil {
+			loc.set(level, &RegionRef{Code: found.Code, Name: found.Name})
+		}
+	}
+	return loc, !outermost
+}
+
+// LoadBoundaryDir memuat berkas batas wilayah dari dir. Nama berkas menentukan
+// tingkatnya: provinsi, kabupaten, kecamatan, desa dengan ekstensi .geojson/.json
+// atau .shp (beserta .dbf dengan nama yang sama).
+func LoadBoundaryDir(dir string) (*BoundaryIndex, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	idx := NewBoundaryIndex()
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		ext := strings.ToLower(filepath.Ext(e.Name()))
+		level := Level(strings.ToLower(strings.TrimSuffix(e.Name(), filepath.Ext(e.Name()))))
+		if !isLevel(level) {
+			continue
+		}
+		path := filepath.Join(dir, e.Name())
+
+		var list []*Boundary
+		switch ext {
+		case ".geojson", ".json":
+			list, err = LoadGeoJSON(path, level)
+		case ".shp":
+			list, err = LoadShapefile(path, level)
+		default:
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", e.Name(), err)
+		}
+		for _, b := range list {
+			idx.Add(b)
+		}
+	}
+	return idx, nil
+}
+
+func isLevel(level Level) bool {
+	for _, l := range Levels {
+		if l == level {
+			return true
+		}
+	}
+	return false
+}
+
+// Nama atribut kode dan nama wilayah yang dikenali per tingkat (huruf kecil).
+// Selain kolom umum, dikenali juga kolom shapefile RBI Badan Informasi Geospasial.
+var (
+	codeKeys = map[Level][]string{
+		LevelProvinsi:  {"kode_wilayah", "kode", "kdppum", "code"},
+		LevelKabupaten: {"kode_wilayah", "kode", "kdpkab", "code"},
+		LevelKecamatan: {"kode_wilayah", "kode", "kdcpum", "code"},
+		LevelDesa:      {"kode_wilayah", "kode", "kdepum", "code"},
+	}
+	nameKeys = map[Level][]string{
+		LevelProvinsi:  {"nama", "name", "wadmpr"},
+		LevelKabupaten: {"nama", "name", "wadmkk"},
+		LevelKecamatan: {"nama", "name", "wadmkc"},
+		LevelDesa:      {"nama", "name", "wadmkd"},
+	}
+)
+
+// pickAttr mengambil atribut pertama yang tersedia dari keys (tanpa membedakan huruf besar)
+func pickAttr(attrs map[string]string, keys []string) string {
+	lower := make(map[string]string, len(attrs))
+	for k, v := range attrs {
+		lower[strings.ToLower(k)] = v
+	}
+	for _, k := range keys {
+		if v := strings.TrimSpace(lower[k]); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+func minFloat(a, b float64) float64 {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxFloat(a, b float64) float64 {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+var boundaries *BoundaryIndex
+
+// InitBoundaries memuat batas wilayah bawaan yang dipakai Boundaries(). Jika dir tidak
+// berisi berkas batas wilayah, indeks bawaan tetap nil sehingga lookup dinonaktifkan.
+func InitBoundaries(dir string) error {
+	boundaries = nil
+	idx, err := LoadBoundaryDir(dir)
+	if err != nil {
+		return err
+	}
+	if !idx.Empty() {
+		boundaries = idx
+	}
+	return nil
+}
+
+// Boundaries mengembalikan indeks batas wilayah bawaan (nil jika belum dimuat atau
+// tidak ada berkas batas wilayah)
+func Boundaries() *BoundaryIndex {
+	return boundaries
+}
+
+// All mengembalikan semua batas wilayah, berurutan dari tingkat paling luas
+func (idx *BoundaryIndex) All() []*Boundary {
+	var out []*Boundary
+	for _, level := range Levels {
+		out = append(out, idx.levels[level]...)
+	}
+	return out
+}
+
+// LevelOfCode menentukan tingkat wilayah dari jumlah segmen kode Kemendagri
+func LevelOfCode(code string) Level {
+	n := len(strings.Split(NormalizeCode(code), "."))
+	if n >= 1 && n <= len(Levels) {
+		return Levels[n-1]
+	}
+	return ""
+}
+
+// ParentCode mengembalikan kode wilayah induk ("34.04.05" -> "34.04"), kosong untuk provinsi
+func ParentCode(code string) string {
+	code = NormalizeCode(code)
+	if i := strings.LastIndexByte(code, '.'); i >= 0 {
+		return code[:i]
+	}
+	return ""
+}
@@ -0,0 +1,121 @@
+package geo
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// square membuat ring persegi dari (minLon,minLat) sampai (maxLon,maxLat)
+func square(minLon, minLat, maxLon, maxLat float64) []Point {
+	return []Point{{minLon, minLat}, {maxLon, minLat}, {maxLon, maxLat}, {minLon, maxLat}, {minLon, minLat}}
+}
+
+func testIndex() *BoundaryIndex {
+	idx := NewBoundaryIndex()
+	idx.Add(newBoundary(LevelProvinsi, "34", "DI Yogyakarta", [][]Point{square(110, -8, 111, -7)}))
+	idx.Add(newBoundary(LevelKabupaten, "3404", "Sleman", [][]Point{square(110, -7.8, 111, -7)}))
+	idx.Add(newBoundary(LevelKabupaten, "3471", "Kota Yogyakarta", [][]Point{square(110, -8, 111, -7.8)}))
+	// kecamatan dengan lubang: titik di dalam lubang tidak termasuk kecamatan ini
+	idx.Add(newBoundary(LevelKecamatan, "340405", "Depok", [][]Point{
+		square(110.2, -7.8, 110.6, -7.4),
+		square(110.3, -7.7, 110.4, -7.6),
+	}))
+	return idx
+}
+
+func TestLookup(t *testing.T) {
+	tests := []struct {
+		name     string
+		lat, lon float64
+		ok       bool
+		code     string
+		kab      string
+	}{
+		{"sampai kecamatan", -7.75, 110.25, true, "34.04.05", "Sleman"},
+		{"di dalam lubang kecamatan", -7.65, 110.35, true, "34.04", "Sleman"},
+		{"kabupaten lain", -7.9, 110.5, true, "34.71", "Kota Yogyakarta"},
+		{"di luar provinsi", -6.2, 106.8, false, "", ""},
+	}
+	idx := testIndex()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loc, ok := idx.Lookup(tt.lat, tt.lon)
+			if ok != tt.ok {
+				t.Fatalf("ok = %v, want %v", ok, tt.ok)
+			}
+			if got := loc.Code(); got != tt.code {
+				t.Errorf("Code() = %q, want %q", got, tt.code)
+			}
+			kab := ""
+			if loc.Kabupaten != nil {
+				kab = loc.Kabupaten.Name
+			}
+			if kab != tt.kab {
+				t.Errorf("kabupaten = %q, want %q", kab, tt.kab)
+			}
+		})
+	}
+}
+
+func TestLookupEmptyIndex(t *testing.T) {
+	loc, ok := NewBoundaryIndex().Lookup(-7.78, 110.37)
+	if !ok || loc.Code() != "" {
+		t.Errorf("Lookup pada indeks kosong = %+v, %v; want lokasi kosong, true", loc, ok)
+	}
+}
+
+func TestInitBoundariesEmptyDir(t *testing.T) {
+	dir := t.TempDir()
+	// hanya README seperti data/wilayah bawaan
+	if err := os.WriteFile(filepath.Join(dir, "README.md"), []byte("# Batas wilayah"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := InitBoundaries(dir); err != nil {
+		t.Fatalf("InitBoundaries: %v", err)
+	}
+	if Boundaries() != nil {
+		t.Error("Boundaries() harus nil jika tidak ada berkas batas wilayah")
+	}
+}
+
+func TestInitBoundariesGeoJSON(t *testing.T) {
+	dir := t.TempDir()
+	fc := `{"type":"FeatureCollection","features":[{"type":"Feature",
+		"properties":{"kode":"3404","nama":"Sleman"},
+		"geometry":{"type":"Polygon","coordinates":[[[110,-7.8],[111,-7.8],[111,-7],[110,-7],[110,-7.8]]]}}]}`
+	if err := os.WriteFile(filepath.Join(dir, "kabupaten.geojson"), []byte(fc), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := InitBoundaries(dir); err != nil {
+		t.Fatalf("InitBoundaries: %v", err)
+	}
+	defer func() { boundaries = nil }()
+
+	idx := Boundaries()
+	if idx == nil {
+		t.Fatal("Boundaries() nil setelah berkas dimuat")
+	}
+	if loc, ok := idx.Lookup(-7.7, 110.4); !ok || loc.Code() != "34.04" {
+		t.Errorf("Lookup = %+v, %v; want 34.04, true", loc, ok)
+	}
+	if _, ok := idx.Lookup(-7.9, 110.4); ok {
+		t.Error("koordinat di luar kabupaten yang dimuat harus ditolak")
+	}
+}
+
+func TestNormalizeCode(t *testing.T) {
+	tests := map[string]string{
+		"34":            "34",
+		"3404":          "34.04",
+		"340405":        "34.04.05",
+		"3404052001":    "34.04.05.2001",
+		"34.04.05.2001": "34.04.05.2001",
+		" 12345 ":       "12345",
+	}
+	for in, want := range tests {
+		if got := NormalizeCode(in); got != want {
+			t.Errorf("NormalizeCode(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
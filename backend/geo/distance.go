@@ -0,0 +1,49 @@
+package geo
+
+import "math"
+
+const (
+	earthRadius     = 6371000.0 // meter
+	metersPerDegree = earthRadius * math.Pi / 180
+)
+
+// Distance menghitung jarak dua titik (meter) dengan rumus haversine
+func Distance(lat1, lon1, lat2, lon2 float64) float64 {
+	toRad := func(d float64) float64 { return d * math.Pi / 180 }
+	dLat := toRad(lat2 - lat1)
+	dLon := toRad(lon2 - lon1)
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(toRad(lat1))*math.Cos(toRad(lat2))*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadius * math.Atan2(math.Sqrt(a), math.Sqrt(1-a))
+}
+
+// BoundingBox adalah area persegi dalam derajat
+type BoundingBox struct {
+	MinLat, MinLon, MaxLat, MaxLon float64
+}
+
+// Contains memeriksa apakah titik berada di dalam bounding box
+func (b BoundingBox) Contains(lat, lon float64) bool {
+	return lat >= b.MinLat && lat <= b.MaxLat && lon >= b.MinLon && lon <= b.MaxLon
+}
+
+// Center mengembalikan titik tengah bounding box
+func (b BoundingBox) Center() (lat, lon float64) {
+	return (b.MinLat + b.MaxLat) / 2, (b.MinLon + b.MaxLon) / 2
+}
+
+// RadiusBox mengembalikan bounding box yang mencakup lingkaran berjari-jari radiusM
+func RadiusBox(lat, lon, radiusM float64) BoundingBox {
+	dLat := radiusM / metersPerDegree
+	cos := math.Cos(lat * math.Pi / 180)
+	dLon := 180.0
+	if cos > 1e-9 {
+		dLon = math.Min(180, radiusM/(metersPerDegree*cos))
+	}
+	return BoundingBox{
+		MinLat: math.Max(-90, lat-dLat),
+		MaxLat: math.Min(90, lat+dLat),
+		MinLon: math.Max(-180, lon-dLon),
+		MaxLon: math.Min(180, lon+dLon),
+	}
+}
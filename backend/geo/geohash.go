@@ -0,0 +1,189 @@
+// Package geo berisi utilitas geospasial: geohash, jarak haversine dan bounding box.
+package geo
+
+import (
+	"math"
+	"strings"
+)
+
+const base32 = "0123456789bcdefghjkmnpqrstuvwxyz"
+
+// Presisi geohash yang disimpan di laporan (sel ~4.8m x 4.8m)
+const StoredPrecision = 9
+
+// Encode mengubah koordinat menjadi geohash dengan panjang precision
+func Encode(lat, lon float64, precision int) string {
+	latRange := [2]float64{-90, 90}
+	lonRange := [2]float64{-180, 180}
+
+	var b strings.Builder
+	bit, ch := 0, 0
+	even := true
+	for b.Len() < precision {
+		if even {
+			mid := (lonRange[0] + lonRange[1]) / 2
+			if lon >= mid {
+				ch = ch<<1 | 1
+				lonRange[0] = mid
+			} else {
+				ch <<= 1
+				lonRange[1] = mid
+			}
+		} else {
+			mid := (latRange[0] + latRange[1]) / 2
+			if lat >= mid {
+				ch = ch<<1 | 1
+				latRange[0] = mid
+			} else {
+				ch <<= 1
+				latRange[1] = mid
+			}
+		}
+		even = !even
+		bit++
+		if bit == 5 {
+			b.WriteByte(base32[ch])
+			bit, ch = 0, 0
+		}
+	}
+	return b.String()
+}
+
+// Decode mengembalikan batas sel geohash (minLat, minLon, maxLat, maxLon)
+func Decode(hash string) (minLat, minLon, maxLat, maxLon float64) {
+	latRange := [2]float64{-90, 90}
+	lonRange := [2]float64{-180, 180}
+	even := true
+	for i := 0; i < len(hash); i++ {
+		idx := strings.IndexByte(base32, hash[i])
+		for mask := 16; mask > 0; mask >>= 1 {
+			if even {
+				mid := (lonRange[0] + lonRange[1]) / 2
+				if idx&mask != 0 {
+					lonRange[0] = mid
+				} else {
+					lonRange[1] = mid
+				}
+			} else {
+				mid := (latRange[0] + latRange[1]) / 2
+				if idx&mask != 0 {
+					latRange[0] = mid
+				} else {
+					latRange[1] = mid
+				}
+			}
+			even = !even
+		}
+	}
+	return latRange[0], lonRange[0], latRange[1], lonRange[1]
+}
+
+// CellSize mengembalikan tinggi dan lebar sel geohash (meter) di sekitar lintang lat
+func CellSize(precision int, lat float64) (height, width float64) {
+	latDeg, lonDeg := cellDegrees(precision)
+	height = latDeg * metersPerDegree
+	width = lonDeg * metersPerDegree * math.Cos(lat*math.Pi/180)
+	return height, width
+}
+
+// cellDegrees mengembalikan tinggi dan lebar sel geohash dalam derajat
+func cellDegrees(precision int) (latDeg, lonDeg float64) {
+	bits := precision * 5
+	latDeg = 180 / math.Pow(2, float64(bits/2))
+	lonDeg = 360 / math.Pow(2, float64((bits+1)/2))
+	return latDeg, lonDeg
+}
+
+// PrecisionForRadius memilih presisi geohash terpanjang yang selnya tidak lebih kecil
+// dari radius, sehingga sel pusat + 8 tetangganya pasti mencakup lingkaran pencarian.
+func PrecisionForRadius(radiusM, lat float64) int {
+	for p := StoredPrecision; p > 1; p-- {
+		h, w := CellSize(p, lat)
+		if h >= radiusM && w >= radiusM {
+			return p
+		}
+	}
+	return 1
+}
+
+// Neighbors mengembalikan sel pusat beserta 8 sel di sekelilingnya
+func Neighbors(hash string) []string {
+	minLat, minLon, maxLat, maxLon := Decode(hash)
+	dLat := maxLat - minLat
+	dLon := maxLon - minLon
+	cLat := (minLat + maxLat) / 2
+	cLon := (minLon + maxLon) / 2
+
+	seen := map[string]bool{}
+	var cells []string
+	for _, dy := range []float64{-1, 0, 1} {
+		for _, dx := range []float64{-1, 0, 1} {
+			lat := cLat + dy*dLat
+			if lat > 90 || lat < -90 {
+				continue
+			}
+			lon := cLon + dx*dLon
+			if lon > 180 {
+				lon -= 360
+			} else if lon < -180 {
+				lon += 360
+			}
+			h := Encode(lat, lon, len(hash))
+			if !seen[h] {
+				seen[h] = true
+				cells = append(cells, h)
+			}
+		}
+	}
+	return cells
+}
+
+// Snap memindahkan koordinat ke titik tengah sel geohash yang ukurannya minimal
+// meters, untuk menyamarkan lokasi persis tanpa bisa dikembalikan ke posisi aslinya.
+func Snap(lat, lon, meters float64) (float64, float64) {
+	if meters <= 0 {
+		return lat, lon
+	}
+	p := PrecisionForRadius(meters, lat)
+	minLat, minLon, maxLat, maxLon := Decode(Encode(lat, lon, p))
+	return (minLat + maxLat) / 2, (minLon + maxLon) / 2
+}
+
+// SnapBox memperlebar b sehingga mencakup semua titik yang hasil Snap(meters)-nya berada
+// di dalam b. Snap memindahkan titik paling jauh setengah sel, dan sel paling besar ada di
+// lintang terjauh dari ekuator, jadi ukuran sel dihitung ulang sampai stabil.
+func SnapBox(b BoundingBox, meters float64) BoundingBox {
+	if meters <= 0 {
+		return b
+	}
+	out, precision := b, 0
+	for {
+		p := PrecisionForRadius(meters, math.Max(math.Abs(out.MinLat), math.Abs(out.MaxLat)))
+		if p == precision {
+			return out
+		}
+		precision = p
+		latDeg, lonDeg := cellDegrees(p)
+		out = BoundingBox{
+			MinLat: math.Max(-90, b.MinLat-latDeg/2),
+			MaxLat: math.Min(90, b.MaxLat+latDeg/2),
+			MinLon: math.Max(-180, b.MinLon-lonDeg/2),
+			MaxLon: math.Min(180, b.MaxLon+lonDeg/2),
+		}
+	}
+}
+
+// CoveringCells mengembalikan sel geohash titik tengah b beserta 8 tetangganya, dengan
+// presisi terpanjang yang selnya tidak lebih kecil dari setengah ukuran b, sehingga
+// sel-sel itu pasti mencakup seluruh b
+func CoveringCells(b BoundingBox) []string {
+	p := StoredPrecision
+	for ; p > 1; p-- {
+		latDeg, lonDeg := cellDegrees(p)
+		if latDeg >= (b.MaxLat-b.MinLat)/2 && lonDeg >= (b.MaxLon-b.MinLon)/2 {
+			break
+		}
+	}
+	lat, lon := b.Center()
+	return Neighbors(Encode(lat, lon, p))
+}
@@ -0,0 +1,36 @@
+package geo
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSnapBoxCoversSnappedPoints(t *testing.T) {
+	tests := []struct {
+		name   string
+		lat    float64
+		lon    float64
+		meters float64
+	}{
+		{"yogyakarta 250m", -7.78291, 110.36712, 250},
+		{"yogyakarta 5km", -7.78291, 110.36712, 5000},
+		{"lintang tinggi 250m", 60.1699, 24.9384, 250},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sLat, sLon := Snap(tt.lat, tt.lon, tt.meters)
+			// box sekecil mungkin di titik samaran harus tetap mencakup titik asli setelah diperlebar
+			box := SnapBox(BoundingBox{MinLat: sLat, MinLon: sLon, MaxLat: sLat, MaxLon: sLon}, tt.meters)
+			if !box.Contains(tt.lat, tt.lon) {
+				t.Errorf("SnapBox = %+v tidak mencakup titik asli %v,%v", box, tt.lat, tt.lon)
+			}
+			hash := Encode(tt.lat, tt.lon, StoredPrecision)
+			for _, cell := range CoveringCells(box) {
+				if strings.HasPrefix(hash, cell) {
+					return
+				}
+			}
+			t.Errorf("CoveringCells(%+v) tidak mencakup titik asli", box)
+		})
+	}
+}
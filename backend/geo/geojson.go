@@ -0,0 +1,67 @@
+package geo
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+type geoJSONFile struct {
+	Type     string `json:"type"`
+	Features []struct {
+		Properties map[string]interface{} `json:"properties"`
+		Geometry   struct {
+			Type        string          `json:"type"`
+			Coordinates json.RawMessage `json:"coordinates"`
+		} `json:"geometry"`
+	} `json:"features"`
+}
+
+// LoadGeoJSON membaca FeatureCollection berisi Polygon/MultiPolygon sebagai batas wilayah
+func LoadGeoJSON(path string, level Level) ([]*Boundary, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var fc geoJSONFile
+	if err := json.Unmarshal(data, &fc); err != nil {
+		return nil, err
+	}
+	if fc.Type != "FeatureCollection" {
+		return nil, fmt.Errorf("bukan FeatureCollection")
+	}
+
+	var out []*Boundary
+	for i, f := range fc.Features {
+		var rings [][]Point
+		switch f.Geometry.Type {
+		case "Polygon":
+			if err := json.Unmarshal(f.Geometry.Coordinates, &rings); err != nil {
+				return nil, fmt.Errorf("feature %d: %w", i, err)
+			}
+		case "MultiPolygon":
+			var polys [][][]Point
+			if err := json.Unmarshal(f.Geometry.Coordinates, &polys); err != nil {
+				return nil, fmt.Errorf("feature %d: %w", i, err)
+			}
+			for _, p := range polys {
+				rings = append(rings, p...)
+			}
+		default:
+			continue
+		}
+
+		attrs := make(map[string]string, len(f.Properties))
+		for k, v := range f.Properties {
+			if v != nil {
+				attrs[k] = fmt.Sprint(v)
+			}
+		}
+		code := pickAttr(attrs, codeKeys[level])
+		if code == "" {
+			return nil, fmt.Errorf("feature %d: atribut kode wilayah tidak ditemukan", i)
+		}
+		out = append(out, newBoundary(level, code, pickAttr(attrs, nameKeys[level]), rings))
+	}
+	return out, nil
+}
@@ -0,0 +1,147 @@
+package geo
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Tipe shape yang berisi poligon (Polygon, PolygonZ, PolygonM)
+var polygonShapeTypes = map[uint32]bool{5: true, 15: true, 25: true}
+
+// LoadShapefile membaca poligon dari berkas .shp dan atributnya dari .dbf dengan nama yang sama
+func LoadShapefile(path string, level Level) ([]*Boundary, error) {
+	shapes, err := readShp(path)
+	if err != nil {
+		return nil, err
+	}
+	attrs, err := readDbf(strings.TrimSuffix(path, filepath.Ext(path)) + ".dbf")
+	if err != nil {
+		return nil, err
+	}
+	if len(attrs) != len(shapes) {
+		return nil, fmt.Errorf("jumlah record .shp (%d) dan .dbf (%d) berbeda", len(shapes), len(attrs))
+	}
+
+	var out []*Boundary
+	for i, rings := range shapes {
+		if len(rings) == 0 {
+			continue
+		}
+		code := pickAttr(attrs[i], codeKeys[level])
+		if code == "" {
+			return nil, fmt.Errorf("record %d: atribut kode wilayah tidak ditemukan", i+1)
+		}
+		out = append(out, newBoundary(level, code, pickAttr(attrs[i], nameKeys[level]), rings))
+	}
+	return out, nil
+}
+
+// readShp mengembalikan ring setiap record; record bukan poligon bernilai nil
+func readShp(path string) ([][][]Point, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < 100 || binary.BigEndian.Uint32(data[0:4]) != 9994 {
+		return nil, fmt.Errorf("bukan berkas shapefile")
+	}
+
+	var shapes [][][]Point
+	for pos := 100; pos+8 <= len(data); {
+		length := int(binary.BigEndian.Uint32(data[pos+4:pos+8])) * 2
+		start := pos + 8
+		end := start + length
+		if length < 4 || end > len(data) {
+			return nil, fmt.Errorf("record shapefile terpotong")
+		}
+		rec := data[start:end]
+		pos = end
+
+		if !polygonShapeTypes[binary.LittleEndian.Uint32(rec[0:4])] {
+			shapes = append(shapes, nil)
+			continue
+		}
+		if len(rec) < 44 {
+			return nil, fmt.Errorf("record poligon tidak valid")
+		}
+		numParts := int(binary.LittleEndian.Uint32(rec[36:40]))
+		numPoints := int(binary.LittleEndian.Uint32(rec[40:44]))
+		pointsAt := 44 + 4*numParts
+		if pointsAt+16*numPoints > len(rec) {
+			return nil, fmt.Errorf("record poligon tidak valid")
+		}
+
+		parts := make([]int, numParts+1)
+		for i := 0; i < numParts; i++ {
+			parts[i] = int(binary.LittleEndian.Uint32(rec[44+4*i:]))
+		}
+		parts[numParts] = numPoints
+
+		rings := make([][]Point, 0, numParts)
+		for i := 0; i < numParts; i++ {
+			if parts[i] > parts[i+1] {
+				return nil, fmt.Errorf("record poligon tidak valid")
+			}
+			ring := make([]Point, 0, parts[i+1]-parts[i])
+			for k := parts[i]; k < parts[i+1]; k++ {
+				off := pointsAt + 16*k
+				x := math.Float64frombits(binary.LittleEndian.Uint64(rec[off:]))
+				y := math.Float64frombits(binary.LittleEndian.Uint64(rec[off+8:]))
+				ring = append(ring, Point{x, y})
+			}
+			rings = append(rings, ring)
+		}
+		shapes = append(shapes, rings)
+	}
+	return shapes, nil
+}
+
+// readDbf membaca atribut dBASE sebagai teks; record yang ditandai terhapus tetap
+// dihitung agar urutannya sama dengan record .shp
+func readDbf(path string) ([]map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < 32 {
+		return nil, fmt.Errorf("berkas .dbf tidak valid")
+	}
+	numRecords := int(binary.LittleEndian.Uint32(data[4:8]))
+	headerLen := int(binary.LittleEndian.Uint16(data[8:10]))
+	recordLen := int(binary.LittleEndian.Uint16(data[10:12]))
+
+	type field struct {
+		name   string
+		length int
+	}
+	var fields []field
+	for off := 32; off+32 <= headerLen && off < len(data) && data[off] != 0x0D; off += 32 {
+		name := string(bytes.TrimRight(data[off:off+11], "\x00"))
+		fields = append(fields, field{name: name, length: int(data[off+16])})
+	}
+
+	records := make([]map[string]string, 0, numRecords)
+	for i := 0; i < numRecords; i++ {
+		start := headerLen + i*recordLen
+		if start+recordLen > len(data) {
+			return nil, fmt.Errorf("berkas .dbf terpotong")
+		}
+		rec := data[start : start+recordLen]
+		attrs := make(map[string]string, len(fields))
+		off := 1 // byte pertama adalah penanda hapus
+		for _, f := range fields {
+			if off+f.length > len(rec) {
+				break
+			}
+			attrs[f.name] = strings.TrimSpace(string(rec[off : off+f.length]))
+			off += f.length
+		}
+		records = append(records, attrs)
+	}
+	return records, nil
+}
@@ -0,0 +1,79 @@
+package jobs
+
+import (
+	"encoding/json"
+	"log"
+	"project-backend/scheduler"
+	"strconv"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type cronEntry struct {
+	Name    string    `json:"name"`
+	Spec    string    `json:"spec"`
+	Type    string    `json:"type"`
+	NextRun time.Time `json:"next_run_at"`
+
+	cron    *scheduler.Cron
+	payload json.RawMessage
+}
+
+var (
+	cronMu  sync.Mutex
+	entries []*cronEntry
+)
+
+// Schedule mendaftarkan job typ yang dimasukkan ke antrean sesuai ekspresi cron spec.
+// Setiap waktu jadwal hanya menghasilkan satu job walau ada beberapa proses server
+// (lewat UniqueKey). Jadwal yang terlewat saat server mati tidak dijalankan susulan.
+// Harus dipanggil saat start-up.
+func Schedule(name, spec, typ string, payload interface{}) error {
+	c, err := scheduler.ParseCron(spec)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	cronMu.Lock()
+	defer cronMu.Unlock()
+	entries = append(entries, &cronEntry{
+		Name: name, Spec: spec, Type: typ,
+		NextRun: c.Next(time.Now()),
+		cron:    c, payload: data,
+	})
+	return nil
+}
+
+// Schedules mengembalikan jadwal terdaftar beserta waktu berikutnya
+func Schedules() []cronEntry {
+	cronMu.Lock()
+	defer cronMu.Unlock()
+	out := make([]cronEntry, len(entries))
+	for i, e := range entries {
+		out[i] = *e
+	}
+	return out
+}
+
+// enqueueDue memasukkan job terjadwal yang waktunya sudah tiba
+func enqueueDue(db *gorm.DB, now time.Time) {
+	cronMu.Lock()
+	defer cronMu.Unlock()
+	for _, e := range entries {
+		if e.NextRun.IsZero() || now.Before(e.NextRun) {
+			continue
+		}
+		key := "cron:" + e.Name + ":" + strconv.FormatInt(e.NextRun.Unix(), 10)
+		if _, err := Enqueue(db, e.Type, e.payload, EnqueueOptions{RunAt: e.NextRun, UniqueKey: key}); err != nil {
+			// dicoba lagi pada tick berikutnya
+			log.Printf("jobs: gagal menjadwalkan %s: %v", e.Name, err)
+			continue
+		}
+		e.NextRun = e.cron.Next(now)
+	}
+}
@@ -0,0 +1,182 @@
+// Package jobs adalah antrean pekerjaan latar berbasis database. Setiap jenis job
+// punya handler bertipe (payload JSON dibaca ke struct handler), batas percobaan
+// dengan jeda yang makin panjang, batas job berjalan bersamaan per jenis, dan
+// penjadwalan dengan ekspresi cron. Job yang habis percobaan menjadi dead dan
+// bisa dicoba ulang lewat RetryDead.
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"project-backend/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Nilai bawaan Options
+const (
+	DefaultMaxAttempts = 5
+	DefaultTimeout     = 5 * time.Minute
+	defaultBackoffBase = 10 * time.Second
+	maxBackoff         = time.Hour
+)
+
+// Options mengatur eksekusi satu jenis job. Nilai nol memakai bawaan.
+type Options struct {
+	// Batas percobaan sebelum job menjadi dead
+	MaxAttempts int
+	// Batas job jenis ini yang berjalan bersamaan di satu proses (0 = hanya dibatasi
+	// jumlah worker)
+	Concurrency int
+	// Batas waktu satu percobaan; context handler dibatalkan setelahnya
+	Timeout time.Duration
+	// Backoff mengembalikan jeda sebelum percobaan berikutnya setelah attempt kali gagal.
+	// Bawaan: 10 detik dikali 2^(attempt-1), paling lama 1 jam.
+	Backoff func(attempt int) time.Duration
+}
+
+func (o Options) withDefaults() Options {
+	if o.MaxAttempts <= 0 {
+		o.MaxAttempts = DefaultMaxAttempts
+	}
+	if o.Timeout <= 0 {
+		o.Timeout = DefaultTimeout
+	}
+	if o.Backoff == nil {
+		o.Backoff = ExponentialBackoff(defaultBackoffBase)
+	}
+	return o
+}
+
+// ExponentialBackoff mengembalikan backoff base * 2^(attempt-1), paling lama 1 jam
+func ExponentialBackoff(base time.Duration) func(int) time.Duration {
+	return func(attempt int) time.Duration {
+		d := base << (attempt - 1)
+		if d > maxBackoff || d <= 0 {
+			d = maxBackoff
+		}
+		return d
+	}
+}
+
+type handler struct {
+	opts Options
+	run  func(ctx context.Context, job models.Job) error
+}
+
+var handlers = map[string]handler{}
+
+// Register mendaftarkan handler jenis job typ. Payload job dibaca dari JSON ke T;
+// payload yang tidak bisa dibaca membuat job langsung dead. fn harus memakai ctx
+// (mis. db.WithContext(ctx)) agar berhenti saat Timeout habis; job yang melewati
+// Timeout tetap dianggap berjalan sampai reap. Harus dipanggil saat start-up sebelum
+// worker berjalan.
+func Register[T any](typ string, opts Options, fn func(ctx context.Context, payload T) error) {
+	handlers[typ] = handler{
+		opts: opts.withDefaults(),
+		run: func(ctx context.Context, job models.Job) error {
+			var payload T
+			if job.Payload != "" {
+				if err := json.Unmarshal([]byte(job.Payload), &payload); err != nil {
+					return Permanent(fmt.Errorf("payload tidak valid: %w", err))
+				}
+			}
+			return fn(ctx, payload)
+		},
+	}
+}
+
+// Types mengembalikan jenis job yang terdaftar
+func Types() []string {
+	types := make([]string, 0, len(handlers))
+	for t := range handlers {
+		types = append(types, t)
+	}
+	return types
+}
+
+// permanentError menandai kegagalan yang tidak perlu dicoba ulang
+type permanentError struct{ err error }
+
+func (e permanentError) Error() string { return e.err.Error() }
+func (e permanentError) Unwrap() error { return e.err }
+
+// Permanent membungkus err agar job langsung menjadi dead tanpa dicoba ulang
+func Permanent(err error) error {
+	return permanentError{err}
+}
+
+func isPermanent(err error) bool {
+	var p permanentError
+	return errors.As(err, &p)
+}
+
+// EnqueueOptions mengatur job yang dimasukkan ke antrean
+type EnqueueOptions struct {
+	// Waktu paling awal job dijalankan (nol = sekarang)
+	RunAt time.Time
+	// UniqueKey, jika diisi, mencegah job dengan kunci yang sama dimasukkan dua kali
+	UniqueKey string
+}
+
+// Enqueue memasukkan job ke antrean. db boleh berupa transaksi agar job hanya tersimpan
+// jika perubahan datanya tersimpan. Jika UniqueKey sudah ada, job tidak dibuat dan
+// Job.ID bernilai 0.
+func Enqueue(db *gorm.DB, typ string, payload interface{}, opts EnqueueOptions) (models.Job, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return models.Job{}, err
+	}
+	maxAttempts := DefaultMaxAttempts
+	if h, ok := handlers[typ]; ok {
+		maxAttempts = h.opts.MaxAttempts
+	}
+	job := models.Job{
+		Type:        typ,
+		Payload:     string(data),
+		Status:      models.JobQueued,
+		MaxAttempts: maxAttempts,
+		RunAt:       opts.RunAt,
+	}
+	if job.RunAt.IsZero() {
+		job.RunAt = time.Now()
+	}
+	if opts.UniqueKey != "" {
+		key := opts.UniqueKey
+		job.UniqueKey = &key
+	}
+	err = db.Clauses(clause.OnConflict{DoNothing: true}).Create(&job).Error
+	return job, err
+}
+
+// RetryDead memasukkan kembali job dead ke antrean dengan hitungan percobaan dari nol
+func RetryDead(db *gorm.DB, id uint) (models.Job, error) {
+	var job models.Job
+	res := db.Model(&models.Job{}).Where("id = ? AND status = ?", id, models.JobDead).Updates(map[string]interface{}{
+		"status":      models.JobQueued,
+		"attempts":    0,
+		"run_at":      time.Now(),
+		"locked_by":   "",
+		"locked_at":   nil,
+		"finished_at": nil,
+	})
+	if res.Error != nil {
+		return job, res.Error
+	}
+	if res.RowsAffected == 0 {
+		return job, ErrNotDead
+	}
+	return job, db.First(&job, id).Error
+}
+
+// ErrNotDead dikembalikan RetryDead jika job tidak ada atau bukan dead
+var ErrNotDead = errors.New("job tidak ditemukan atau tidak berstatus dead")
+
+// Cleanup menghapus job sukses yang selesai sebelum before
+func Cleanup(db *gorm.DB, before time.Time) error {
+	return db.Where("status = ? AND finished_at < ?", models.JobSucceeded, before).Delete(&models.Job{}).Error
+}
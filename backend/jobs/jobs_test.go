@@ -0,0 +1,55 @@
+package jobs
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestExponentialBackoff(t *testing.T) {
+	backoff := ExponentialBackoff(10 * time.Second)
+	tests := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{1, 10 * time.Second},
+		{2, 20 * time.Second},
+		{3, 40 * time.Second},
+		{8, 1280 * time.Second},
+		{9, 2560 * time.Second},
+		{10, time.Hour}, // 5120 detik dibatasi 1 jam
+		{64, time.Hour}, // geser melewati 64 bit
+		{200, time.Hour},
+	}
+	for _, tt := range tests {
+		if got := backoff(tt.attempt); got != tt.want {
+			t.Errorf("backoff(%d) = %v, want %v", tt.attempt, got, tt.want)
+		}
+	}
+}
+
+func TestOptionsDefaults(t *testing.T) {
+	o := Options{}.withDefaults()
+	if o.MaxAttempts != DefaultMaxAttempts || o.Timeout != DefaultTimeout {
+		t.Errorf("withDefaults = %+v", o)
+	}
+	if got := o.Backoff(1); got != defaultBackoffBase {
+		t.Errorf("Backoff(1) bawaan = %v, want %v", got, defaultBackoffBase)
+	}
+
+	custom := Options{MaxAttempts: 2, Timeout: time.Minute, Backoff: func(int) time.Duration { return time.Second }}.withDefaults()
+	if custom.MaxAttempts != 2 || custom.Timeout != time.Minute || custom.Backoff(5) != time.Second {
+		t.Errorf("nilai yang diisi tidak boleh diganti: %+v", custom)
+	}
+}
+
+func TestPermanent(t *testing.T) {
+	base := errors.New("payload rusak")
+	err := Permanent(base)
+	if !isPermanent(err) || !errors.Is(err, base) {
+		t.Errorf("Permanent(%v) harus permanen dan membungkus error aslinya", base)
+	}
+	if isPermanent(base) {
+		t.Error("error biasa tidak boleh dianggap permanen")
+	}
+}
@@ -0,0 +1,242 @@
+package jobs
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"os"
+	"project-backend/models"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	pollInterval = time.Second
+	// Tambahan waktu setelah Timeout sebelum job running dianggap ditinggal workernya
+	// (mis. proses mati) dan dikembalikan ke antrean
+	staleGrace = time.Minute
+)
+
+// Worker mengambil dan menjalankan job dari antrean. Beberapa worker (di proses yang
+// sama atau berbeda) aman berjalan bersamaan karena setiap job diklaim secara atomik.
+type Worker struct {
+	db          *gorm.DB
+	id          string
+	concurrency int
+
+	mu      sync.Mutex
+	running map[string]int // job berjalan per jenis
+	active  int
+
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+// NewWorker membuat worker yang menjalankan paling banyak concurrency job sekaligus
+func NewWorker(db *gorm.DB, concurrency int) *Worker {
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+	host, _ := os.Hostname()
+	b := make([]byte, 4)
+	rand.Read(b)
+	return &Worker{
+		db:          db,
+		id:          fmt.Sprintf("%s-%d-%s", host, os.Getpid(), hex.EncodeToString(b)),
+		concurrency: concurrency,
+		running:     map[string]int{},
+		stop:        make(chan struct{}),
+	}
+}
+
+// Start menjalankan worker di background
+func (w *Worker) Start() {
+	w.wg.Add(1)
+	go w.loop()
+}
+
+// Stop berhenti mengambil job baru dan menunggu job yang sedang berjalan selesai
+func (w *Worker) Stop() {
+	close(w.stop)
+	w.wg.Wait()
+}
+
+func (w *Worker) loop() {
+	defer w.wg.Done()
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-w.stop:
+			return
+		case now := <-ticker.C:
+			enqueueDue(w.db, now)
+			if err := w.reap(now); err != nil {
+				log.Printf("jobs: gagal memulihkan job macet: %v", err)
+			}
+			if err := w.claim(now); err != nil {
+				log.Printf("jobs: gagal mengambil job: %v", err)
+			}
+		}
+	}
+}
+
+// eligibleTypes mengembalikan jenis job yang masih punya slot dan jumlah slot worker
+// yang kosong
+func (w *Worker) eligibleTypes() ([]string, int) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	free := w.concurrency - w.active
+	var types []string
+	for typ, h := range handlers {
+		if h.opts.Concurrency == 0 || w.running[typ] < h.opts.Concurrency {
+			types = append(types, typ)
+		}
+	}
+	return types, free
+}
+
+// reserve memesan slot untuk job typ; false jika batas worker atau jenis sudah penuh
+func (w *Worker) reserve(typ string) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	limit := handlers[typ].opts.Concurrency
+	if w.active >= w.concurrency || (limit > 0 && w.running[typ] >= limit) {
+		return false
+	}
+	w.active++
+	w.running[typ]++
+	return true
+}
+
+func (w *Worker) release(typ string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.active--
+	w.running[typ]--
+}
+
+func (w *Worker) claim(now time.Time) error {
+	types, free := w.eligibleTypes()
+	if free <= 0 || len(types) == 0 {
+		return nil
+	}
+	var candidates []models.Job
+	if err := w.db.Where("status = ? AND run_at <= ? AND type IN ?", models.JobQueued, now, types).
+		Order("run_at, id").Limit(free * 2).Find(&candidates).Error; err != nil {
+		return err
+	}
+
+	for _, job := range candidates {
+		if !w.reserve(job.Type) {
+			continue
+		}
+		res := w.db.Model(&models.Job{}).
+			Where("id = ? AND status = ?", job.ID, models.JobQueued).
+			Updates(map[string]interface{}{
+				"status":    models.JobRunning,
+				"locked_by": w.id,
+				"locked_at": now,
+				"attempts":  gorm.Expr("attempts + 1"),
+			})
+		if res.Error != nil || res.RowsAffected == 0 {
+			w.release(job.Type)
+			if res.Error != nil {
+				return res.Error
+			}
+			continue
+		}
+		job.Attempts++
+		job.Status = models.JobRunning
+
+		w.wg.Add(1)
+		go func(job models.Job) {
+			defer w.wg.Done()
+			defer w.release(job.Type)
+			w.execute(job)
+		}(job)
+	}
+	return nil
+}
+
+func (w *Worker) execute(job models.Job) {
+	h := handlers[job.Type]
+	ctx, cancel := context.WithTimeout(context.Background(), h.opts.Timeout)
+	err := run(ctx, h, job)
+	cancel()
+
+	now := time.Now()
+	updates := map[string]interface{}{"locked_by": "", "locked_at": nil}
+	switch {
+	case err == nil:
+		updates["status"] = models.JobSucceeded
+		updates["finished_at"] = now
+		updates["last_error"] = ""
+	case isPermanent(err) || job.Attempts >= job.MaxAttempts:
+		updates["status"] = models.JobDead
+		updates["finished_at"] = now
+		updates["last_error"] = err.Error()
+		log.Printf("jobs: job %d (%s) dead setelah %d percobaan: %v", job.ID, job.Type, job.Attempts, err)
+	default:
+		updates["status"] = models.JobQueued
+		updates["run_at"] = now.Add(h.opts.Backoff(job.Attempts))
+		updates["last_error"] = err.Error()
+	}
+	// hanya jika job masih dipegang worker ini (belum dipulihkan reap)
+	res := w.db.Model(&models.Job{}).
+		Where("id = ? AND status = ? AND locked_by = ?", job.ID, models.JobRunning, w.id).
+		Updates(updates)
+	if res.Error != nil {
+		log.Printf("jobs: gagal menyimpan hasil job %d: %v", job.ID, res.Error)
+	}
+}
+
+// run menjalankan handler dan mengubah panic menjadi error
+func run(ctx context.Context, h handler, job models.Job) (err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("panic: %v", p)
+		}
+	}()
+	return h.run(ctx, job)
+}
+
+// reap mengembalikan job running yang melewati Timeout+staleGrace (workernya mati
+// atau macet) ke antrean, atau menjadikannya dead jika percobaan sudah habis
+func (w *Worker) reap(now time.Time) error {
+	var stale []models.Job
+	if err := w.db.Where("status = ? AND locked_at < ?", models.JobRunning, now.Add(-staleGrace)).
+		Find(&stale).Error; err != nil {
+		return err
+	}
+	for _, job := range stale {
+		timeout := DefaultTimeout
+		if h, ok := handlers[job.Type]; ok {
+			timeout = h.opts.Timeout
+		}
+		if job.LockedAt == nil || now.Sub(*job.LockedAt) < timeout+staleGrace {
+			continue
+		}
+		updates := map[string]interface{}{
+			"status":     models.JobQueued,
+			"run_at":     now,
+			"locked_by":  "",
+			"locked_at":  nil,
+			"last_error": "worker " + job.LockedBy + " tidak menyelesaikan job dalam batas waktu",
+		}
+		if job.Attempts >= job.MaxAttempts {
+			updates["status"] = models.JobDead
+			updates["finished_at"] = now
+		}
+		if err := w.db.Model(&models.Job{}).
+			Where("id = ? AND status = ? AND locked_at = ?", job.ID, models.JobRunning, *job.LockedAt).
+			Updates(updates).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package mailer
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// FileMailer adalah penampung email untuk pengembangan: setiap email ditulis ke Dir
+// sebagai berkas .eml (bisa dibuka di klien email) beserta salinan .html untuk dilihat
+// langsung di browser. Tidak ada email yang benar-benar dikirim.
+type FileMailer struct {
+	Dir  string
+	From string
+}
+
+func (m FileMailer) Send(msg Message) error {
+	if err := os.MkdirAll(m.Dir, 0o755); err != nil {
+		return err
+	}
+	name := time.Now().Format("20060102-150405.000") + "-" + randomID()[:6] + "-" + slug(msg.Subject)
+	base := filepath.Join(m.Dir, name)
+	if err := os.WriteFile(base+".eml", Build(m.From, msg), 0o644); err != nil {
+		return err
+	}
+	if msg.HTML != "" {
+		return os.WriteFile(base+".html", []byte(msg.HTML), 0o644)
+	}
+	return nil
+}
+
+// slug membuat potongan nama berkas yang aman dari subjek email
+func slug(s string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(s) {
+		if b.Len() >= 40 {
+			break
+		}
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			b.WriteRune(r)
+		case b.Len() > 0 && !strings.HasSuffix(b.String(), "-"):
+			b.WriteByte('-')
+		}
+	}
+	return strings.Trim(b.String(), "-")
+}
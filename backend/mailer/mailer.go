@@ -0,0 +1,79 @@
+// Package mailer mengirim email lewat interface Mailer sehingga pengirim (SMTP,
+// log untuk pengembangan) bisa diganti tanpa mengubah pemanggil.
+package mailer
+
+import (
+	"errors"
+	"log"
+	"strings"
+)
+
+// Attachment adalah berkas lampiran email
+type Attachment struct {
+	Filename    string
+	ContentType string
+	Data        []byte
+}
+
+// Message adalah satu email. Text wajib diisi; HTML opsional dan dikirim sebagai
+// alternatif dari Text.
+type Message struct {
+	To          []string
+	Subject     string
+	Text        string
+	HTML        string
+	Headers     map[string]string // header tambahan, mis. List-Unsubscribe
+	Attachments []Attachment
+}
+
+// Mailer mengirim email
+type Mailer interface {
+	Send(msg Message) error
+}
+
+var (
+	ErrNoRecipient = errors.New("email tanpa penerima")
+	ErrDisabled    = errors.New("pengiriman email belum dikonfigurasi")
+)
+
+var defaultMailer Mailer = LogMailer{}
+
+// Init mengganti mailer bawaan yang dipakai Send
+func Init(m Mailer) {
+	defaultMailer = m
+}
+
+// Default mengembalikan mailer bawaan
+func Default() Mailer {
+	return defaultMailer
+}
+
+// Send mengirim email lewat mailer bawaan
+func Send(msg Message) error {
+	if len(msg.To) == 0 {
+		return ErrNoRecipient
+	}
+	return defaultMailer.Send(msg)
+}
+
+// LogMailer tidak mengirim apa pun, hanya mencatat ringkasan email di log.
+// Dipakai saat SMTP belum dikonfigurasi.
+type LogMailer struct{}
+
+func (LogMailer) Send(msg Message) error {
+	names := make([]string, len(msg.Attachments))
+	for i, a := range msg.Attachments {
+		names[i] = a.Filename
+	}
+	log.Printf("mailer: (tidak dikirim) ke=%s subjek=%q lampiran=%v",
+		strings.Join(msg.To, ","), msg.Subject, names)
+	return nil
+}
+
+// DisabledMailer menolak setiap email dengan ErrDisabled sehingga antrean mencatat
+// kegagalannya. Dipakai saat SMTP belum dikonfigurasi di luar pengembangan.
+type DisabledMailer struct{}
+
+func (DisabledMailer) Send(msg Message) error {
+	return ErrDisabled
+}
@@ -0,0 +1,101 @@
+package mailer
+
+import (
+	"encoding/json"
+	"project-backend/models"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	// Batas percobaan kirim sebelum email ditandai failed
+	maxAttempts = 6
+	// Jeda percobaan ulang pertama; berikutnya dua kali lipat (1, 2, 4, 8, 16 menit)
+	retryBase = time.Minute
+	// Email diambil per batch agar satu tick tidak berjalan terlalu lama
+	queueBatch = 50
+	// Lama klaim; email yang diklaim proses lain tidak diambil selama jeda ini
+	claimTimeout = 5 * time.Minute
+)
+
+// Enqueue memasukkan email ke antrean kirim. Lampiran tidak didukung di antrean;
+// email berlampiran dikirim langsung dengan Send.
+func Enqueue(db *gorm.DB, msg Message) error {
+	return EnqueueOnce(db, "", msg)
+}
+
+// EnqueueOnce seperti Enqueue, tetapi email dengan dedupKey yang sama hanya
+// dimasukkan sekali. dedupKey kosong berarti tanpa pemeriksaan duplikat.
+func EnqueueOnce(db *gorm.DB, dedupKey string, msg Message) error {
+	if len(msg.To) == 0 {
+		return ErrNoRecipient
+	}
+	headers, _ := json.Marshal(msg.Headers)
+	row := models.EmailMessage{
+		To:            strings.Join(msg.To, ","),
+		Subject:       msg.Subject,
+		Text:          msg.Text,
+		HTML:          msg.HTML,
+		Headers:       string(headers),
+		Status:        models.EmailPending,
+		NextAttemptAt: time.Now(),
+	}
+	if dedupKey != "" {
+		row.DedupKey = &dedupKey
+	}
+	return db.Clauses(clause.OnConflict{DoNothing: true}).Create(&row).Error
+}
+
+// retryDelay mengembalikan jeda sebelum percobaan berikutnya setelah attempts kali gagal
+func retryDelay(attempts int) time.Duration {
+	return retryBase << (attempts - 1)
+}
+
+// ProcessQueue mengirim email yang sudah jatuh tempo lewat mailer bawaan. Setiap email
+// diklaim dengan menggeser next_attempt_at secara atomik agar tidak terkirim ganda
+// jika ada beberapa proses. Mengembalikan jumlah email yang terkirim.
+func ProcessQueue(db *gorm.DB, now time.Time) (int, error) {
+	var due []models.EmailMessage
+	if err := db.Where("status = ? AND next_attempt_at <= ?", models.EmailPending, now).
+		Order("next_attempt_at").Limit(queueBatch).Find(&due).Error; err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for _, e := range due {
+		claim := db.Model(&models.EmailMessage{}).
+			Where("id = ? AND status = ? AND next_attempt_at = ?", e.ID, models.EmailPending, e.NextAttemptAt).
+			Update("next_attempt_at", now.Add(claimTimeout))
+		if claim.Error != nil {
+			return sent, claim.Error
+		}
+		if claim.RowsAffected == 0 {
+			continue
+		}
+
+		msg := Message{To: strings.Split(e.To, ","), Subject: e.Subject, Text: e.Text, HTML: e.HTML}
+		json.Unmarshal([]byte(e.Headers), &msg.Headers)
+
+		updates := map[string]interface{}{"attempts": e.Attempts + 1}
+		if err := defaultMailer.Send(msg); err != nil {
+			updates["last_error"] = err.Error()
+			if e.Attempts+1 >= maxAttempts {
+				updates["status"] = models.EmailFailed
+			} else {
+				updates["next_attempt_at"] = now.Add(retryDelay(e.Attempts + 1))
+			}
+		} else {
+			updates["status"] = models.EmailSent
+			updates["sent_at"] = now
+			updates["last_error"] = ""
+			sent++
+		}
+		if err := db.Model(&models.EmailMessage{}).Where("id = ?", e.ID).Updates(updates).Error; err != nil {
+			return sent, err
+		}
+	}
+	return sent, nil
+}
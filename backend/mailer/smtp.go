@@ -0,0 +1,151 @@
+package mailer
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"mime"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/smtp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SMTPMailer mengirim email lewat server SMTP (STARTTLS otomatis jika didukung server)
+type SMTPMailer struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string // "Nama <alamat>" atau alamat saja
+}
+
+func (m SMTPMailer) Send(msg Message) error {
+	from, err := mail.ParseAddress(m.From)
+	if err != nil {
+		return fmt.Errorf("alamat pengirim tidak valid: %w", err)
+	}
+	var auth smtp.Auth
+	if m.Username != "" {
+		auth = smtp.PlainAuth("", m.Username, m.Password, m.Host)
+	}
+	return smtp.SendMail(m.Host+":"+strconv.Itoa(m.Port), auth, from.Address, msg.To, Build(m.From, msg))
+}
+
+// Build menyusun email MIME lengkap (header + body) dari msg
+func Build(from string, msg Message) []byte {
+	var b bytes.Buffer
+	header := func(k, v string) { b.WriteString(k + ": " + v + "\r\n") }
+	header("From", from)
+	header("To", strings.Join(msg.To, ", "))
+	header("Subject", mime.QEncoding.Encode("utf-8", msg.Subject))
+	header("Date", time.Now().Format(time.RFC1123Z))
+	header("Message-ID", "<"+randomID()+"@"+domainOf(from)+">")
+	header("MIME-Version", "1.0")
+	keys := make([]string, 0, len(msg.Headers))
+	for k := range msg.Headers {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		header(k, msg.Headers[k])
+	}
+
+	body, contentType := buildBody(msg)
+	header("Content-Type", contentType)
+	b.WriteString("\r\n")
+	b.Write(body)
+	return b.Bytes()
+}
+
+// buildBody mengembalikan isi dan Content-Type: text saja, multipart/alternative
+// (text + html), dan dibungkus multipart/mixed jika ada lampiran
+func buildBody(msg Message) ([]byte, string) {
+	var body []byte
+	var contentType string
+	if msg.HTML == "" {
+		body, contentType = quotedPrintable(msg.Text), `text/plain; charset="utf-8"`
+	} else {
+		boundary := "alt-" + randomID()
+		var b bytes.Buffer
+		writePart(&b, boundary, `text/plain; charset="utf-8"`, "quoted-printable", "", quotedPrintable(msg.Text))
+		writePart(&b, boundary, `text/html; charset="utf-8"`, "quoted-printable", "", quotedPrintable(msg.HTML))
+		b.WriteString("--" + boundary + "--\r\n")
+		body, contentType = b.Bytes(), `multipart/alternative; boundary="`+boundary+`"`
+	}
+	if len(msg.Attachments) == 0 {
+		return body, contentType
+	}
+
+	boundary := "mix-" + randomID()
+	var b bytes.Buffer
+	b.WriteString("--" + boundary + "\r\nContent-Type: " + contentType + "\r\n")
+	if msg.HTML == "" {
+		b.WriteString("Content-Transfer-Encoding: quoted-printable\r\n")
+	}
+	b.WriteString("\r\n")
+	b.Write(body)
+	b.WriteString("\r\n")
+	for _, a := range msg.Attachments {
+		ct := a.ContentType
+		if ct == "" {
+			ct = "application/octet-stream"
+		}
+		disposition := mime.FormatMediaType("attachment", map[string]string{"filename": a.Filename})
+		writePart(&b, boundary, ct, "base64", disposition, base64Lines(a.Data))
+	}
+	b.WriteString("--" + boundary + "--\r\n")
+	return b.Bytes(), `multipart/mixed; boundary="` + boundary + `"`
+}
+
+func writePart(b *bytes.Buffer, boundary, contentType, encoding, disposition string, data []byte) {
+	b.WriteString("--" + boundary + "\r\n")
+	b.WriteString("Content-Type: " + contentType + "\r\n")
+	b.WriteString("Content-Transfer-Encoding: " + encoding + "\r\n")
+	if disposition != "" {
+		b.WriteString("Content-Disposition: " + disposition + "\r\n")
+	}
+	b.WriteString("\r\n")
+	b.Write(data)
+	b.WriteString("\r\n")
+}
+
+func quotedPrintable(s string) []byte {
+	var b bytes.Buffer
+	w := quotedprintable.NewWriter(&b)
+	w.Write([]byte(s))
+	w.Close()
+	return b.Bytes()
+}
+
+// base64Lines mengodekan data dengan baris maksimal 76 karakter (RFC 2045)
+func base64Lines(data []byte) []byte {
+	enc := base64.StdEncoding.EncodeToString(data)
+	var b bytes.Buffer
+	for len(enc) > 76 {
+		b.WriteString(enc[:76] + "\r\n")
+		enc = enc[76:]
+	}
+	b.WriteString(enc)
+	return b.Bytes()
+}
+
+func randomID() string {
+	buf := make([]byte, 12)
+	rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
+
+func domainOf(from string) string {
+	if addr, err := mail.ParseAddress(from); err == nil {
+		if i := strings.LastIndex(addr.Address, "@"); i >= 0 {
+			return addr.Address[i+1:]
+		}
+	}
+	return "localhost"
+}
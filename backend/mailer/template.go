@@ -0,0 +1,63 @@
+package mailer
+
+import (
+	"bytes"
+	"embed"
+	htmltemplate "html/template"
+	"strings"
+	texttemplate "text/template"
+)
+
+// Bahasa template email. Bahasa lain jatuh ke DefaultLanguage.
+const (
+	LanguageID      = "id"
+	LanguageEN      = "en"
+	DefaultLanguage = LanguageID
+)
+
+// Setiap template berupa templates/<bahasa>/<nama>.txt (blok "subject" dan "text")
+// dan templates/<bahasa>/<nama>.html (blok "content") yang dibungkus layout.html.
+//
+//go:embed templates
+var templateFS embed.FS
+
+// IsSupportedLanguage memeriksa apakah tersedia template untuk bahasa lang
+func IsSupportedLanguage(lang string) bool {
+	return lang == LanguageID || lang == LanguageEN
+}
+
+// Render mengisi template name dalam bahasa lang dengan data dan mengembalikan Message
+// berisi Subject, Text dan HTML (tanpa penerima)
+func Render(lang, name string, data interface{}) (Message, error) {
+	if !IsSupportedLanguage(lang) {
+		lang = DefaultLanguage
+	}
+	dir := "templates/" + lang + "/"
+
+	text, err := texttemplate.ParseFS(templateFS, dir+name+".txt")
+	if err != nil {
+		return Message{}, err
+	}
+	var subject, body bytes.Buffer
+	if err := text.ExecuteTemplate(&subject, "subject", data); err != nil {
+		return Message{}, err
+	}
+	if err := text.ExecuteTemplate(&body, "text", data); err != nil {
+		return Message{}, err
+	}
+
+	html, err := htmltemplate.ParseFS(templateFS, dir+"layout.html", dir+name+".html")
+	if err != nil {
+		return Message{}, err
+	}
+	var page bytes.Buffer
+	if err := html.ExecuteTemplate(&page, "layout", data); err != nil {
+		return Message{}, err
+	}
+
+	return Message{
+		Subject: strings.TrimSpace(subject.String()),
+		Text:    strings.TrimSpace(body.String()) + "\n",
+		HTML:    page.String(),
+	}, nil
+}
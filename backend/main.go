@@ -1,8 +1,25 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
 	"project-backend/config"
+	"project-backend/controllers"
+	"project-backend/geo"
+	"project-backend/jobs"
+	"project-backend/mailer"
+	"project-backend/messaging"
+	"project-backend/notification"
+	"project-backend/outbox"
 	"project-backend/routes"
+	"project-backend/scheduler"
+	"project-backend/search"
+	"project-backend/webhook"
+	"syscall"
 	"time"
 
 	"github.com/gin-contrib/cors"
@@ -30,13 +47,122 @@ func main() {
 	// Koneksi database
 	config.Connect()
 
+	// Indeks pencarian full-text (FULLTEXT di MySQL, FTS5 di SQLite; pencarian LIKE jika gagal)
+	if err := search.Init(config.DB); err != nil {
+		log.Println("Search index setup failed, using LIKE search:", err)
+	}
+
+	// Batas wilayah administrasi untuk menentukan kode wilayah laporan
+	if err := geo.InitBoundaries("data/wilayah"); err != nil {
+		log.Println("Boundary data not loaded, region lookup disabled:", err)
+	} else if geo.Boundaries() == nil {
+		log.Println("No boundary files in data/wilayah, region lookup disabled")
+	} else if _, err := controllers.SyncRegions(geo.Boundaries()); err != nil {
+		log.Println("Region sync failed:", err)
+	}
+
+	// Notifikasi in-app, diteruskan juga ke koneksi real-time
+	notification.Init(config.DB)
+	notification.OnCreated(controllers.PushNotification)
+
+	// Email keluar lewat SMTP; tanpa SMTP email ditolak, kecuali folder penampung
+	// pengembangan diisi lewat MAIL_SINK_DIR
+	switch {
+	case config.SMTPHost != "":
+		mailer.Init(mailer.SMTPMailer{
+			Host:     config.SMTPHost,
+			Port:     config.SMTPPort,
+			Username: config.SMTPUsername,
+			Password: config.SMTPPassword,
+			From:     config.MailFrom,
+		})
+	case config.MailSinkDir != "":
+		log.Println("SMTP not configured, writing outgoing email to", config.MailSinkDir)
+		mailer.Init(mailer.FileMailer{Dir: config.MailSinkDir, From: config.MailFrom})
+	default:
+		log.Println("SMTP not configured, outgoing email is disabled")
+		mailer.Init(mailer.DisabledMailer{})
+	}
+	// Email yang benar-benar dikirim wajib memuat tautan berhenti berlangganan bertanda tangan
+	if (config.SMTPHost != "" || config.MailSinkDir != "") && config.UnsubscribeSecret == "" {
+		log.Fatal("UNSUBSCRIBE_SECRET is not set but outgoing email is enabled, refusing to start")
+	}
+
+	// Notifikasi WhatsApp/SMS; tanpa gateway pesan tidak dikirim
+	if config.MessagingURL == "" {
+		log.Println("MESSAGING_URL not set, WhatsApp/SMS notifications are disabled")
+	} else {
+		messaging.Init(messaging.HTTPGateway{
+			URL:    config.MessagingURL,
+			Token:  config.MessagingToken,
+			Sender: config.MessagingSender,
+		})
+	}
+
+	// Consumer event outbox (notifikasi, email, WhatsApp/SMS, webhook)
+	controllers.RegisterOutboxConsumers()
+
+	// Tugas berkala berfrekuensi tinggi (antrean kirim) di dalam proses server. Antrean
+	// email, WhatsApp/SMS, webhook dan outbox sengaja tidak dijadikan job: setiap baris
+	// antreannya sudah menyimpan status, jumlah percobaan dan jadwal ulangnya sendiri
+	// (dengan klaim atomik dan batas per nomor), dan membungkusnya dengan job hanya
+	// menambah satu baris job per tick tanpa jaminan tambahan.
+	runner := scheduler.New()
+	runner.Every("outbox-dispatch", 2*time.Second, func(now time.Time) error {
+		_, err := outbox.Dispatch(config.DB, now)
+		return err
+	})
+	runner.Every("email-queue", 30*time.Second, func(now time.Time) error {
+		_, err := mailer.ProcessQueue(config.DB, now)
+		return err
+	})
+	runner.Every("messaging-queue", 15*time.Second, func(now time.Time) error {
+		_, err := messaging.ProcessQueue(config.DB, now)
+		return err
+	})
+	runner.Every("webhook-deliveries", 15*time.Second, func(now time.Time) error {
+		_, err := webhook.ProcessQueue(config.DB, now)
+		return err
+	})
+	runner.Start()
+
+	// Antrean job latar; tugas berjadwal (langganan, cek SLA, pembersihan) berjalan
+	// sebagai job cron agar bisa dicoba ulang dan diperiksa admin
+	if err := controllers.RegisterJobs(); err != nil {
+		log.Println("Job registration failed:", err)
+	}
+	worker := jobs.NewWorker(config.DB, config.JobWorkers)
+	worker.Start()
+
 	// Daftarkan route
 	routes.AuthRoutes(r)
 	routes.ReportRoutes(r)
 	routes.UserRoutes(r)
 	routes.AdminRoutes(r)
 	routes.CategoryRoutes(r)
+	routes.RegionRoutes(r)
+	routes.NotificationRoutes(r)
 
 	// Jalankan server
-	r.Run(":8080")
+	srv := &http.Server{Addr: ":8080", Handler: r}
+	go func() {
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatal("Server failed:", err)
+		}
+	}()
+
+	// Berhenti dengan rapi saat SIGINT/SIGTERM: tolak request baru, lalu tunggu tugas
+	// berkala dan job yang sedang berjalan selesai agar tidak tertinggal berstatus running
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	<-ctx.Done()
+	log.Println("Shutting down...")
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		log.Println("Server shutdown:", err)
+	}
+	runner.Stop()
+	worker.Stop()
 }
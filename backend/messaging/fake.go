@@ -0,0 +1,36 @@
+package messaging
+
+import "sync"
+
+// FakeGateway tidak mengirim apa pun: pesan disimpan di memori agar bisa diperiksa.
+// Hanya untuk pengujian; server tanpa gateway memakai DisabledGateway. Err, jika diisi,
+// dikembalikan oleh Send untuk mensimulasikan gateway yang gagal.
+type FakeGateway struct {
+	mu   sync.Mutex
+	sent []Message
+	Err  error
+}
+
+func (g *FakeGateway) Send(msg Message) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.Err != nil {
+		return g.Err
+	}
+	g.sent = append(g.sent, msg)
+	return nil
+}
+
+// Sent mengembalikan salinan pesan yang sudah "dikirim"
+func (g *FakeGateway) Sent() []Message {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return append([]Message(nil), g.sent...)
+}
+
+// Reset mengosongkan pesan tersimpan
+func (g *FakeGateway) Reset() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.sent = nil
+}
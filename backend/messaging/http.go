@@ -0,0 +1,57 @@
+package messaging
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// HTTPGateway mengirim pesan ke penyedia WhatsApp/SMS lewat HTTP. Setiap pesan dikirim
+// sebagai POST JSON {"to", "channel", "sender", "message"} dengan token di header
+// Authorization; respons selain 2xx dianggap gagal.
+type HTTPGateway struct {
+	URL    string
+	Token  string
+	Sender string
+	Client *http.Client
+}
+
+const httpTimeout = 15 * time.Second
+
+func (g HTTPGateway) Send(msg Message) error {
+	body, err := json.Marshal(map[string]string{
+		"to":      msg.To,
+		"channel": msg.Channel,
+		"sender":  g.Sender,
+		"message": msg.Text,
+	})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, g.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if g.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+g.Token)
+	}
+
+	client := g.Client
+	if client == nil {
+		client = &http.Client{Timeout: httpTimeout}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("gateway membalas %s: %s", resp.Status, bytes.TrimSpace(detail))
+	}
+	return nil
+}
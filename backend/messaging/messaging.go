@@ -0,0 +1,92 @@
+// Package messaging mengirim pesan WhatsApp/SMS lewat interface Gateway sehingga
+// penyedia layanan bisa diganti tanpa mengubah pemanggil.
+package messaging
+
+import (
+	"errors"
+	"strings"
+	"unicode"
+)
+
+// Kanal pengiriman pesan
+const (
+	ChannelWhatsApp = "whatsapp"
+	ChannelSMS      = "sms"
+)
+
+// IsValidChannel memeriksa kanal pesan
+func IsValidChannel(ch string) bool {
+	return ch == ChannelWhatsApp || ch == ChannelSMS
+}
+
+// Message adalah satu pesan ke satu nomor. To dalam format E.164 (+628...).
+type Message struct {
+	To      string
+	Channel string
+	Text    string
+}
+
+// Gateway mengirim pesan WhatsApp/SMS
+type Gateway interface {
+	Send(msg Message) error
+}
+
+var (
+	ErrInvalidPhone = errors.New("nomor HP tidak valid")
+	ErrRateLimited  = errors.New("batas pesan untuk nomor ini terlampaui")
+	ErrDisabled     = errors.New("gateway WhatsApp/SMS belum dikonfigurasi")
+)
+
+// DisabledGateway menolak setiap pesan dengan ErrDisabled. Dipakai selama gateway
+// belum dikonfigurasi sehingga tidak ada nomor atau isi pesan yang keluar dari server.
+type DisabledGateway struct{}
+
+func (DisabledGateway) Send(msg Message) error {
+	return ErrDisabled
+}
+
+var defaultGateway Gateway = DisabledGateway{}
+
+// Init mengganti gateway bawaan
+func Init(g Gateway) {
+	defaultGateway = g
+}
+
+// Default mengembalikan gateway bawaan
+func Default() Gateway {
+	return defaultGateway
+}
+
+// Enabled melaporkan apakah gateway bawaan bisa mengirim pesan
+func Enabled() bool {
+	_, disabled := defaultGateway.(DisabledGateway)
+	return !disabled
+}
+
+// NormalizePhone mengubah nomor HP Indonesia ke format E.164. Spasi, tanda hubung dan
+// tanda kurung diabaikan; awalan 0 dan 62 diubah menjadi +62.
+func NormalizePhone(s string) (string, error) {
+	var b strings.Builder
+	for i, r := range strings.TrimSpace(s) {
+		switch {
+		case unicode.IsDigit(r):
+			b.WriteRune(r)
+		case r == '+' && i == 0:
+		case r == ' ' || r == '-' || r == '(' || r == ')' || r == '.':
+		default:
+			return "", ErrInvalidPhone
+		}
+	}
+	digits := b.String()
+	switch {
+	case strings.HasPrefix(digits, "0"):
+		digits = "62" + digits[1:]
+	case strings.HasPrefix(digits, "8"):
+		digits = "62" + digits
+	}
+	// nomor seluler Indonesia: 62 8xx, total 10-15 digit
+	if !strings.HasPrefix(digits, "628") || len(digits) < 10 || len(digits) > 15 {
+		return "", ErrInvalidPhone
+	}
+	return "+" + digits, nil
+}
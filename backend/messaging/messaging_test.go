@@ -0,0 +1,106 @@
+package messaging
+
+import (
+	"errors"
+	"sync"
+	"testing"
+)
+
+func TestFakeGateway(t *testing.T) {
+	g := &FakeGateway{}
+	msgs := []Message{
+		{To: "+6281234567890", Channel: ChannelWhatsApp, Text: "satu"},
+		{To: "+6281234567891", Channel: ChannelSMS, Text: "dua"},
+	}
+	for _, m := range msgs {
+		if err := g.Send(m); err != nil {
+			t.Fatalf("Send: %v", err)
+		}
+	}
+	sent := g.Sent()
+	if len(sent) != 2 || sent[0] != msgs[0] || sent[1] != msgs[1] {
+		t.Fatalf("Sent() = %v, want %v", sent, msgs)
+	}
+
+	// Sent mengembalikan salinan
+	sent[0].Text = "diubah"
+	if g.Sent()[0].Text != "satu" {
+		t.Error("mengubah hasil Sent() ikut mengubah pesan tersimpan")
+	}
+
+	g.Reset()
+	if len(g.Sent()) != 0 {
+		t.Errorf("setelah Reset, Sent() = %v", g.Sent())
+	}
+
+	failure := errors.New("gateway mati")
+	g.Err = failure
+	if err := g.Send(msgs[0]); err != failure {
+		t.Errorf("Send dengan Err = %v, want %v", err, failure)
+	}
+	if len(g.Sent()) != 0 {
+		t.Error("pesan yang gagal tidak boleh tersimpan")
+	}
+}
+
+func TestFakeGatewayConcurrent(t *testing.T) {
+	g := &FakeGateway{}
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			g.Send(Message{To: "+6281234567890", Channel: ChannelSMS, Text: "x"})
+		}()
+	}
+	wg.Wait()
+	if n := len(g.Sent()); n != 50 {
+		t.Errorf("len(Sent()) = %d, want 50", n)
+	}
+}
+
+func TestDefaultGatewayDisabled(t *testing.T) {
+	old := Default()
+	defer Init(old)
+
+	Init(DisabledGateway{})
+	if Enabled() {
+		t.Error("Enabled() = true untuk DisabledGateway")
+	}
+	if err := Default().Send(Message{To: "+6281234567890"}); err != ErrDisabled {
+		t.Errorf("Send = %v, want ErrDisabled", err)
+	}
+	Init(&FakeGateway{})
+	if !Enabled() {
+		t.Error("Enabled() = false setelah Init gateway lain")
+	}
+}
+
+func TestNormalizePhone(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+		err  bool
+	}{
+		{"081234567890", "+6281234567890", false},
+		{"+62 812-3456-7890", "+6281234567890", false},
+		{"6281234567890", "+6281234567890", false},
+		{"(0812) 3456.7890", "+6281234567890", false},
+		{"81234567890", "+6281234567890", false},
+		{"0274123456", "", true},
+		{"0812abc", "", true},
+		{"08123", "", true},
+		{"+1 555 0100", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := NormalizePhone(tt.in)
+			if (err != nil) != tt.err {
+				t.Fatalf("err = %v, want error %v", err, tt.err)
+			}
+			if got != tt.want {
+				t.Errorf("NormalizePhone(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
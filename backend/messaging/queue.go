@@ -0,0 +1,133 @@
+package messaging
+
+import (
+	"project-backend/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	// Batas percobaan kirim sebelum pesan ditandai failed
+	maxAttempts = 4
+	// Jeda percobaan ulang pertama; berikutnya dua kali lipat
+	retryBase    = time.Minute
+	queueBatch   = 50
+	claimTimeout = 5 * time.Minute
+)
+
+// Limit membatasi jumlah pesan per nomor: paling banyak Max pesan dalam Window
+type Limit struct {
+	Max    int
+	Window time.Duration
+}
+
+// Enqueue memasukkan pesan ke antrean kirim. Jika nomor tujuan sudah menerima
+// limit.Max pesan dalam limit.Window terakhir, pesan dicatat sebagai rate_limited,
+// tidak dikirim, dan ErrRateLimited dikembalikan. Penghitungan dan penyimpanan berjalan
+// dalam satu transaksi dengan kunci per nomor, jadi batas tetap berlaku untuk pengiriman
+// bersamaan. Pesan dengan dedupKey (jika diisi) yang sudah pernah dimasukkan diabaikan.
+func Enqueue(db *gorm.DB, userID uint, dedupKey string, msg Message, limit Limit) error {
+	now := time.Now()
+	row := models.OutboundMessage{
+		UserID:        userID,
+		Phone:         msg.To,
+		Channel:       msg.Channel,
+		Text:          msg.Text,
+		Status:        models.MessagePending,
+		NextAttemptAt: now,
+	}
+	if dedupKey != "" {
+		row.DedupKey = &dedupKey
+	}
+
+	limited := false
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if dedupKey != "" {
+			var exists int64
+			if err := tx.Model(&models.OutboundMessage{}).Where("dedup_key = ?", dedupKey).Count(&exists).Error; err != nil {
+				return err
+			}
+			if exists > 0 {
+				return nil
+			}
+		}
+		if limit.Max > 0 {
+			if err := lockPhone(tx, msg.To); err != nil {
+				return err
+			}
+			var recent int64
+			if err := tx.Model(&models.OutboundMessage{}).
+				Where("phone = ? AND created_at >= ? AND status IN ?", msg.To, now.Add(-limit.Window),
+					[]string{models.MessagePending, models.MessageSent}).
+				Count(&recent).Error; err != nil {
+				return err
+			}
+			if recent >= int64(limit.Max) {
+				row.Status = models.MessageRateLimited
+				limited = true
+			}
+		}
+		return tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&row).Error
+	})
+	if err != nil {
+		return err
+	}
+	if limited {
+		return ErrRateLimited
+	}
+	return nil
+}
+
+// lockPhone mengunci baris MessageRateLock untuk nomor (dibuat jika belum ada) sampai
+// transaksi selesai
+func lockPhone(tx *gorm.DB, phone string) error {
+	lock := models.MessageRateLock{Phone: phone}
+	if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&lock).Error; err != nil {
+		return err
+	}
+	return tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("phone = ?", phone).First(&lock).Error
+}
+
+// ProcessQueue mengirim pesan yang sudah jatuh tempo lewat gateway bawaan, dengan
+// klaim atomik seperti antrean email. Mengembalikan jumlah pesan yang terkirim.
+func ProcessQueue(db *gorm.DB, now time.Time) (int, error) {
+	var due []models.OutboundMessage
+	if err := db.Where("status = ? AND next_attempt_at <= ?", models.MessagePending, now).
+		Order("next_attempt_at").Limit(queueBatch).Find(&due).Error; err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for _, m := range due {
+		claim := db.Model(&models.OutboundMessage{}).
+			Where("id = ? AND status = ? AND next_attempt_at = ?", m.ID, models.MessagePending, m.NextAttemptAt).
+			Update("next_attempt_at", now.Add(claimTimeout))
+		if claim.Error != nil {
+			return sent, claim.Error
+		}
+		if claim.RowsAffected == 0 {
+			continue
+		}
+
+		updates := map[string]interface{}{"attempts": m.Attempts + 1}
+		if err := defaultGateway.Send(Message{To: m.Phone, Channel: m.Channel, Text: m.Text}); err != nil {
+			updates["last_error"] = err.Error()
+			if m.Attempts+1 >= maxAttempts {
+				updates["status"] = models.MessageFailed
+			} else {
+				updates["next_attempt_at"] = now.Add(retryBase << m.Attempts)
+			}
+		} else {
+			updates["status"] = models.MessageSent
+			updates["sent_at"] = now
+			updates["last_error"] = ""
+			sent++
+		}
+		if err := db.Model(&models.OutboundMessage{}).Where("id = ?", m.ID).Updates(updates).Error; err != nil {
+			return sent, err
+		}
+	}
+	return sent, nil
+}
@@ -0,0 +1,23 @@
+package middleware
+
+import (
+	"log"
+	"project-backend/audit"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AuditMiddleware mencatat tindakan ke audit log setelah handler selesai dengan sukses.
+// Handler dapat mengisi data sebelum/sesudah lewat audit.SetBefore dan audit.SetAfter.
+func AuditMiddleware(action, targetType string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		if c.Writer.Status() >= 400 {
+			return
+		}
+		if err := audit.Record(audit.FromContext(c, action, targetType)); err != nil {
+			log.Printf("audit: gagal mencatat %s: %v", action, err)
+		}
+	}
+}
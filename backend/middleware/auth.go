@@ -1,10 +1,12 @@
 package middleware
 
 import (
+	"errors"
 	"net/http"
 	"project-backend/config"
 	"project-backend/models"
 	"strings"
+	"time"
 
 	"github.com/dgrijalva/jwt-go"
 	"github.com/gin-gonic/gin"
@@ -39,6 +41,12 @@ func AuthMiddleware() gin.HandlerFunc {
 			c.Abort()
 			return
 		}
+		// tiket stream hanya berlaku untuk endpoint stream
+		if typ, _ := claims["typ"].(string); typ == streamTicketType {
+			c.JSON(http.StatusUnauthorized, gin.H{"message": "Invalid token"})
+			c.Abort()
+			return
+		}
 
 		// user_id
 		idFromClaims, ok := claims["user_id"]
@@ -79,6 +87,64 @@ func AuthMiddleware() gin.HandlerFunc {
 	}
 }
 
+const (
+	streamTicketType = "stream"
+	// StreamTicketTTL adalah masa berlaku tiket stream; cukup untuk membuka koneksi
+	StreamTicketTTL = time.Minute
+)
+
+// IssueStreamTicket membuat tiket berumur pendek untuk membuka stream SSE. EventSource di
+// browser tidak bisa mengirim header Authorization, jadi tiket ini yang dikirim lewat
+// ?ticket= alih-alih token login yang berlaku 24 jam dan bisa tercatat di log akses.
+func IssueStreamTicket(userID uint, role string) (string, time.Time, error) {
+	exp := time.Now().Add(StreamTicketTTL)
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"typ":     streamTicketType,
+		"user_id": userID,
+		"role":    role,
+		"exp":     exp.Unix(),
+	})
+	signed, err := token.SignedString(jwtSecret)
+	return signed, exp, err
+}
+
+// StreamAuthMiddleware menerima header Authorization seperti AuthMiddleware, atau tiket
+// dari IssueStreamTicket lewat ?ticket=. Hanya dipakai untuk endpoint stream.
+func StreamAuthMiddleware() gin.HandlerFunc {
+	auth := AuthMiddleware()
+	return func(c *gin.Context) {
+		ticket := c.Query("ticket")
+		if c.GetHeader("Authorization") != "" || ticket == "" {
+			auth(c)
+			return
+		}
+
+		token, err := jwt.Parse(ticket, func(token *jwt.Token) (interface{}, error) {
+			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+				return nil, errors.New("unexpected signing method")
+			}
+			return jwtSecret, nil
+		})
+		if err != nil || !token.Valid {
+			c.JSON(http.StatusUnauthorized, gin.H{"message": "Tiket stream tidak valid atau kedaluwarsa"})
+			c.Abort()
+			return
+		}
+		claims, _ := token.Claims.(jwt.MapClaims)
+		typ, _ := claims["typ"].(string)
+		userID, ok := claims["user_id"].(float64)
+		if typ != streamTicketType || !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"message": "Tiket stream tidak valid atau kedaluwarsa"})
+			c.Abort()
+			return
+		}
+		role, _ := claims["role"].(string)
+		c.Set("userID", uint(userID))
+		c.Set("role", role)
+		c.Next()
+	}
+}
+
 // AdminMiddleware mengizinkan hanya role admin, superadmin, dan kategori_admin
 func AdminMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -0,0 +1,63 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/gin-gonic/gin"
+)
+
+func signed(t *testing.T, claims jwt.MapClaims) string {
+	s, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(jwtSecret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestStreamAuthMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ticket, _, err := IssueStreamTicket(42, "user")
+	if err != nil {
+		t.Fatal(err)
+	}
+	login := signed(t, jwt.MapClaims{"user_id": 42, "role": "user", "exp": time.Now().Add(time.Hour).Unix()})
+	expired := signed(t, jwt.MapClaims{"typ": streamTicketType, "user_id": 42, "exp": time.Now().Add(-time.Second).Unix()})
+
+	tests := []struct {
+		name   string
+		query  string
+		header string
+		want   int
+	}{
+		{"tiket valid", "?ticket=" + ticket, "", http.StatusOK},
+		{"tiket kedaluwarsa", "?ticket=" + expired, "", http.StatusUnauthorized},
+		{"token login bukan tiket", "?ticket=" + login, "", http.StatusUnauthorized},
+		{"access_token tidak lagi diterima", "?access_token=" + login, "", http.StatusUnauthorized},
+		{"header Authorization", "", "Bearer " + login, http.StatusOK},
+		{"tiket di header Authorization", "", "Bearer " + ticket, http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := gin.New()
+			r.GET("/events/stream", StreamAuthMiddleware(), func(c *gin.Context) {
+				if c.GetUint("userID") != 42 {
+					t.Errorf("userID = %d, want 42", c.GetUint("userID"))
+				}
+				c.Status(http.StatusOK)
+			})
+			req := httptest.NewRequest(http.MethodGet, "/events/stream"+tt.query, nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			if w.Code != tt.want {
+				t.Errorf("status = %d, want %d", w.Code, tt.want)
+			}
+		})
+	}
+}
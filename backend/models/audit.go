@@ -0,0 +1,45 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ErrAuditLogImmutable dikembalikan jika ada yang mencoba mengubah atau menghapus audit log
+var ErrAuditLogImmutable = errors.New("audit log bersifat append-only dan tidak dapat diubah")
+
+// AuditLog mencatat tindakan administratif. Setiap baris menyimpan hash baris sebelumnya
+// (PrevHash) sehingga perubahan pada baris mana pun akan memutus rantai hash.
+type AuditLog struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	ActorID    uint      `gorm:"index" json:"actor_id"`
+	ActorName  string    `gorm:"size:100" json:"actor_name"` // disalin agar tetap terbaca walau user dihapus
+	ActorRole  string    `gorm:"size:30" json:"actor_role"`
+	Action     string    `gorm:"size:60;index" json:"action"`
+	TargetType string    `gorm:"size:30;index:idx_audit_target" json:"target_type"`
+	TargetID   string    `gorm:"size:60;index:idx_audit_target" json:"target_id"`
+	Before     string    `gorm:"type:text" json:"before"`
+	After      string    `gorm:"type:text" json:"after"`
+	IP         string    `gorm:"size:64" json:"ip"`
+	UserAgent  string    `gorm:"size:255" json:"user_agent"`
+	CreatedAt  time.Time `gorm:"index" json:"created_at"`
+	PrevHash   string    `gorm:"size:64" json:"prev_hash"`
+	Hash       string    `gorm:"size:64;uniqueIndex" json:"hash"`
+}
+
+// AuditChainLock adalah baris kunci tunggal rantai audit log. audit.Record menguncinya
+// (FOR UPDATE) sebelum membaca hash entri terakhir, sehingga penulisan dari beberapa
+// proses server atau perintah cmd tetap berurutan dan rantai tidak bercabang.
+type AuditChainLock struct {
+	ID uint `gorm:"primaryKey;autoIncrement:false"`
+}
+
+func (a *AuditLog) BeforeUpdate(tx *gorm.DB) error {
+	return ErrAuditLogImmutable
+}
+
+func (a *AuditLog) BeforeDelete(tx *gorm.DB) error {
+	return ErrAuditLogImmutable
+}
@@ -13,6 +13,7 @@ type Category struct {
 	ID        uint      `gorm:"primaryKey" json:"id"`
 	Name      string    `gorm:"unique;not null" json:"name"`
 	UserID    uint      `json:"user_id"`
+	Severity  int       `gorm:"default:3" json:"severity"` // 1 (ringan) - 5 (kritis), dipakai untuk skor prioritas
 	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
 	UpdatedAt time.Time `gorm:"autoUpdateTime" json:"updated_at"`
 }
@@ -0,0 +1,29 @@
+package models
+
+import "time"
+
+// Status antrean email
+const (
+	EmailPending = "pending"
+	EmailSent    = "sent"
+	EmailFailed  = "failed"
+)
+
+// EmailMessage adalah email di antrean kirim. Email yang gagal dicoba lagi dengan
+// jeda yang makin panjang sampai batas percobaan, lalu ditandai failed.
+type EmailMessage struct {
+	ID            uint       `gorm:"primaryKey" json:"id"`
+	To            string     `gorm:"size:255" json:"to"` // dipisah koma
+	Subject       string     `gorm:"size:255" json:"subject"`
+	Text          string     `gorm:"type:text" json:"-"`
+	HTML          string     `gorm:"type:mediumtext" json:"-"`
+	Headers       string     `gorm:"type:text" json:"-"` // JSON
+	Status        string     `gorm:"size:10;index:idx_email_queue,priority:1" json:"status"`
+	Attempts      int        `json:"attempts"`
+	NextAttemptAt time.Time  `gorm:"index:idx_email_queue,priority:2" json:"next_attempt_at"`
+	LastError     string     `gorm:"type:text" json:"last_error"`
+	SentAt        *time.Time `json:"sent_at"`
+	DedupKey      *string    `gorm:"size:100;uniqueIndex" json:"-"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
@@ -0,0 +1,12 @@
+package models
+
+import "time"
+
+// Endorsement menandai warga yang ikut terdampak ("saya juga") pada sebuah laporan.
+// Satu user hanya boleh memberi satu dukungan per laporan.
+type Endorsement struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	ReportID  uint      `gorm:"uniqueIndex:idx_endorsement_report_user;not null" json:"report_id"`
+	UserID    uint      `gorm:"uniqueIndex:idx_endorsement_report_user;not null" json:"user_id"`
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
@@ -0,0 +1,30 @@
+package models
+
+import "time"
+
+// Status job
+const (
+	JobQueued    = "queued"
+	JobRunning   = "running"
+	JobSucceeded = "succeeded"
+	JobDead      = "dead" // gagal permanen atau habis percobaan; bisa dicoba ulang admin
+)
+
+// Job adalah satu pekerjaan latar di antrean. Job yang gagal dijadwalkan ulang
+// (status tetap queued dengan LastError terisi) sampai MaxAttempts, lalu menjadi dead.
+type Job struct {
+	ID          uint       `gorm:"primaryKey" json:"id"`
+	Type        string     `gorm:"size:50;index:idx_job_type,priority:1" json:"type"`
+	Payload     string     `gorm:"type:text" json:"payload"` // JSON
+	Status      string     `gorm:"size:10;index:idx_job_queue,priority:1;index:idx_job_type,priority:2" json:"status"`
+	Attempts    int        `json:"attempts"`
+	MaxAttempts int        `json:"max_attempts"`
+	RunAt       time.Time  `gorm:"index:idx_job_queue,priority:2" json:"run_at"`
+	LockedBy    string     `gorm:"size:64" json:"locked_by"`
+	LockedAt    *time.Time `json:"locked_at"`
+	LastError   string     `gorm:"type:text" json:"last_error"`
+	UniqueKey   *string    `gorm:"size:150;uniqueIndex" json:"unique_key,omitempty"`
+	FinishedAt  *time.Time `json:"finished_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
@@ -0,0 +1,36 @@
+package models
+
+import "time"
+
+// Status pesan WhatsApp/SMS keluar
+const (
+	MessagePending     = "pending"
+	MessageSent        = "sent"
+	MessageFailed      = "failed"
+	MessageRateLimited = "rate_limited"
+)
+
+// OutboundMessage adalah pesan WhatsApp/SMS di antrean kirim sekaligus catatan pesan
+// yang sudah dikirim ke suatu nomor (dipakai untuk pembatasan jumlah pesan per nomor)
+type OutboundMessage struct {
+	ID            uint       `gorm:"primaryKey" json:"id"`
+	UserID        uint       `gorm:"index" json:"user_id"`
+	Phone         string     `gorm:"size:20;index:idx_message_phone,priority:1" json:"phone"`
+	Channel       string     `gorm:"size:10" json:"channel"`
+	Text          string     `gorm:"type:text" json:"text"`
+	Status        string     `gorm:"size:15;index:idx_message_queue,priority:1" json:"status"`
+	Attempts      int        `json:"attempts"`
+	NextAttemptAt time.Time  `gorm:"index:idx_message_queue,priority:2" json:"next_attempt_at"`
+	LastError     string     `gorm:"type:text" json:"last_error"`
+	SentAt        *time.Time `json:"sent_at"`
+	DedupKey      *string    `gorm:"size:100;uniqueIndex" json:"-"`
+	CreatedAt     time.Time  `gorm:"index:idx_message_phone,priority:2" json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+// MessageRateLock adalah baris kunci per nomor tujuan. Enqueue menguncinya (FOR UPDATE)
+// selama menghitung dan menyimpan pesan sehingga pengiriman bersamaan ke nomor yang sama
+// tidak bisa melewati batas per nomor.
+type MessageRateLock struct {
+	Phone string `gorm:"primaryKey;size:20"`
+}
@@ -0,0 +1,30 @@
+package models
+
+import "time"
+
+// Notification adalah satu pesan di kotak masuk in-app milik user
+type Notification struct {
+	ID       uint   `gorm:"primaryKey" json:"id"`
+	UserID   uint   `gorm:"index:idx_notification_inbox,priority:1;uniqueIndex:idx_notification_dedup,priority:1" json:"user_id"`
+	Type     string `gorm:"size:30;index" json:"type"`
+	Title    string `gorm:"size:200" json:"title"`
+	Body     string `gorm:"type:text" json:"body"`
+	ReportID *uint  `gorm:"index" json:"report_id"`
+	Link     string `gorm:"size:255" json:"link"` // path frontend, mis. "/detail/12"
+	// ReadAt nil berarti belum dibaca
+	ReadAt *time.Time `gorm:"index:idx_notification_inbox,priority:2" json:"read_at"`
+	// DedupKey mencegah notifikasi yang sama dibuat dua kali untuk user yang sama
+	// (mis. pelanggaran SLA yang diperiksa berkala). Boleh kosong (NULL).
+	DedupKey  *string   `gorm:"size:120;uniqueIndex:idx_notification_dedup,priority:2" json:"-"`
+	CreatedAt time.Time `gorm:"index" json:"created_at"`
+}
+
+// NotificationPreference menyimpan pilihan user untuk satu jenis notifikasi di satu kanal.
+// Jika tidak ada baris, notifikasi dianggap aktif.
+type NotificationPreference struct {
+	ID      uint   `gorm:"primaryKey" json:"-"`
+	UserID  uint   `gorm:"uniqueIndex:idx_notification_pref,priority:1" json:"-"`
+	Type    string `gorm:"size:30;uniqueIndex:idx_notification_pref,priority:2" json:"type"`
+	Channel string `gorm:"size:20;uniqueIndex:idx_notification_pref,priority:3" json:"channel"`
+	Enabled bool   `json:"enabled"`
+}
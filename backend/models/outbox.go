@@ -0,0 +1,34 @@
+package models
+
+import "time"
+
+// Status event outbox
+const (
+	OutboxPending   = "pending"
+	OutboxDelivered = "delivered"
+	OutboxFailed    = "failed"
+)
+
+// OutboxEvent adalah event domain yang ditulis dalam transaksi yang sama dengan
+// perubahan datanya, lalu diteruskan ke consumer (notifikasi, email, webhook) oleh
+// dispatcher. Event tidak hilang walau proses mati tepat setelah commit.
+type OutboxEvent struct {
+	ID            uint       `gorm:"primaryKey" json:"id"`
+	Type          string     `gorm:"size:50" json:"type"`
+	ReportID      uint       `gorm:"index" json:"report_id"`
+	Payload       string     `gorm:"type:text" json:"payload"` // JSON
+	Status        string     `gorm:"size:10;index:idx_outbox_queue,priority:1" json:"status"`
+	Attempts      int        `json:"attempts"`
+	NextAttemptAt time.Time  `gorm:"index:idx_outbox_queue,priority:2" json:"next_attempt_at"`
+	LastError     string     `gorm:"type:text" json:"last_error"`
+	DeliveredAt   *time.Time `json:"delivered_at"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+// OutboxDelivery mencatat consumer yang sudah berhasil memproses suatu event, agar
+// percobaan ulang tidak mengirim ulang ke consumer yang sudah selesai
+type OutboxDelivery struct {
+	EventID   uint      `gorm:"primaryKey;autoIncrement:false" json:"event_id"`
+	Consumer  string    `gorm:"primaryKey;size:30" json:"consumer"`
+	CreatedAt time.Time `json:"created_at"`
+}
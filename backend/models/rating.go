@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+// ReportRating adalah penilaian pelapor atas penyelesaian laporannya (1-5 bintang).
+// Satu penilaian untuk setiap kali laporan diselesaikan.
+type ReportRating struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	ReportID   uint      `gorm:"uniqueIndex:idx_report_rating_once;not null" json:"report_id"`
+	UserID     uint      `gorm:"uniqueIndex:idx_report_rating_once" json:"user_id"`
+	AdminID    *uint     `gorm:"index" json:"admin_id"` // admin yang menyelesaikan laporan
+	Stars      int       `json:"stars"`
+	Comment    string    `json:"comment"`
+	ResolvedAt time.Time `gorm:"uniqueIndex:idx_report_rating_once" json:"resolved_at"` // waktu status Selesai yang dinilai
+	CreatedAt  time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// Region adalah wilayah administrasi dengan kode Kemendagri bertingkat:
+// provinsi "34", kabupaten/kota "34.04", kecamatan "34.04.05", desa/kelurahan "34.04.05.2001".
+type Region struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	Code       string    `gorm:"size:20;uniqueIndex;not null" json:"code"`
+	Name       string    `gorm:"not null" json:"name"`
+	Level      string    `gorm:"size:20;index" json:"level"` // provinsi, kabupaten, kecamatan, desa
+	ParentCode string    `gorm:"size:20;index" json:"parent_code"`
+	CreatedAt  time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt  time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
@@ -1,6 +1,11 @@
 package models
 
-import "time"
+import (
+	"project-backend/geo"
+	"time"
+
+	"gorm.io/gorm"
+)
 
 type Report struct {
 	ID          uint      `gorm:"primaryKey" json:"id"`
@@ -9,19 +14,47 @@ type Report struct {
 	Title       string    `json:"title"`
 	Wilayah     string    `json:"wilayah"`
 	Lokasi      string    `json:"lokasi"`
-	Latitude    float64   `json:"latitude"`
-	Longitude   float64   `json:"longitude"`
+	Latitude    float64   `gorm:"index:idx_report_lat_lon,priority:1" json:"latitude"`
+	Longitude   float64   `gorm:"index:idx_report_lat_lon,priority:2" json:"longitude"`
 	Description string    `json:"description"`
-	Status      string    `json:"status"`
-	UserID      uint      `json:"user_id"`
-	CategoryID  *uint     `json:"category_id"`
+	Status      string    `gorm:"size:20;index" json:"status"`
+	UserID      uint      `gorm:"index" json:"user_id"`
+	CategoryID  *uint     `gorm:"index" json:"category_id"`
 	Category    Category  `gorm:"foreignKey:CategoryID" json:"category"`
-	CreatedAt   time.Time `gorm:"autoCreateTime" json:"created_at"`
-	UpdatedAt   time.Time `gorm:"autoUpdateTime" json:"updated_at"`
-
-	User       User        `gorm:"foreignKey:UserID" json:"user"`
-	Riwayat    []Riwayat   `gorm:"foreignKey:ReportID" json:"riwayat"`
-	Comments   []Comment   `gorm:"foreignKey:ReportID" json:"comments"`
-	FollowUps  []FollowUp  `gorm:"foreignKey:ReportID" json:"followups"`
-	BuktiFotos []BuktiFoto `gorm:"foreignKey:ReportID" json:"bukti_fotos"`
+	CreatedAt   time.Time `gorm:"autoCreateTime;index" json:"created_at"`
+	UpdatedAt   time.Time `gorm:"autoUpdateTime;index" json:"updated_at"`
+
+	// Jumlah dukungan "saya juga", disimpan di sini agar bisa diurutkan dan difilter
+	EndorsementCount int `gorm:"default:0;index" json:"endorsement_count"`
+
+	// Prioritas efektif (rendah/sedang/tinggi/darurat). Berasal dari PriorityOverride
+	// jika diisi admin, selain itu dari PriorityScore yang dihitung sistem.
+	Priority          string     `gorm:"size:20;default:rendah;index" json:"priority"`
+	PriorityScore     int        `gorm:"default:0;index" json:"priority_score"`
+	PriorityOverride  *string    `gorm:"size:20" json:"priority_override"`
+	PriorityUpdatedAt *time.Time `json:"priority_updated_at"`
+
+	// Geohash koordinat laporan, dipakai sebagai indeks spasial untuk pencarian radius
+	Geohash string `gorm:"size:12;index" json:"geohash"`
+	// Kode wilayah Kemendagri paling rinci hasil lookup koordinat (mis. "34.04.05.2001")
+	KodeWilayah string  `gorm:"size:20;index" json:"kode_wilayah"`
+	RegionID    *uint   `gorm:"index" json:"region_id"`
+	Region      *Region `gorm:"foreignKey:RegionID" json:"region,omitempty"`
+
+	ResolvedAt  *time.Time `json:"resolved_at"` // terakhir kali status menjadi Selesai
+	ResolvedBy  *uint      `json:"resolved_by"` // admin yang menetapkan status Selesai
+	ReopenCount int        `gorm:"default:0" json:"reopen_count"`
+
+	User       User           `gorm:"foreignKey:UserID" json:"user"`
+	Riwayat    []Riwayat      `gorm:"foreignKey:ReportID" json:"riwayat"`
+	Comments   []Comment      `gorm:"foreignKey:ReportID" json:"comments"`
+	FollowUps  []FollowUp     `gorm:"foreignKey:ReportID" json:"followups"`
+	BuktiFotos []BuktiFoto    `gorm:"foreignKey:ReportID" json:"bukti_fotos"`
+	Ratings    []ReportRating `gorm:"foreignKey:ReportID" json:"ratings,omitempty"`
+}
+
+// BeforeSave menjaga Geohash selalu sesuai dengan Latitude/Longitude
+func (r *Report) BeforeSave(tx *gorm.DB) error {
+	r.Geohash = geo.Encode(r.Latitude, r.Longitude, geo.StoredPrecision)
+	return nil
 }
@@ -0,0 +1,53 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// FieldChange mencatat perubahan satu field laporan (nilai lama -> nilai baru)
+type FieldChange struct {
+	Field string `json:"field"`
+	Old   string `json:"old"`
+	New   string `json:"new"`
+}
+
+// FieldChanges disimpan sebagai JSON di satu kolom
+type FieldChanges []FieldChange
+
+func (f FieldChanges) Value() (driver.Value, error) {
+	if f == nil {
+		return "[]", nil
+	}
+	b, err := json.Marshal(f)
+	return string(b), err
+}
+
+func (f *FieldChanges) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*f = nil
+		return nil
+	case []byte:
+		return json.Unmarshal(v, f)
+	case string:
+		return json.Unmarshal([]byte(v), f)
+	}
+	return errors.New("tipe data FieldChanges tidak didukung")
+}
+
+// ReportRevision adalah satu versi perubahan laporan beserta siapa yang mengubahnya
+type ReportRevision struct {
+	ID         uint         `gorm:"primaryKey" json:"id"`
+	ReportID   uint         `gorm:"uniqueIndex:idx_report_revision_version;not null" json:"report_id"`
+	Version    int          `gorm:"uniqueIndex:idx_report_revision_version" json:"version"`
+	EditorID   uint         `json:"editor_id"`
+	EditorRole string       `json:"editor_role"`
+	Action     string       `gorm:"size:30" json:"action"` // edit, withdraw, photo_add, photo_remove
+	Changes    FieldChanges `gorm:"type:text" json:"changes"`
+	CreatedAt  time.Time    `gorm:"autoCreateTime" json:"created_at"`
+
+	Editor User `gorm:"foreignKey:EditorID" json:"editor"`
+}
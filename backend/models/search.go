@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+// SearchDocument menyimpan teks laporan yang sudah dinormalisasi (stemming + stopword)
+// untuk pencarian full-text di MySQL. Di SQLite dipakai tabel virtual FTS5.
+type SearchDocument struct {
+	ReportID   uint      `gorm:"primaryKey;autoIncrement:false" json:"report_id"`
+	Title      string    `gorm:"type:text;index:idx_search_title,class:FULLTEXT;index:idx_search_all,class:FULLTEXT,priority:1" json:"title"`
+	Body       string    `gorm:"type:text;index:idx_search_body,class:FULLTEXT;index:idx_search_all,class:FULLTEXT,priority:2" json:"body"`             // deskripsi + lokasi
+	Discussion string    `gorm:"type:mediumtext;index:idx_search_disc,class:FULLTEXT;index:idx_search_all,class:FULLTEXT,priority:3" json:"discussion"` // komentar + tindak lanjut
+	UpdatedAt  time.Time `json:"updated_at"`
+}
@@ -0,0 +1,30 @@
+package models
+
+import "time"
+
+// ReportSubscription adalah langganan rekap laporan yang dikirim berkala lewat email.
+// Laporan yang dirangkum dibatasi lingkup admin pemiliknya (kategori/wilayah) ditambah Filters.
+type ReportSubscription struct {
+	ID     uint   `gorm:"primaryKey" json:"id"`
+	UserID uint   `gorm:"index" json:"user_id"` // admin pemilik langganan
+	User   User   `gorm:"foreignKey:UserID" json:"user,omitempty"`
+	Name   string `gorm:"size:100" json:"name"`
+
+	// Jadwal: daily/weekly/monthly pada jam Hour (waktu server). Weekday (0 = Minggu)
+	// untuk weekly, DayOfMonth (1-28) untuk monthly.
+	Frequency  string `gorm:"size:10" json:"frequency"`
+	Hour       int    `json:"hour"`
+	Weekday    int    `json:"weekday"`
+	DayOfMonth int    `json:"day_of_month"`
+
+	Format     string `gorm:"size:10" json:"format"`       // csv, xlsx atau pdf
+	Filters    string `gorm:"type:text" json:"filters"`    // query string filter daftar laporan, mis. "status=Diajukan&category_id=2"
+	Recipients string `gorm:"type:text" json:"recipients"` // alamat email dipisah koma; kosong = email pemilik
+	Active     bool   `gorm:"default:true;index" json:"active"`
+
+	NextRunAt time.Time  `gorm:"index" json:"next_run_at"`
+	LastRunAt *time.Time `json:"last_run_at"`
+	LastError string     `gorm:"type:text" json:"last_error"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
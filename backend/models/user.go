@@ -7,15 +7,23 @@ import (
 )
 
 type User struct {
-	ID         uint           `gorm:"primaryKey" json:"id"`
-	Name       string         `json:"name"`
-	Email      string         `gorm:"unique" json:"email"`
-	Password   string         `json:"-"`
-	Role       string         `json:"role"`
-	IsActive   bool           `gorm:"default:true" json:"is_active"`
-	Categories []Category     `gorm:"foreignKey:UserID" json:"categories"`
-	Reports    []Report       `gorm:"foreignKey:UserID"`
-	CreatedAt  time.Time      `json:"created_at"`
-	UpdatedAt  time.Time      `json:"updated_at"`
-	DeletedAt  gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty"`
+	ID       uint   `gorm:"primaryKey" json:"id"`
+	Name     string `json:"name"`
+	Email    string `gorm:"unique" json:"email"`
+	Password string `json:"-"`
+	Role     string `json:"role"`
+	IsActive bool   `gorm:"default:true" json:"is_active"`
+	Language string `gorm:"size:5;default:id" json:"language"` // bahasa email: id atau en
+	// Nomor HP dan persetujuan WhatsApp/SMS tidak ikut diserialisasi; hanya dikembalikan
+	// ke pemiliknya lewat UpdateProfile
+	Phone            string         `gorm:"size:20;index" json:"-"` // format E.164, mis. +628123456789
+	MessagingOptIn   bool           `json:"-"`                      // pesan hanya dikirim jika aktif
+	MessagingOptInAt *time.Time     `json:"-"`
+	MessagingChannel string         `gorm:"size:10;default:whatsapp" json:"-"` // whatsapp atau sms
+	Categories       []Category     `gorm:"foreignKey:UserID" json:"categories"`
+	Regions          []Region       `gorm:"many2many:admin_regions" json:"regions"`
+	Reports          []Report       `gorm:"foreignKey:UserID"`
+	CreatedAt        time.Time      `json:"created_at"`
+	UpdatedAt        time.Time      `json:"updated_at"`
+	DeletedAt        gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty"`
 }
@@ -0,0 +1,50 @@
+package models
+
+import "time"
+
+// Webhook adalah langganan sistem lain terhadap event laporan. Events dan CategoryIDs
+// berupa daftar dipisah koma; CategoryIDs kosong berarti semua kategori.
+type Webhook struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	Name        string    `gorm:"size:100" json:"name"`
+	URL         string    `gorm:"size:500" json:"url"`
+	Secret      string    `gorm:"size:100" json:"-"`
+	Events      string    `gorm:"size:255" json:"events"`
+	CategoryIDs string    `gorm:"size:255" json:"category_ids"`
+	Active      bool      `gorm:"default:true" json:"active"`
+	CreatedBy   uint      `json:"created_by"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// Status pengiriman webhook
+const (
+	DeliveryPending = "pending"
+	DeliverySuccess = "success"
+	DeliveryFailed  = "failed"
+)
+
+// WebhookDelivery adalah satu pengiriman event ke satu webhook beserta hasil
+// percobaan terakhirnya
+type WebhookDelivery struct {
+	ID             uint       `gorm:"primaryKey" json:"id"`
+	WebhookID      uint       `gorm:"index:idx_delivery_webhook,priority:1;uniqueIndex:idx_delivery_event,priority:1" json:"webhook_id"`
+	EventID        string     `gorm:"size:40;uniqueIndex:idx_delivery_event,priority:2" json:"event_id"`
+	Event          string     `gorm:"size:50" json:"event"`
+	Payload        string     `gorm:"type:mediumtext" json:"payload"`
+	Status         string     `gorm:"size:10;index:idx_delivery_queue,priority:1" json:"status"`
+	Attempts       int        `json:"attempts"`
+	NextAttemptAt  time.Time  `gorm:"index:idx_delivery_queue,priority:2" json:"next_attempt_at"`
+	ResponseStatus int        `json:"response_status"`
+	ResponseBody   string     `gorm:"type:text" json:"response_body"`
+	LastError      string     `gorm:"type:text" json:"last_error"`
+	DurationMs     int64      `json:"duration_ms"`
+	DeliveredAt    *time.Time `json:"delivered_at"`
+	RedeliveryOf   *uint      `json:"redelivery_of"`
+	// Redelivery 0 untuk pengiriman asli, lebih besar untuk pengiriman ulang; bersama
+	// webhook dan event membentuk kunci unik sehingga setiap event hanya dikirim sekali
+	// per webhook dan pengiriman ulang yang bersamaan tidak tercatat ganda
+	Redelivery uint      `gorm:"not null;default:0;uniqueIndex:idx_delivery_event,priority:3" json:"redelivery"`
+	CreatedAt  time.Time `gorm:"index:idx_delivery_webhook,priority:2" json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
@@ -0,0 +1,161 @@
+// Package notification membuat notifikasi in-app untuk pelapor dan admin dari
+// kejadian pada laporan (perubahan status, komentar, tindak lanjut, penugasan dan
+// pelanggaran SLA), dengan memperhatikan preferensi tiap user.
+package notification
+
+import (
+	"log"
+	"project-backend/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Jenis notifikasi
+const (
+	TypeStatusChanged = "status_changed"
+	TypeComment       = "comment"
+	TypeFollowUp      = "followup"
+	TypeAssigned      = "assigned"
+	TypeSLABreach     = "sla_breach"
+)
+
+// Types berisi semua jenis notifikasi beserta keterangannya, untuk halaman preferensi
+var Types = []struct {
+	Type  string `json:"type"`
+	Label string `json:"label"`
+}{
+	{TypeStatusChanged, "Status laporan berubah"},
+	{TypeComment, "Komentar baru"},
+	{TypeFollowUp, "Tindak lanjut baru"},
+	{TypeAssigned, "Laporan ditugaskan kepada saya"},
+	{TypeSLABreach, "Laporan melewati batas waktu penanganan"},
+}
+
+// Kanal pengiriman notifikasi
+const (
+	ChannelInApp     = "in_app"
+	ChannelEmail     = "email"
+	ChannelMessaging = "messaging" // WhatsApp/SMS
+)
+
+// Channels berisi kanal yang bisa diatur user
+var Channels = []string{ChannelInApp, ChannelEmail, ChannelMessaging}
+
+// IsValidType memeriksa jenis notifikasi
+func IsValidType(t string) bool {
+	for _, it := range Types {
+		if it.Type == t {
+			return true
+		}
+	}
+	return false
+}
+
+// IsValidChannel memeriksa kanal notifikasi
+func IsValidChannel(ch string) bool {
+	for _, c := range Channels {
+		if c == ch {
+			return true
+		}
+	}
+	return false
+}
+
+// Event adalah satu kejadian yang diberitahukan ke Recipients. ActorID (pelaku)
+// tidak ikut menerima notifikasi.
+type Event struct {
+	Type       string
+	ReportID   uint
+	ActorID    uint
+	Title      string
+	Body       string
+	Link       string
+	Recipients []uint
+	// DedupKey opsional; notifikasi dengan kunci yang sama hanya dibuat sekali per user
+	DedupKey string
+}
+
+var (
+	defaultDB *gorm.DB
+	listeners []func(models.Notification)
+)
+
+// Init menyiapkan database yang dipakai Publish
+func Init(db *gorm.DB) {
+	defaultDB = db
+}
+
+// OnCreated mendaftarkan fungsi yang dipanggil untuk setiap notifikasi baru
+// (mis. untuk push real-time). Harus dipanggil saat start-up.
+func OnCreated(fn func(models.Notification)) {
+	listeners = append(listeners, fn)
+}
+
+// Publish menyimpan notifikasi untuk setiap penerima. Kegagalan dicatat di log dan
+// dikembalikan agar pemanggil yang bisa mencoba ulang (mis. outbox) dapat melakukannya.
+func Publish(ev Event) error {
+	if defaultDB == nil {
+		return nil
+	}
+	created, err := Create(defaultDB, ev)
+	if err != nil {
+		log.Printf("notification: gagal membuat notifikasi %s laporan %d: %v", ev.Type, ev.ReportID, err)
+	}
+	for _, n := range created {
+		for _, fn := range listeners {
+			fn(n)
+		}
+	}
+	return err
+}
+
+// Create menyimpan notifikasi untuk penerima yang mengaktifkan jenis tersebut dan
+// mengembalikan notifikasi yang benar-benar dibuat (yang duplikat dilewati)
+func Create(db *gorm.DB, ev Event) ([]models.Notification, error) {
+	var created []models.Notification
+	seen := map[uint]bool{}
+	for _, userID := range ev.Recipients {
+		if userID == 0 || userID == ev.ActorID || seen[userID] {
+			continue
+		}
+		seen[userID] = true
+		if !Enabled(db, userID, ev.Type, ChannelInApp) {
+			continue
+		}
+
+		n := models.Notification{
+			UserID: userID,
+			Type:   ev.Type,
+			Title:  ev.Title,
+			Body:   ev.Body,
+			Link:   ev.Link,
+		}
+		if ev.ReportID != 0 {
+			reportID := ev.ReportID
+			n.ReportID = &reportID
+		}
+		if ev.DedupKey != "" {
+			key := ev.DedupKey
+			n.DedupKey = &key
+		}
+		res := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&n)
+		if res.Error != nil {
+			return created, res.Error
+		}
+		if res.RowsAffected > 0 {
+			created = append(created, n)
+		}
+	}
+	return created, nil
+}
+
+// Enabled memeriksa preferensi user; tanpa preferensi tersimpan notifikasi aktif
+func Enabled(db *gorm.DB, userID uint, typ, channel string) bool {
+	var pref models.NotificationPreference
+	err := db.Where("user_id = ? AND type = ? AND channel = ?", userID, typ, channel).Take(&pref).Error
+	if err != nil {
+		return true
+	}
+	return pref.Enabled
+}
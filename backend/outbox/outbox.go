@@ -0,0 +1,189 @@
+// Package outbox menjamin event domain tidak hilang: event ditulis ke tabel
+// outbox_events di dalam transaksi perubahan data (Write), lalu dispatcher
+// (Dispatch) meneruskannya ke setiap consumer terdaftar minimal sekali. Consumer
+// yang sudah berhasil dicatat di outbox_deliveries sehingga tidak dipanggil lagi
+// saat event dicoba ulang; consumer tetap harus tahan terhadap panggilan ganda
+// (mis. proses mati setelah consumer selesai tapi sebelum dicatat) dengan memakai
+// DedupKey.
+package outbox
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"project-backend/models"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	// Batas percobaan sebelum event ditandai failed
+	maxAttempts = 10
+	// Jeda percobaan ulang pertama; berikutnya dua kali lipat, paling lama maxRetryDelay
+	retryBase     = 5 * time.Second
+	maxRetryDelay = 30 * time.Minute
+	batchSize     = 100
+	claimTimeout  = 5 * time.Minute
+)
+
+// Event adalah event outbox yang diterima consumer
+type Event struct {
+	ID        uint
+	Type      string
+	ReportID  uint
+	Payload   []byte
+	CreatedAt time.Time
+}
+
+// DedupKey adalah kunci unik event untuk consumer, dipakai untuk mencegah efek ganda
+// jika event diproses lebih dari sekali
+func (e Event) DedupKey(consumer string) string {
+	return fmt.Sprintf("outbox:%d:%s", e.ID, consumer)
+}
+
+// Decode membaca payload JSON event ke v
+func (e Event) Decode(v interface{}) error {
+	return json.Unmarshal(e.Payload, v)
+}
+
+// Consumer memproses satu event. Error membuat event dicoba ulang untuk consumer ini.
+type Consumer func(ev Event) error
+
+type consumer struct {
+	name string
+	fn   Consumer
+}
+
+var consumers []consumer
+
+// Register mendaftarkan consumer. Harus dipanggil saat start-up sebelum dispatcher berjalan.
+func Register(name string, fn Consumer) {
+	consumers = append(consumers, consumer{name, fn})
+}
+
+// Write menyimpan event di dalam transaksi tx. Panggil di transaksi yang sama dengan
+// perubahan data agar event tersimpan jika dan hanya jika perubahan tersimpan.
+func Write(tx *gorm.DB, typ string, reportID uint, payload interface{}) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	return tx.Create(&models.OutboxEvent{
+		Type:          typ,
+		ReportID:      reportID,
+		Payload:       string(data),
+		Status:        models.OutboxPending,
+		NextAttemptAt: now,
+		CreatedAt:     now,
+	}).Error
+}
+
+func retryDelay(attempts int) time.Duration {
+	d := retryBase << (attempts - 1)
+	if d > maxRetryDelay || d <= 0 {
+		d = maxRetryDelay
+	}
+	return d
+}
+
+// Dispatch meneruskan event yang jatuh tempo ke semua consumer sesuai urutan
+// penulisan. Setiap event diklaim secara atomik agar tidak diproses bersamaan oleh
+// beberapa proses. Mengembalikan jumlah event yang selesai.
+func Dispatch(db *gorm.DB, now time.Time) (int, error) {
+	var due []models.OutboxEvent
+	if err := db.Where("status = ? AND next_attempt_at <= ?", models.OutboxPending, now).
+		Order("id").Limit(batchSize).Find(&due).Error; err != nil {
+		return 0, err
+	}
+
+	done := 0
+	for _, row := range due {
+		claim := db.Model(&models.OutboxEvent{}).
+			Where("id = ? AND status = ? AND next_attempt_at = ?", row.ID, models.OutboxPending, row.NextAttemptAt).
+			Update("next_attempt_at", now.Add(claimTimeout))
+		if claim.Error != nil {
+			return done, claim.Error
+		}
+		if claim.RowsAffected == 0 {
+			continue
+		}
+
+		errs := deliver(db, row)
+		updates := map[string]interface{}{"attempts": row.Attempts + 1}
+		if len(errs) == 0 {
+			updates["status"] = models.OutboxDelivered
+			updates["delivered_at"] = now
+			updates["last_error"] = ""
+			done++
+		} else {
+			updates["last_error"] = strings.Join(errs, "; ")
+			if row.Attempts+1 >= maxAttempts {
+				updates["status"] = models.OutboxFailed
+				log.Printf("outbox: event %d (%s) gagal setelah %d percobaan: %s", row.ID, row.Type, row.Attempts+1, updates["last_error"])
+			} else {
+				updates["next_attempt_at"] = now.Add(retryDelay(row.Attempts + 1))
+			}
+		}
+		if err := db.Model(&models.OutboxEvent{}).Where("id = ?", row.ID).Updates(updates).Error; err != nil {
+			return done, err
+		}
+	}
+	return done, nil
+}
+
+// deliver memanggil consumer yang belum berhasil memproses event dan mengembalikan
+// pesan error consumer yang gagal
+func deliver(db *gorm.DB, row models.OutboxEvent) []string {
+	var finished []string
+	db.Model(&models.OutboxDelivery{}).Where("event_id = ?", row.ID).Pluck("consumer", &finished)
+	skip := make(map[string]bool, len(finished))
+	for _, name := range finished {
+		skip[name] = true
+	}
+
+	ev := Event{ID: row.ID, Type: row.Type, ReportID: row.ReportID, Payload: []byte(row.Payload), CreatedAt: row.CreatedAt}
+	var errs []string
+	for _, c := range consumers {
+		if skip[c.name] {
+			continue
+		}
+		if err := call(c, ev); err != nil {
+			errs = append(errs, c.name+": "+err.Error())
+			continue
+		}
+		err := db.Clauses(clause.OnConflict{DoNothing: true}).
+			Create(&models.OutboxDelivery{EventID: row.ID, Consumer: c.name}).Error
+		if err != nil {
+			errs = append(errs, c.name+": "+err.Error())
+		}
+	}
+	return errs
+}
+
+// call menjalankan consumer dan mengubah panic menjadi error agar consumer lain tetap berjalan
+func call(c consumer, ev Event) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return c.fn(ev)
+}
+
+// Cleanup menghapus event yang sudah terkirim sebelum before beserta catatan
+// consumernya. Event failed disimpan untuk diperiksa.
+func Cleanup(db *gorm.DB, before time.Time) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		old := tx.Model(&models.OutboxEvent{}).Select("id").
+			Where("status = ? AND delivered_at < ?", models.OutboxDelivered, before)
+		if err := tx.Where("event_id IN (?)", old).Delete(&models.OutboxDelivery{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("status = ? AND delivered_at < ?", models.OutboxDelivered, before).
+			Delete(&models.OutboxEvent{}).Error
+	})
+}
@@ -0,0 +1,144 @@
+// Package pdf membuat dokumen PDF (bukti pengaduan dan rekap bulanan) langsung di
+// server dengan fpdf, tanpa layanan eksternal. Setiap dokumen punya struct data
+// sendiri yang diisi oleh pemanggil, sehingga package ini tidak mengakses database.
+package pdf
+
+import (
+	"bytes"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/go-pdf/fpdf"
+)
+
+const (
+	pageMargin = 15.0
+	pageWidth  = 210.0 // A4
+	lineHeight = 6.0
+)
+
+// Warna tema (RGB)
+var (
+	colorPrimary = [3]int{185, 28, 28}
+	colorMuted   = [3]int{107, 114, 128}
+	colorBorder  = [3]int{229, 231, 235}
+)
+
+// LabelValue adalah satu baris data bernama, dipakai di tabel dan grafik
+type LabelValue struct {
+	Label string
+	Value float64
+}
+
+// document membungkus fpdf dengan penerjemah UTF-8 -> cp1252 untuk font bawaan
+type document struct {
+	*fpdf.Fpdf
+	tr func(string) string
+}
+
+func newDocument(appName, title string) *document {
+	f := fpdf.New("P", "mm", "A4", "")
+	f.SetMargins(pageMargin, pageMargin, pageMargin)
+	f.SetAutoPageBreak(true, pageMargin+5)
+	f.SetTitle(title, true)
+	f.SetCreator(appName, true)
+	d := &document{Fpdf: f, tr: f.UnicodeTranslatorFromDescriptor("")}
+
+	f.SetHeaderFunc(func() {
+		d.SetFont("Helvetica", "B", 14)
+		d.setColor(colorPrimary)
+		d.CellFormat(0, 8, d.tr(appName), "", 1, "L", false, 0, "")
+		d.SetFont("Helvetica", "", 9)
+		d.setColor(colorMuted)
+		d.CellFormat(0, 5, d.tr("Layanan Pengaduan Masyarakat"), "", 1, "L", false, 0, "")
+		d.SetDrawColor(colorPrimary[0], colorPrimary[1], colorPrimary[2])
+		d.SetLineWidth(0.6)
+		y := d.GetY() + 1
+		d.Line(pageMargin, y, pageWidth-pageMargin, y)
+		d.SetY(y + 5)
+		d.SetTextColor(0, 0, 0)
+	})
+	f.SetFooterFunc(func() {
+		d.SetY(-pageMargin)
+		d.SetFont("Helvetica", "I", 8)
+		d.setColor(colorMuted)
+		d.CellFormat(0, 5, d.tr(title), "", 0, "L", false, 0, "")
+		d.CellFormat(0, 5, d.tr("Halaman ")+strconv.Itoa(d.PageNo())+" / {nb}", "", 0, "R", false, 0, "")
+	})
+	f.AliasNbPages("")
+	return d
+}
+
+func (d *document) setColor(c [3]int) {
+	d.SetTextColor(c[0], c[1], c[2])
+}
+
+func (d *document) heading(text string) {
+	d.SetFont("Helvetica", "B", 16)
+	d.SetTextColor(0, 0, 0)
+	d.CellFormat(0, 10, d.tr(text), "", 1, "C", false, 0, "")
+	d.Ln(2)
+}
+
+func (d *document) section(text string) {
+	d.Ln(3)
+	d.SetFont("Helvetica", "B", 11)
+	d.setColor(colorPrimary)
+	d.CellFormat(0, 7, d.tr(text), "B", 1, "L", false, 0, "")
+	d.SetTextColor(0, 0, 0)
+	d.Ln(2)
+}
+
+// field menulis pasangan label: nilai; nilai panjang dibungkus ke baris berikutnya
+func (d *document) field(label, value string, labelWidth, width float64) {
+	x := d.GetX()
+	d.SetFont("Helvetica", "", 10)
+	d.setColor(colorMuted)
+	d.CellFormat(labelWidth, lineHeight, d.tr(label), "", 0, "L", false, 0, "")
+	d.SetTextColor(0, 0, 0)
+	if strings.TrimSpace(value) == "" {
+		value = "-"
+	}
+	d.MultiCell(width-labelWidth, lineHeight, d.tr(value), "", "L", false)
+	d.SetX(x)
+}
+
+// image menambahkan gambar dari berkas jika formatnya didukung (jpeg/png/gif).
+// Gambar yang tidak bisa dibaca dilewati agar dokumen tetap terbentuk.
+func (d *document) image(path string, x, y, w, h float64) bool {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return false
+	}
+	return d.imageBytes(path, data, x, y, w, h)
+}
+
+func (d *document) imageBytes(name string, data []byte, x, y, w, h float64) bool {
+	cfg, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil || cfg.Width == 0 || cfg.Height == 0 {
+		return false
+	}
+	imgType := map[string]string{"jpeg": "JPG", "png": "PNG", "gif": "GIF"}[format]
+	if imgType == "" {
+		return false
+	}
+
+	// pertahankan rasio dalam kotak w x h
+	ratio := float64(cfg.Width) / float64(cfg.Height)
+	iw, ih := w, w/ratio
+	if ih > h {
+		ih, iw = h, h*ratio
+	}
+	opt := fpdf.ImageOptions{ImageType: imgType, ReadDpi: false}
+	d.RegisterImageOptionsReader(name, opt, bytes.NewReader(data))
+	if !d.Ok() {
+		return false
+	}
+	d.ImageOptions(name, x+(w-iw)/2, y+(h-ih)/2, iw, ih, false, opt, 0, "")
+	return true
+}
@@ -0,0 +1,289 @@
+package pdf
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var monthNames = [...]string{"Januari", "Februari", "Maret", "April", "Mei", "Juni",
+	"Juli", "Agustus", "September", "Oktober", "November", "Desember"}
+
+// MonthName mengembalikan nama bulan dan tahun dalam bahasa Indonesia, mis. "Agustus 2025"
+func MonthName(t time.Time) string {
+	return monthNames[t.Month()-1] + " " + strconv.Itoa(t.Year())
+}
+
+// PeriodName menamai rentang [from, to) untuk judul rekap: satu bulan penuh menjadi
+// "Agustus 2025", selain itu "11 Agustus - 17 Agustus 2025"
+func PeriodName(from, to time.Time) string {
+	last := to.Add(-time.Nanosecond)
+	if from.Day() == 1 && from.AddDate(0, 1, 0).Equal(to) {
+		return MonthName(from)
+	}
+	day := func(t time.Time) string { return strconv.Itoa(t.Day()) + " " + monthNames[t.Month()-1] }
+	if from.Year() == last.Year() && from.YearDay() == last.YearDay() {
+		return day(from) + " " + strconv.Itoa(from.Year())
+	}
+	if from.Year() != last.Year() {
+		return day(from) + " " + strconv.Itoa(from.Year()) + " - " + day(last) + " " + strconv.Itoa(last.Year())
+	}
+	return day(from) + " - " + day(last) + " " + strconv.Itoa(last.Year())
+}
+
+// CategoryRecap adalah rekap satu kategori dalam sebulan
+type CategoryRecap struct {
+	Name     string
+	Total    int64
+	Resolved int64
+	Median   float64 // median jam sampai selesai
+}
+
+// RecapData adalah isi rekap laporan untuk admin (bulanan, atau periode lain untuk
+// laporan terjadwal)
+type RecapData struct {
+	AppName   string
+	Period    string // lihat PeriodName
+	Scope     string // keterangan cakupan kategori/wilayah admin
+	PrintedAt time.Time
+
+	Total        int64
+	PrevTotal    int64 // total bulan sebelumnya, untuk perbandingan
+	Resolved     int64
+	Satisfaction float64
+	RatingCount  int64
+
+	FirstResponseMedian float64 // jam
+	ResolveMedian       float64 // jam
+	ResolveP90          float64 // jam
+
+	ByStatus   []LabelValue
+	ByCategory []CategoryRecap
+	ByRegion   []LabelValue
+	Daily      []LabelValue // jumlah laporan masuk per hari
+}
+
+// Recap menulis PDF rekap laporan ke w
+func Recap(w io.Writer, data RecapData) error {
+	title := "Rekap Laporan " + data.Period
+	d := newDocument(data.AppName, title)
+	d.AddPage()
+	d.heading(strings.ToUpper(title))
+	d.SetFont("Helvetica", "", 9)
+	d.setColor(colorMuted)
+	d.CellFormat(0, 5, d.tr("Cakupan: "+data.Scope), "", 1, "C", false, 0, "")
+	d.SetTextColor(0, 0, 0)
+	d.Ln(3)
+
+	contentWidth := pageWidth - 2*pageMargin
+
+	// kartu ringkasan
+	change := "-"
+	if data.PrevTotal > 0 {
+		pct := float64(data.Total-data.PrevTotal) / float64(data.PrevTotal) * 100
+		change = fmt.Sprintf("%+.0f%% dari periode lalu", pct)
+	}
+	resolvedPct := 0.0
+	if data.Total > 0 {
+		resolvedPct = float64(data.Resolved) / float64(data.Total) * 100
+	}
+	cards := []struct{ label, value, note string }{
+		{"Laporan Masuk", strconv.FormatInt(data.Total, 10), change},
+		{"Selesai", strconv.FormatInt(data.Resolved, 10), fmt.Sprintf("%.0f%% dari laporan masuk", resolvedPct)},
+		{"Median Penyelesaian", formatHours(data.ResolveMedian), "p90 " + formatHours(data.ResolveP90)},
+		{"Kepuasan", fmt.Sprintf("%.2f / 5", data.Satisfaction), fmt.Sprintf("%d penilaian", data.RatingCount)},
+	}
+	const gap = 3.0
+	cardW := (contentWidth - gap*float64(len(cards)-1)) / float64(len(cards))
+	top := d.GetY()
+	d.SetDrawColor(colorBorder[0], colorBorder[1], colorBorder[2])
+	d.SetLineWidth(0.3)
+	for i, card := range cards {
+		x := pageMargin + float64(i)*(cardW+gap)
+		d.Rect(x, top, cardW, 24, "D")
+		d.SetXY(x+2, top+2)
+		d.SetFont("Helvetica", "", 8)
+		d.setColor(colorMuted)
+		d.CellFormat(cardW-4, 4, d.tr(card.label), "", 2, "L", false, 0, "")
+		d.SetFont("Helvetica", "B", 15)
+		d.SetTextColor(0, 0, 0)
+		d.CellFormat(cardW-4, 9, d.tr(card.value), "", 2, "L", false, 0, "")
+		d.SetFont("Helvetica", "", 7)
+		d.setColor(colorMuted)
+		d.CellFormat(cardW-4, 4, d.tr(card.note), "", 0, "L", false, 0, "")
+	}
+	d.SetTextColor(0, 0, 0)
+	d.SetY(top + 28)
+	d.SetFont("Helvetica", "", 9)
+	d.CellFormat(0, 5, d.tr("Median waktu tanggapan pertama: "+formatHours(data.FirstResponseMedian)), "", 1, "L", false, 0, "")
+
+	d.section("Laporan Masuk per Hari")
+	d.columnChart(data.Daily, contentWidth, 45)
+
+	d.section("Status Laporan")
+	d.barChart(data.ByStatus, contentWidth)
+
+	d.section("Rekap per Kategori")
+	d.categoryTable(data.ByCategory, contentWidth)
+
+	if len(data.ByRegion) > 0 {
+		d.section("Wilayah dengan Laporan Terbanyak")
+		d.barChart(data.ByRegion, contentWidth)
+	}
+
+	d.Ln(6)
+	d.SetFont("Helvetica", "I", 8)
+	d.setColor(colorMuted)
+	d.CellFormat(0, 4, d.tr("Dibuat otomatis pada "+data.PrintedAt.Format("02-01-2006 15:04")+"."), "", 1, "L", false, 0, "")
+
+	return d.Output(w)
+}
+
+func formatHours(h float64) string {
+	if h <= 0 {
+		return "-"
+	}
+	if h < 48 {
+		return fmt.Sprintf("%.1f jam", h)
+	}
+	return fmt.Sprintf("%.1f hari", h/24)
+}
+
+// barChart menggambar grafik batang horizontal: label | batang | nilai
+func (d *document) barChart(items []LabelValue, width float64) {
+	if len(items) == 0 {
+		d.emptyNote()
+		return
+	}
+	const labelW, valueW, rowH = 50.0, 14.0, 6.0
+	max := 0.0
+	for _, it := range items {
+		max = math.Max(max, it.Value)
+	}
+	barMax := width - labelW - valueW
+	for _, it := range items {
+		if d.GetY()+rowH > 297-pageMargin-5 {
+			d.AddPage()
+		}
+		y := d.GetY()
+		d.SetFont("Helvetica", "", 9)
+		d.CellFormat(labelW, rowH, d.tr(truncate(it.Label, 30)), "", 0, "L", false, 0, "")
+		if max > 0 && it.Value > 0 {
+			d.SetFillColor(colorPrimary[0], colorPrimary[1], colorPrimary[2])
+			d.Rect(pageMargin+labelW, y+1, math.Max(barMax*it.Value/max, 0.5), rowH-2, "F")
+		}
+		d.SetX(pageMargin + labelW + barMax)
+		d.CellFormat(valueW, rowH, formatNumber(it.Value), "", 1, "R", false, 0, "")
+	}
+}
+
+// columnChart menggambar grafik kolom vertikal dengan label di bawah sumbu
+func (d *document) columnChart(items []LabelValue, width, height float64) {
+	if len(items) == 0 {
+		d.emptyNote()
+		return
+	}
+	if d.GetY()+height+10 > 297-pageMargin-5 {
+		d.AddPage()
+	}
+	max := 0.0
+	for _, it := range items {
+		max = math.Max(max, it.Value)
+	}
+	const axisW = 8.0
+	top := d.GetY()
+	base := top + height
+	colW := (width - axisW) / float64(len(items))
+
+	d.SetDrawColor(colorBorder[0], colorBorder[1], colorBorder[2])
+	d.SetLineWidth(0.2)
+	d.Line(pageMargin+axisW, base, pageMargin+width, base)
+	d.SetFont("Helvetica", "", 7)
+	d.setColor(colorMuted)
+	d.SetXY(pageMargin, top)
+	d.CellFormat(axisW-1, 3, formatNumber(max), "", 0, "R", false, 0, "")
+	d.SetXY(pageMargin, base-3)
+	d.CellFormat(axisW-1, 3, "0", "", 0, "R", false, 0, "")
+
+	d.SetFillColor(colorPrimary[0], colorPrimary[1], colorPrimary[2])
+	// label hanya setiap beberapa kolom agar tidak bertumpuk
+	every := int(math.Ceil(6 / colW))
+	if every < 1 {
+		every = 1
+	}
+	for i, it := range items {
+		x := pageMargin + axisW + float64(i)*colW
+		if max > 0 && it.Value > 0 {
+			h := height * it.Value / max
+			d.Rect(x+colW*0.15, base-h, colW*0.7, h, "F")
+		}
+		if i%every == 0 {
+			d.SetXY(x-colW, base+1)
+			d.CellFormat(colW*3, 3, d.tr(it.Label), "", 0, "C", false, 0, "")
+		}
+	}
+	d.SetTextColor(0, 0, 0)
+	d.SetY(base + 6)
+}
+
+func (d *document) categoryTable(rows []CategoryRecap, width float64) {
+	if len(rows) == 0 {
+		d.emptyNote()
+		return
+	}
+	cols := []struct {
+		title string
+		w     float64
+		align string
+	}{
+		{"Kategori", width - 90, "L"},
+		{"Masuk", 22, "R"},
+		{"Selesai", 22, "R"},
+		{"% Selesai", 22, "R"},
+		{"Median", 24, "R"},
+	}
+	d.SetFont("Helvetica", "B", 9)
+	d.SetFillColor(243, 244, 246)
+	for _, col := range cols {
+		d.CellFormat(col.w, 7, d.tr(col.title), "1", 0, col.align, true, 0, "")
+	}
+	d.Ln(-1)
+	d.SetFont("Helvetica", "", 9)
+	for _, r := range rows {
+		pct := "-"
+		if r.Total > 0 {
+			pct = fmt.Sprintf("%.0f%%", float64(r.Resolved)/float64(r.Total)*100)
+		}
+		values := []string{truncate(r.Name, 45), strconv.FormatInt(r.Total, 10),
+			strconv.FormatInt(r.Resolved, 10), pct, formatHours(r.Median)}
+		for i, col := range cols {
+			d.CellFormat(col.w, 6, d.tr(values[i]), "1", 0, col.align, false, 0, "")
+		}
+		d.Ln(-1)
+	}
+}
+
+func (d *document) emptyNote() {
+	d.SetFont("Helvetica", "I", 9)
+	d.setColor(colorMuted)
+	d.CellFormat(0, 6, d.tr("Tidak ada data."), "", 1, "L", false, 0, "")
+	d.SetTextColor(0, 0, 0)
+}
+
+func formatNumber(v float64) string {
+	if v == math.Trunc(v) {
+		return strconv.FormatFloat(v, 'f', 0, 64)
+	}
+	return strconv.FormatFloat(v, 'f', 1, 64)
+}
+
+func truncate(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n-1]) + "…"
+}
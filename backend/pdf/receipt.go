@@ -0,0 +1,147 @@
+package pdf
+
+import (
+	"fmt"
+	"io"
+	"project-backend/qr"
+	"time"
+)
+
+// Maksimum foto yang dicetak di bukti pengaduan
+const maxReceiptPhotos = 4
+
+// ReceiptData adalah isi "Bukti Pengaduan" untuk satu laporan
+type ReceiptData struct {
+	AppName     string
+	TrackingID  string
+	TrackingURL string // tautan halaman pelacakan publik, dikodekan ke QR code
+	Title       string
+	Category    string
+	Status      string
+	Wilayah     string
+	KodeWilayah string
+	Lokasi      string
+	Latitude    float64
+	Longitude   float64
+	Description string
+	Reporter    string // kosong/"Anonim" untuk laporan anonim
+	CreatedAt   time.Time
+	Photos      []string // path berkas foto di disk
+	PrintedAt   time.Time
+}
+
+// Receipt menulis PDF bukti pengaduan ke w
+func Receipt(w io.Writer, data ReceiptData) error {
+	d := newDocument(data.AppName, "Bukti Pengaduan "+data.TrackingID)
+	d.AddPage()
+	d.heading("BUKTI PENGADUAN")
+
+	// kotak tracking ID + QR code di kanan
+	const qrSize = 38.0
+	contentWidth := pageWidth - 2*pageMargin
+	top := d.GetY()
+	d.SetDrawColor(colorBorder[0], colorBorder[1], colorBorder[2])
+	d.SetLineWidth(0.3)
+	d.Rect(pageMargin, top, contentWidth, qrSize+6, "D")
+
+	d.SetXY(pageMargin+4, top+5)
+	d.SetFont("Helvetica", "", 10)
+	d.setColor(colorMuted)
+	d.CellFormat(0, 5, d.tr("Nomor Pelacakan (Tracking ID)"), "", 1, "L", false, 0, "")
+	d.SetX(pageMargin + 4)
+	d.SetFont("Courier", "B", 20)
+	d.SetTextColor(0, 0, 0)
+	d.CellFormat(0, 12, d.tr(data.TrackingID), "", 1, "L", false, 0, "")
+	d.SetX(pageMargin + 4)
+	d.SetFont("Helvetica", "", 9)
+	d.setColor(colorMuted)
+	d.MultiCell(contentWidth-qrSize-12, 4.5, d.tr("Simpan nomor ini untuk memantau perkembangan laporan. "+
+		"Pindai QR code atau buka tautan berikut:"), "", "L", false)
+	d.SetX(pageMargin + 4)
+	d.SetTextColor(37, 99, 235)
+	d.CellFormat(contentWidth-qrSize-12, 5, d.tr(data.TrackingURL), "", 1, "L", false, 0, data.TrackingURL)
+	d.SetTextColor(0, 0, 0)
+
+	if data.TrackingURL != "" {
+		png, err := qr.PNG(data.TrackingURL, qr.DefaultSize)
+		if err != nil {
+			return fmt.Errorf("membuat QR code: %w", err)
+		}
+		d.imageBytes("qr-"+data.TrackingID, png, pageWidth-pageMargin-qrSize-3, top+3, qrSize, qrSize)
+	}
+	d.SetY(top + qrSize + 8)
+
+	const labelWidth = 40.0
+	d.section("Rincian Pengaduan")
+	d.field("Judul", data.Title, labelWidth, contentWidth)
+	d.field("Kategori", data.Category, labelWidth, contentWidth)
+	d.field("Status", data.Status, labelWidth, contentWidth)
+	d.field("Tanggal Diajukan", data.CreatedAt.Format("02-01-2006 15:04"), labelWidth, contentWidth)
+	reporter := data.Reporter
+	if reporter == "" {
+		reporter = "Anonim"
+	}
+	d.field("Pelapor", reporter, labelWidth, contentWidth)
+
+	d.section("Lokasi")
+	wilayah := data.Wilayah
+	if data.KodeWilayah != "" {
+		wilayah += " (" + data.KodeWilayah + ")"
+	}
+	d.field("Wilayah", wilayah, labelWidth, contentWidth)
+	d.field("Alamat", data.Lokasi, labelWidth, contentWidth)
+	d.field("Koordinat", fmt.Sprintf("%.6f, %.6f", data.Latitude, data.Longitude), labelWidth, contentWidth)
+
+	d.section("Uraian")
+	d.SetFont("Helvetica", "", 10)
+	d.MultiCell(0, 5.5, d.tr(data.Description), "", "L", false)
+
+	if len(data.Photos) > 0 {
+		d.section("Foto Bukti")
+		photos := data.Photos
+		if len(photos) > maxReceiptPhotos {
+			photos = photos[:maxReceiptPhotos]
+		}
+		const gap = 4.0
+		size := (contentWidth - gap) / 2
+		if d.GetY()+size > 297-pageMargin-5 {
+			d.AddPage()
+		}
+		x, y := pageMargin, d.GetY()
+		placed := 0
+		for _, p := range photos {
+			if !d.image(p, x, y, size, size*0.75) {
+				continue
+			}
+			placed++
+			if placed%2 == 1 {
+				x += size + gap
+				continue
+			}
+			x = pageMargin
+			y += size*0.75 + gap
+			if y+size*0.75 > 297-pageMargin-5 {
+				d.AddPage()
+				y = d.GetY()
+			}
+		}
+		if placed%2 == 1 {
+			y += size*0.75 + gap
+		}
+		d.SetY(y)
+		if placed < len(data.Photos) {
+			d.SetFont("Helvetica", "I", 9)
+			d.setColor(colorMuted)
+			d.CellFormat(0, 5, d.tr(fmt.Sprintf("%d dari %d foto ditampilkan.", placed, len(data.Photos))), "", 1, "L", false, 0, "")
+			d.SetTextColor(0, 0, 0)
+		}
+	}
+
+	d.Ln(6)
+	d.SetFont("Helvetica", "I", 8)
+	d.setColor(colorMuted)
+	d.MultiCell(0, 4, d.tr("Dokumen ini dibuat otomatis oleh sistem pada "+
+		data.PrintedAt.Format("02-01-2006 15:04")+" dan sah tanpa tanda tangan."), "", "L", false)
+
+	return d.Output(w)
+}
@@ -0,0 +1,97 @@
+// Package qr membuat QR code (PNG atau SVG) untuk tautan pelacakan laporan.
+// Dipakai oleh endpoint QR, bukti pengaduan PDF dan email konfirmasi.
+package qr
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	qrcode "github.com/skip2/go-qrcode"
+)
+
+const (
+	FormatPNG = "png"
+	FormatSVG = "svg"
+
+	DefaultSize = 256
+	MinSize     = 64
+	MaxSize     = 1024
+)
+
+var ErrUnknownFormat = errors.New("format harus png atau svg")
+
+// Tingkat koreksi kesalahan sedang (~15%) cukup untuk kertas cetak yang sedikit rusak
+const level = qrcode.Medium
+
+// ClampSize membatasi ukuran gambar (piksel) ke rentang yang diizinkan
+func ClampSize(size int) int {
+	if size <= 0 {
+		return DefaultSize
+	}
+	if size < MinSize {
+		return MinSize
+	}
+	if size > MaxSize {
+		return MaxSize
+	}
+	return size
+}
+
+// PNG mengembalikan QR code content sebagai gambar PNG berukuran size x size
+func PNG(content string, size int) ([]byte, error) {
+	return qrcode.Encode(content, level, ClampSize(size))
+}
+
+// SVG mengembalikan QR code content sebagai SVG. Setiap baris modul gelap digambar
+// sebagai satu path sehingga ukuran berkas tetap kecil dan tajam di resolusi berapa pun.
+func SVG(content string, size int) ([]byte, error) {
+	code, err := qrcode.New(content, level)
+	if err != nil {
+		return nil, err
+	}
+	bitmap := code.Bitmap() // sudah termasuk quiet zone
+	n := strconv.Itoa(len(bitmap))
+	px := strconv.Itoa(ClampSize(size))
+
+	var b strings.Builder
+	b.WriteString(`<?xml version="1.0" encoding="UTF-8"?>` + "\n")
+	b.WriteString(`<svg xmlns="http://www.w3.org/2000/svg" width="` + px + `" height="` + px +
+		`" viewBox="0 0 ` + n + ` ` + n + `" shape-rendering="crispEdges">`)
+	b.WriteString(`<rect width="100%" height="100%" fill="#fff"/><path fill="#000" d="`)
+	for y, row := range bitmap {
+		for x := 0; x < len(row); x++ {
+			if !row[x] {
+				continue
+			}
+			// gabungkan modul gelap yang berurutan dalam satu baris
+			start := x
+			for x < len(row) && row[x] {
+				x++
+			}
+			b.WriteString("M" + strconv.Itoa(start) + " " + strconv.Itoa(y) +
+				"h" + strconv.Itoa(x-start) + "v1h-" + strconv.Itoa(x-start) + "z")
+		}
+	}
+	b.WriteString(`"/></svg>`)
+	return []byte(b.String()), nil
+}
+
+// Encode membuat QR code dalam format png atau svg
+func Encode(content, format string, size int) ([]byte, error) {
+	switch format {
+	case FormatPNG:
+		return PNG(content, size)
+	case FormatSVG:
+		return SVG(content, size)
+	}
+	return nil, ErrUnknownFormat
+}
+
+// ContentType mengembalikan MIME type untuk format
+func ContentType(format string) string {
+	if format == FormatSVG {
+		return "image/svg+xml"
+	}
+	return "image/png"
+}
@@ -0,0 +1,179 @@
+// Package realtime mengirim event ke klien yang terhubung lewat Server-Sent Events.
+// Setiap event hanya dikirim ke user penerimanya, disimpan sementara di buffer
+// sehingga klien yang tersambung ulang dengan Last-Event-ID bisa menerima event
+// yang terlewat.
+package realtime
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// Jumlah event terakhir yang disimpan untuk replay
+	defaultBufferSize = 1000
+	// Antrean per klien; klien yang terlalu lambat diputus dan harus tersambung ulang
+	clientQueueSize = 64
+)
+
+// Event adalah satu pesan SSE. ID berbentuk "<epoch>-<urutan>"; epoch berubah setiap
+// server dijalankan ulang sehingga ID lama dari proses sebelumnya bisa dikenali.
+type Event struct {
+	ID   string
+	Type string
+	Data []byte // JSON
+}
+
+type storedEvent struct {
+	seq        uint64
+	event      Event
+	recipients map[uint]bool
+}
+
+// Client adalah satu koneksi SSE milik user
+type Client struct {
+	UserID uint
+	ch     chan Event
+}
+
+// Events mengembalikan event untuk klien; channel ditutup jika klien diputus hub
+func (c *Client) Events() <-chan Event {
+	return c.ch
+}
+
+// Hub menyimpan klien yang terhubung dan buffer replay
+type Hub struct {
+	mu      sync.Mutex
+	epoch   string
+	seq     uint64
+	buffer  []storedEvent // ring buffer berurutan menurut seq
+	size    int
+	clients map[uint]map[*Client]struct{}
+}
+
+func NewHub(bufferSize int) *Hub {
+	return &Hub{
+		epoch:   strconv.FormatInt(time.Now().UnixMilli(), 36),
+		size:    bufferSize,
+		clients: map[uint]map[*Client]struct{}{},
+	}
+}
+
+var defaultHub = NewHub(defaultBufferSize)
+
+// Default mengembalikan hub bawaan
+func Default() *Hub {
+	return defaultHub
+}
+
+// Publish mengirim event ke hub bawaan
+func Publish(eventType string, data interface{}, recipients []uint) {
+	defaultHub.Publish(eventType, data, recipients)
+}
+
+// Publish mengirim event ke semua koneksi milik recipients dan menyimpannya untuk replay
+func (h *Hub) Publish(eventType string, data interface{}, recipients []uint) {
+	if len(recipients) == 0 {
+		return
+	}
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return
+	}
+	set := make(map[uint]bool, len(recipients))
+	for _, id := range recipients {
+		if id != 0 {
+			set[id] = true
+		}
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.seq++
+	ev := Event{ID: h.epoch + "-" + strconv.FormatUint(h.seq, 10), Type: eventType, Data: payload}
+	h.buffer = append(h.buffer, storedEvent{seq: h.seq, event: ev, recipients: set})
+	if len(h.buffer) > h.size {
+		h.buffer = h.buffer[len(h.buffer)-h.size:]
+	}
+
+	for userID := range set {
+		for c := range h.clients[userID] {
+			select {
+			case c.ch <- ev:
+			default:
+				// antrean penuh: putus agar klien tersambung ulang dan mengambil replay
+				h.remove(c)
+			}
+		}
+	}
+}
+
+// Subscribe mendaftarkan koneksi baru. Jika lastEventID diisi, event milik user
+// setelah ID tersebut dikembalikan sebagai replay. reset bernilai true jika event
+// yang terlewat tidak bisa dipulihkan (server dijalankan ulang atau buffer sudah
+// tergeser), sehingga klien perlu memuat ulang datanya.
+func (h *Hub) Subscribe(userID uint, lastEventID string) (c *Client, replay []Event, reset bool) {
+	c = &Client{UserID: userID, ch: make(chan Event, clientQueueSize)}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.clients[userID] == nil {
+		h.clients[userID] = map[*Client]struct{}{}
+	}
+	h.clients[userID][c] = struct{}{}
+
+	if lastEventID == "" {
+		return c, nil, false
+	}
+	epoch, seqStr, ok := strings.Cut(lastEventID, "-")
+	last, err := strconv.ParseUint(seqStr, 10, 64)
+	if !ok || err != nil || epoch != h.epoch || last > h.seq {
+		return c, nil, true
+	}
+	if len(h.buffer) > 0 && h.buffer[0].seq > last+1 {
+		reset = true
+	}
+	for _, se := range h.buffer {
+		if se.seq > last && se.recipients[userID] {
+			replay = append(replay, se.event)
+		}
+	}
+	return c, replay, reset
+}
+
+// Unsubscribe melepas koneksi
+func (h *Hub) Unsubscribe(c *Client) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.remove(c)
+}
+
+// remove harus dipanggil dengan h.mu terkunci
+func (h *Hub) remove(c *Client) {
+	set, ok := h.clients[c.UserID]
+	if !ok {
+		return
+	}
+	if _, ok := set[c]; !ok {
+		return
+	}
+	delete(set, c)
+	if len(set) == 0 {
+		delete(h.clients, c.UserID)
+	}
+	close(c.ch)
+}
+
+// Connections mengembalikan jumlah koneksi aktif
+func (h *Hub) Connections() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	n := 0
+	for _, set := range h.clients {
+		n += len(set)
+	}
+	return n
+}
@@ -0,0 +1,69 @@
+package realtime
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Interval komentar heartbeat agar proxy tidak menutup koneksi yang diam
+const heartbeatInterval = 25 * time.Second
+
+// Jeda sambung ulang yang disarankan ke browser (milidetik)
+const retryMillis = 3000
+
+// Serve menjalankan stream SSE untuk userID sampai klien memutus koneksi.
+// Last-Event-ID dibaca dari header (dikirim otomatis oleh EventSource saat
+// tersambung ulang) atau query ?last_event_id=.
+func (h *Hub) Serve(w http.ResponseWriter, r *http.Request, userID uint) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming tidak didukung", http.StatusInternalServerError)
+		return
+	}
+
+	lastID := r.Header.Get("Last-Event-ID")
+	if lastID == "" {
+		lastID = r.URL.Query().Get("last_event_id")
+	}
+	client, replay, reset := h.Subscribe(userID, lastID)
+	defer h.Unsubscribe(client)
+
+	header := w.Header()
+	header.Set("Content-Type", "text/event-stream")
+	header.Set("Cache-Control", "no-cache")
+	header.Set("Connection", "keep-alive")
+	header.Set("X-Accel-Buffering", "no") // nginx: jangan buffer respons
+	w.WriteHeader(http.StatusOK)
+
+	fmt.Fprintf(w, "retry: %d\n\n", retryMillis)
+	if reset {
+		fmt.Fprint(w, "event: reset\ndata: {}\n\n")
+	}
+	for _, ev := range replay {
+		writeEvent(w, ev)
+	}
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(heartbeatInterval)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case ev, ok := <-client.Events():
+			if !ok {
+				return // diputus hub karena terlalu lambat
+			}
+			writeEvent(w, ev)
+			flusher.Flush()
+		case <-heartbeat.C:
+			fmt.Fprint(w, ": ping\n\n")
+			flusher.Flush()
+		}
+	}
+}
+
+func writeEvent(w http.ResponseWriter, ev Event) {
+	fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", ev.ID, ev.Type, ev.Data)
+}
@@ -13,19 +13,53 @@ func AdminRoutes(r *gin.Engine) {
 	adminGroup.Use(middleware.AuthMiddleware(), middleware.UserLoaderMiddleware(), middleware.AdminMiddleware())
 	{
 		adminGroup.GET("/stats", controllers.GetAdminStats)
+		adminGroup.GET("/stats/export", controllers.ExportStats)
 		adminGroup.GET("/report-trends", controllers.GetTrends)
 		adminGroup.GET("/by-category", controllers.GetReportsByCategory)
 		adminGroup.GET("/comment-trends", controllers.GetCommentTrends)
 		adminGroup.GET("/folloup-trends", controllers.GetFollowupTrends)
+		adminGroup.GET("/analytics/heatmap", controllers.GetHeatmap)
+		adminGroup.GET("/analytics/resolution", controllers.GetResolutionMetrics)
+		adminGroup.GET("/recap/monthly", controllers.GetMonthlyRecap)
+
+		// Langganan rekap laporan lewat email
+		adminGroup.GET("/subscriptions", controllers.GetSubscriptions)
+		adminGroup.POST("/subscriptions", middleware.AuditMiddleware("subscription.create", "subscription"), controllers.CreateSubscription)
+		adminGroup.PUT("/subscriptions/:id", middleware.AuditMiddleware("subscription.update", "subscription"), controllers.UpdateSubscription)
+		adminGroup.DELETE("/subscriptions/:id", middleware.AuditMiddleware("subscription.delete", "subscription"), controllers.DeleteSubscription)
+		adminGroup.POST("/subscriptions/:id/send", middleware.AuditMiddleware("subscription.send", "subscription"), controllers.SendSubscriptionNow)
 
 		// Bukti Foto Management
 		adminGroup.GET("/bukti-foto", controllers.GetAllBuktiFotoAdmin)
 		adminGroup.GET("/bukti-foto/stats", controllers.GetBuktiFotoStats)
-		adminGroup.DELETE("/bukti-foto/:id", controllers.SoftDeleteBuktiFoto)
-		adminGroup.POST("/bukti-foto/:id/restore", controllers.RestoreBuktiFoto)
-		adminGroup.DELETE("/bukti-foto/:id/permanent", controllers.HardDeleteBuktiFoto)
+		adminGroup.DELETE("/bukti-foto/:id", middleware.AuditMiddleware("bukti_foto.soft_delete", "bukti_foto"), controllers.SoftDeleteBuktiFoto)
+		adminGroup.POST("/bukti-foto/:id/restore", middleware.AuditMiddleware("bukti_foto.restore", "bukti_foto"), controllers.RestoreBuktiFoto)
+		adminGroup.DELETE("/bukti-foto/:id/permanent", middleware.AuditMiddleware("bukti_foto.hard_delete", "bukti_foto"), controllers.HardDeleteBuktiFoto)
 
 		// Report dengan semua bukti foto (termasuk yang dihapus)
 		adminGroup.GET("/reports/:id/with-deleted", controllers.GetReportWithAllBuktiFoto)
+
+		// Wilayah tugas admin (hanya superadmin)
+		adminGroup.PUT("/users/:id/regions", middleware.SuperadminMiddleware(), middleware.AuditMiddleware("user.regions", "user"), controllers.SetAdminRegions)
+
+		// Webhook ke sistem lain (hanya superadmin)
+		webhooks := adminGroup.Group("/webhooks", middleware.SuperadminMiddleware())
+		webhooks.GET("", controllers.GetWebhooks)
+		webhooks.POST("", middleware.AuditMiddleware("webhook.create", "webhook"), controllers.CreateWebhook)
+		webhooks.PUT("/:id", middleware.AuditMiddleware("webhook.update", "webhook"), controllers.UpdateWebhook)
+		webhooks.DELETE("/:id", middleware.AuditMiddleware("webhook.delete", "webhook"), controllers.DeleteWebhook)
+		webhooks.GET("/:id/deliveries", controllers.GetWebhookDeliveries)
+		webhooks.POST("/deliveries/:id/redeliver", middleware.AuditMiddleware("webhook.redeliver", "webhook"), controllers.RedeliverWebhook)
+
+		// Antrean job latar (hanya superadmin)
+		jobGroup := adminGroup.Group("/jobs", middleware.SuperadminMiddleware())
+		jobGroup.GET("", controllers.GetJobs)
+		jobGroup.GET("/schedules", controllers.GetJobSchedules)
+		jobGroup.GET("/:id", controllers.GetJob)
+		jobGroup.POST("/:id/retry", middleware.AuditMiddleware("job.retry", "job"), controllers.RetryJob)
+
+		// Audit log (hanya superadmin)
+		adminGroup.GET("/audit-logs", middleware.SuperadminMiddleware(), controllers.GetAuditLogs)
+		adminGroup.GET("/audit-logs/verify", middleware.SuperadminMiddleware(), controllers.VerifyAuditLogs)
 	}
 }
@@ -33,9 +33,9 @@ func CategoryRoutes(r *gin.Engine) {
 	auth.Use(middleware.AuthMiddleware(), middleware.UserLoaderMiddleware()) // cek token + load user
 	{
 		// auth.GET("", controllers.GetCategories)
-		auth.POST("", controllers.CreateCategory)
-		auth.PUT("/:id", controllers.UpdateCategory)
-		auth.DELETE("/:id", controllers.DeleteCategory)
+		auth.POST("", middleware.AuditMiddleware("category.create", "category"), controllers.CreateCategory)
+		auth.PUT("/:id", middleware.AuditMiddleware("category.update", "category"), controllers.UpdateCategory)
+		auth.DELETE("/:id", middleware.AuditMiddleware("category.delete", "category"), controllers.DeleteCategory)
 		auth.GET("/admins", controllers.GetAdminUsers)
 
 	}
@@ -0,0 +1,32 @@
+package routes
+
+import (
+	"project-backend/controllers"
+	"project-backend/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+func NotificationRoutes(r *gin.Engine) {
+	// Kotak masuk notifikasi milik user login (pelapor maupun admin)
+	notif := r.Group("/notifications")
+	notif.Use(middleware.AuthMiddleware())
+	notif.GET("", controllers.GetNotifications)
+	notif.GET("/unread-count", controllers.GetUnreadNotificationCount)
+	notif.POST("/read-all", controllers.MarkAllNotificationsRead)
+	notif.GET("/preferences", controllers.GetNotificationPreferences)
+	notif.PUT("/preferences", controllers.UpdateNotificationPreferences)
+	notif.POST("/:id/read", controllers.MarkNotificationRead)
+
+	// Tautan berhenti berlangganan dari email notifikasi (tanpa login, ditandatangani);
+	// GET hanya konfirmasi, POST (tombol konfirmasi atau one-click RFC 8058) yang menyimpan
+	r.GET("/notifications/unsubscribe", controllers.ConfirmUnsubscribeEmail)
+	r.POST("/notifications/unsubscribe", controllers.UnsubscribeEmail)
+
+	// Balasan pelapor yang diteruskan gateway WhatsApp/SMS (STOP/MULAI)
+	r.POST("/messaging/inbound", controllers.ReceiveInboundMessage)
+
+	// Stream event real-time (SSE); EventSource memakai tiket dari /events/ticket
+	r.POST("/events/ticket", middleware.AuthMiddleware(), controllers.CreateStreamTicket)
+	r.GET("/events/stream", middleware.StreamAuthMiddleware(), controllers.StreamEvents)
+}
@@ -0,0 +1,20 @@
+package routes
+
+import (
+	"project-backend/controllers"
+	"project-backend/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+func RegionRoutes(r *gin.Engine) {
+	// Endpoint publik (dropdown wilayah)
+	r.GET("/regions", controllers.GetRegions)
+
+	auth := r.Group("/regions")
+	auth.Use(middleware.AuthMiddleware(), middleware.SuperadminMiddleware())
+	{
+		auth.POST("", middleware.AuditMiddleware("region.create", "region"), controllers.CreateRegion)
+		auth.POST("/resolve-reports", middleware.AuditMiddleware("region.resolve_reports", "report"), controllers.ResolveReportRegions)
+	}
+}
@@ -12,6 +12,12 @@ func ReportRoutes(r *gin.Engine) {
 	r.GET("/reports/public", controllers.GetLatestReports)
 	r.GET("/reports/public/:id", controllers.GetPublicReportByID)
 
+	// Pencarian laporan berdasarkan lokasi (radius dan area peta)
+	r.GET("/reports/nearby", controllers.GetNearbyReports)
+	r.GET("/reports/bbox", controllers.GetReportsInBBox)
+	r.GET("/reports/map", controllers.GetReportsMap)
+	r.GET("/wilayah/lookup", controllers.LookupRegion)
+
 	// Endpoint total aduan (letakkan sebelum /:id)
 	r.GET("/reports/total", controllers.GetTotalReports)
 
@@ -23,10 +29,28 @@ func ReportRoutes(r *gin.Engine) {
 	report.GET("/all", controllers.GetAllReports)
 	report.GET("/filter", controllers.GetReportsFiltered)
 
+	// Dukungan "saya juga" (satu user satu dukungan per laporan)
+	report.GET("/:id/endorse", controllers.GetEndorsementStatus)
+	report.POST("/:id/endorse", controllers.EndorseReport)
+	report.DELETE("/:id/endorse", controllers.UnendorseReport)
+
+	// Pelapor memperbaiki atau membatalkan laporan selama masih Diajukan
+	report.PUT("/:id", controllers.UpdateMyReport)
+	report.POST("/:id/withdraw", controllers.WithdrawMyReport)
+	report.GET("/:id/revisions", controllers.GetReportRevisions)
+
+	// Bukti pengaduan (PDF) untuk pelapor
+	report.GET("/:id/receipt", controllers.GetReportReceipt)
+
+	// Pelapor menilai penyelesaian atau membuka kembali laporan yang sudah Selesai
+	report.POST("/:id/rating", controllers.RateReport)
+	report.POST("/:id/reopen", controllers.ReopenReport)
+
 	// Routes komentar
 	r.POST("/comments", middleware.AuthMiddleware(), controllers.CreateComment)
 	r.GET("/comments/:report_id", middleware.AuthMiddleware(), controllers.GetCommentsByReport)
 	r.GET("/reports/search", controllers.SearchReportByTrackingID)
+	r.GET("/reports/qr/:tracking_id", controllers.GetTrackingQR)
 
 	// Routes tindak lanjut
 	r.POST("/followups", middleware.AuthMiddleware(), controllers.CreateFollowUp)
@@ -36,9 +60,16 @@ func ReportRoutes(r *gin.Engine) {
 	reportAdmin := report.Group("/admin")
 	reportAdmin.Use(middleware.AdminMiddleware())
 	reportAdmin.GET("", controllers.GetReportsAdmin)
-	reportAdmin.PATCH("/:id/status", controllers.UpdateReportStatus)
-	reportAdmin.PUT("/:id/status", controllers.UpdateReportStatus)
-	reportAdmin.PATCH("/:id/update", controllers.UpdateReportAdmin)
+	reportAdmin.GET("/search", controllers.SearchReports)
+	reportAdmin.POST("/search/reindex", controllers.ReindexSearch)
+	reportAdmin.GET("/export", middleware.AuditMiddleware("report.export", "report"), controllers.ExportReports)
+	reportAdmin.PATCH("/:id/status", middleware.AuditMiddleware("report.status_change", "report"), controllers.UpdateReportStatus)
+	reportAdmin.PUT("/:id/status", middleware.AuditMiddleware("report.status_change", "report"), controllers.UpdateReportStatus)
+	reportAdmin.PATCH("/:id/update", middleware.AuditMiddleware("report.update", "report"), controllers.UpdateReportAdmin)
+	reportAdmin.PATCH("/:id/priority", middleware.AuditMiddleware("report.priority", "report"), controllers.SetReportPriority)
+	reportAdmin.GET("/:id/revisions", controllers.GetReportRevisions)
+	reportAdmin.POST("/:id/revisions/:version/revert", middleware.AuditMiddleware("report.revert", "report"), controllers.RevertReportRevision)
+	reportAdmin.POST("/priority/recalculate", controllers.RecalculatePriorities)
 
 	// letakkan GET /reports/:id di akhir semua route /reports
 	report.GET("/:id", controllers.GetReportByID)
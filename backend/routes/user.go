@@ -14,7 +14,7 @@ func UserRoutes(r *gin.Engine) {
 		// umum
 		userGroup.GET("", controllers.GetAllUsers)
 		userGroup.GET("/:id", controllers.GetUserByID)
-		userGroup.PUT("/:id", controllers.UpdateUser)
+		userGroup.PUT("/:id", middleware.AuditMiddleware("user.update", "user"), controllers.UpdateUser)
 		userGroup.GET("/posisi", controllers.GetAdminByCategory)
 
 		// user biasa update profil sendiri
@@ -22,15 +22,15 @@ func UserRoutes(r *gin.Engine) {
 		userGroup.PUT("/password", controllers.UpdatePassword)
 
 		// hanya superadmin
-		userGroup.POST("/create-admin", controllers.CreateAdmin)
+		userGroup.POST("/create-admin", middleware.AuditMiddleware("user.create_admin", "user"), controllers.CreateAdmin)
 
 		// TAMBAHKAN route DELETE yang hilang untuk soft delete
-		userGroup.DELETE("/:id", controllers.DeleteUser)
+		userGroup.DELETE("/:id", middleware.AuditMiddleware("user.soft_delete", "user"), controllers.DeleteUser)
 
 		userGroup.GET("/deleted", controllers.GetDeletedUsers)
-		userGroup.PATCH("/:id/toggle-active", controllers.ToggleActiveUser)
-		userGroup.PATCH("/:id/restore", controllers.RestoreUser)
-		userGroup.DELETE("/:id/hard-delete", controllers.HardDeleteUser)
+		userGroup.PATCH("/:id/toggle-active", middleware.AuditMiddleware("user.toggle_active", "user"), controllers.ToggleActiveUser)
+		userGroup.PATCH("/:id/restore", middleware.AuditMiddleware("user.restore", "user"), controllers.RestoreUser)
+		userGroup.DELETE("/:id/hard-delete", middleware.AuditMiddleware("user.hard_delete", "user"), controllers.HardDeleteUser)
 
 		// userGroup.PUT("/:id/toggle", middleware.SuperadminMiddleware(), controllers.ToggleActiveUser)
 	}
@@ -0,0 +1,83 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseCronInvalid(t *testing.T) {
+	specs := []string{
+		"",
+		"* * * *",
+		"* * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"*/0 * * * *",
+		"5-1 * * * *",
+		"a * * * *",
+		"1-x * * * *",
+		"@sometimes",
+	}
+	for _, spec := range specs {
+		if _, err := ParseCron(spec); err != ErrInvalidCron {
+			t.Errorf("ParseCron(%q) error = %v, want ErrInvalidCron", spec, err)
+		}
+	}
+}
+
+func TestCronNext(t *testing.T) {
+	// Jumat, 15 Agustus 2025
+	after := time.Date(2025, 8, 15, 10, 7, 30, 0, time.UTC)
+	at := func(y int, m time.Month, d, h, min int) time.Time {
+		return time.Date(y, m, d, h, min, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name string
+		spec string
+		want time.Time
+	}{
+		{"setiap menit", "* * * * *", at(2025, 8, 15, 10, 8)},
+		{"setiap 10 menit", "*/10 * * * *", at(2025, 8, 15, 10, 10)},
+		{"awal jam", "0 * * * *", at(2025, 8, 15, 11, 0)},
+		{"harian", "30 3 * * *", at(2025, 8, 16, 3, 30)},
+		{"rentang jam dengan langkah", "15 8-17/2 * * *", at(2025, 8, 15, 10, 15)},
+		{"daftar menit", "5,45 * * * *", at(2025, 8, 15, 10, 45)},
+		{"hari Senin", "0 9 * * 1", at(2025, 8, 18, 9, 0)},
+		{"hari 7 adalah Minggu", "0 0 * * 7", at(2025, 8, 17, 0, 0)},
+		{"tanggal atau hari", "0 0 13 * 5", at(2025, 8, 22, 0, 0)},
+		{"awal bulan", "0 0 1 * *", at(2025, 9, 1, 0, 0)},
+		{"singkatan", "@yearly", at(2026, 1, 1, 0, 0)},
+		{"tahun kabisat", "0 0 29 2 *", at(2028, 2, 29, 0, 0)},
+		{"tanggal mustahil", "0 0 30 2 *", time.Time{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := ParseCron(tt.spec)
+			if err != nil {
+				t.Fatalf("ParseCron(%q): %v", tt.spec, err)
+			}
+			if got := c.Next(after); !got.Equal(tt.want) {
+				t.Errorf("Next = %v, want %v", got, tt.want)
+			}
+			if c.String() != tt.spec {
+				t.Errorf("String() = %q, want %q", c.String(), tt.spec)
+			}
+		})
+	}
+}
+
+func TestCronNextOnSchedule(t *testing.T) {
+	// waktu yang tepat jatuh pada jadwal menghasilkan jadwal berikutnya, bukan dirinya
+	c, err := ParseCron("*/10 * * * *")
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2025, 8, 15, 10, 10, 0, 0, time.UTC)
+	if got, want := c.Next(now), now.Add(10*time.Minute); !got.Equal(want) {
+		t.Errorf("Next = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,84 @@
+package scheduler
+
+import (
+	"errors"
+	"time"
+)
+
+// Frekuensi langganan
+const (
+	Daily   = "daily"
+	Weekly  = "weekly"
+	Monthly = "monthly"
+)
+
+// Schedule adalah jadwal berulang pada jam tertentu (waktu lokal server).
+// Weekday dipakai untuk Weekly (0 = Minggu) dan Day untuk Monthly (1-28 agar
+// selalu ada di setiap bulan).
+type Schedule struct {
+	Frequency string
+	Hour      int
+	Weekday   int
+	Day       int
+}
+
+var ErrInvalidSchedule = errors.New("jadwal tidak valid: frequency harus daily/weekly/monthly, hour 0-23, weekday 0-6, day 1-28")
+
+// Validate memeriksa nilai jadwal
+func (s Schedule) Validate() error {
+	if s.Hour < 0 || s.Hour > 23 {
+		return ErrInvalidSchedule
+	}
+	switch s.Frequency {
+	case Daily:
+		return nil
+	case Weekly:
+		if s.Weekday < 0 || s.Weekday > 6 {
+			return ErrInvalidSchedule
+		}
+		return nil
+	case Monthly:
+		if s.Day < 1 || s.Day > 28 {
+			return ErrInvalidSchedule
+		}
+		return nil
+	}
+	return ErrInvalidSchedule
+}
+
+// Next mengembalikan waktu jadwal pertama yang lebih besar dari after
+func (s Schedule) Next(after time.Time) time.Time {
+	after = after.In(time.Local)
+	t := time.Date(after.Year(), after.Month(), after.Day(), s.Hour, 0, 0, 0, time.Local)
+	switch s.Frequency {
+	case Weekly:
+		t = t.AddDate(0, 0, (s.Weekday-int(t.Weekday())+7)%7)
+		if !t.After(after) {
+			t = t.AddDate(0, 0, 7)
+		}
+	case Monthly:
+		t = time.Date(after.Year(), after.Month(), s.Day, s.Hour, 0, 0, 0, time.Local)
+		if !t.After(after) {
+			t = t.AddDate(0, 1, 0)
+		}
+	default:
+		if !t.After(after) {
+			t = t.AddDate(0, 0, 1)
+		}
+	}
+	return t
+}
+
+// Window mengembalikan rentang data [from, to) yang dirangkum oleh kiriman pada at:
+// sehari, seminggu atau sebulan penuh yang berakhir pada tengah malam hari at
+func (s Schedule) Window(at time.Time) (from, to time.Time) {
+	at = at.In(time.Local)
+	to = time.Date(at.Year(), at.Month(), at.Day(), 0, 0, 0, 0, time.Local)
+	switch s.Frequency {
+	case Weekly:
+		return to.AddDate(0, 0, -7), to
+	case Monthly:
+		return to.AddDate(0, -1, 0), to
+	}
+	return to.AddDate(0, 0, -1), to
+}
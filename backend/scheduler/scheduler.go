@@ -0,0 +1,75 @@
+// Package scheduler menjalankan tugas berkala di dalam proses server (tanpa cron
+// eksternal), menghitung jadwal berikutnya untuk langganan laporan dan membaca
+// ekspresi cron untuk job terjadwal.
+package scheduler
+
+import (
+	"log"
+	"sync"
+	"time"
+)
+
+// Task adalah tugas yang dijalankan setiap Interval. now adalah waktu tick.
+type Task struct {
+	Name     string
+	Interval time.Duration
+	Run      func(now time.Time) error
+}
+
+// Runner menjalankan setiap Task di goroutine-nya sendiri. Satu Task tidak pernah
+// berjalan tumpang tindih dengan dirinya sendiri; tick yang terlewat saat Task masih
+// berjalan dibuang.
+type Runner struct {
+	tasks []Task
+	stop  chan struct{}
+	wg    sync.WaitGroup
+}
+
+func New() *Runner {
+	return &Runner{stop: make(chan struct{})}
+}
+
+// Every mendaftarkan tugas; harus dipanggil sebelum Start
+func (r *Runner) Every(name string, interval time.Duration, run func(now time.Time) error) {
+	r.tasks = append(r.tasks, Task{Name: name, Interval: interval, Run: run})
+}
+
+// Start menjalankan semua tugas di background
+func (r *Runner) Start() {
+	for _, t := range r.tasks {
+		r.wg.Add(1)
+		go r.loop(t)
+	}
+}
+
+// Stop menghentikan runner dan menunggu tugas yang sedang berjalan selesai
+func (r *Runner) Stop() {
+	close(r.stop)
+	r.wg.Wait()
+}
+
+func (r *Runner) loop(t Task) {
+	defer r.wg.Done()
+	ticker := time.NewTicker(t.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-r.stop:
+			return
+		case now := <-ticker.C:
+			runTask(t, now)
+		}
+	}
+}
+
+// runTask menjalankan satu tugas; error dan panic hanya dicatat agar runner tetap hidup
+func runTask(t Task, now time.Time) {
+	defer func() {
+		if p := recover(); p != nil {
+			log.Printf("scheduler: tugas %s panic: %v", t.Name, p)
+		}
+	}()
+	if err := t.Run(now); err != nil {
+		log.Printf("scheduler: tugas %s gagal: %v", t.Name, err)
+	}
+}
@@ -0,0 +1,208 @@
+package search
+
+import (
+	"fmt"
+	"project-backend/models"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// mysqlSearcher memakai tabel search_documents dengan FULLTEXT index per kolom
+// dan satu FULLTEXT gabungan untuk penyaringan.
+type mysqlSearcher struct {
+	db *gorm.DB
+}
+
+func (s *mysqlSearcher) Setup() error {
+	return s.db.AutoMigrate(&models.SearchDocument{})
+}
+
+func (s *mysqlSearcher) Index(doc Document) error {
+	row := models.SearchDocument{
+		ReportID:   doc.ReportID,
+		Title:      doc.Title,
+		Body:       doc.Body,
+		Discussion: doc.Discussion,
+		UpdatedAt:  time.Now(),
+	}
+	return s.db.Clauses(clause.OnConflict{UpdateAll: true}).Create(&row).Error
+}
+
+func (s *mysqlSearcher) Delete(reportID uint) error {
+	return s.db.Delete(&models.SearchDocument{}, reportID).Error
+}
+
+func (s *mysqlSearcher) Search(q Query) ([]Hit, int64, error) {
+	if len(q.Terms) == 0 {
+		return nil, 0, nil
+	}
+	terms := strings.Join(q.Terms, " ")
+
+	base := s.db.Table("search_documents").
+		Joins("JOIN reports ON reports.id = search_documents.report_id").
+		Where("MATCH(search_documents.title, search_documents.body, search_documents.discussion) AGAINST (? IN NATURAL LANGUAGE MODE)", terms)
+	if q.Scope != nil {
+		base = base.Scopes(q.Scope)
+	}
+
+	var total int64
+	if err := base.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	score := fmt.Sprintf(
+		"(%.1f * MATCH(search_documents.title) AGAINST (? IN NATURAL LANGUAGE MODE) + "+
+			"%.1f * MATCH(search_documents.body) AGAINST (? IN NATURAL LANGUAGE MODE) + "+
+			"%.1f * MATCH(search_documents.discussion) AGAINST (? IN NATURAL LANGUAGE MODE)) AS score",
+		weightTitle, weightBody, weightDiscussion)
+
+	var hits []Hit
+	err := base.Select("search_documents.report_id, "+score, terms, terms, terms).
+		Order("score DESC").
+		Limit(q.Limit).Offset(q.Offset).
+		Scan(&hits).Error
+	return hits, total, err
+}
+
+// sqliteSearcher memakai tabel virtual FTS5 dengan ranking bm25 berbobot per kolom
+type sqliteSearcher struct {
+	db *gorm.DB
+}
+
+const ftsTable = "report_search_fts"
+
+func (s *sqliteSearcher) Setup() error {
+	return s.db.Exec("CREATE VIRTUAL TABLE IF NOT EXISTS " + ftsTable +
+		" USING fts5(report_id UNINDEXED, title, body, discussion, tokenize = 'unicode61')").Error
+}
+
+func (s *sqliteSearcher) Index(doc Document) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("DELETE FROM "+ftsTable+" WHERE report_id = ?", doc.ReportID).Error; err != nil {
+			return err
+		}
+		return tx.Exec("INSERT INTO "+ftsTable+" (report_id, title, body, discussion) VALUES (?, ?, ?, ?)",
+			doc.ReportID, doc.Title, doc.Body, doc.Discussion).Error
+	})
+}
+
+func (s *sqliteSearcher) Delete(reportID uint) error {
+	return s.db.Exec("DELETE FROM "+ftsTable+" WHERE report_id = ?", reportID).Error
+}
+
+// ftsMatch menyusun ekspresi MATCH FTS5: setiap kata dikutip agar tidak dibaca sebagai
+// operator FTS5, lalu digabung dengan OR
+func ftsMatch(terms []string) string {
+	quoted := make([]string, len(terms))
+	for i, t := range terms {
+		quoted[i] = `"` + strings.ReplaceAll(t, `"`, `""`) + `"`
+	}
+	return strings.Join(quoted, " OR ")
+}
+
+func (s *sqliteSearcher) Search(q Query) ([]Hit, int64, error) {
+	if len(q.Terms) == 0 {
+		return nil, 0, nil
+	}
+	base := s.db.Table(ftsTable).
+		Joins("JOIN reports ON reports.id = "+ftsTable+".report_id").
+		Where(ftsTable+" MATCH ?", ftsMatch(q.Terms))
+	if q.Scope != nil {
+		base = base.Scopes(q.Scope)
+	}
+
+	var total int64
+	if err := base.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// bm25 bernilai negatif (makin kecil makin relevan), dibalik agar konsisten dengan MySQL
+	score := fmt.Sprintf("-bm25(%s, %.1f, %.1f, %.1f, %.1f) AS score",
+		ftsTable, 0.0, weightTitle, weightBody, weightDiscussion)
+
+	var hits []Hit
+	err := base.Select(ftsTable + ".report_id AS report_id, " + score).
+		Order("score DESC, " + ftsTable + ".report_id DESC").
+		Limit(q.Limit).Offset(q.Offset).
+		Scan(&hits).Error
+	return hits, total, err
+}
+
+// likeSearcher mencari langsung di tabel laporan, komentar dan tindak lanjut dengan LIKE.
+// Dipakai jika FULLTEXT index atau tabel FTS5 tidak bisa dibuat; lebih lambat tapi tidak butuh indeks,
+// sehingga Index dan Delete tidak melakukan apa pun.
+type likeSearcher struct {
+	db *gorm.DB
+}
+
+func (s *likeSearcher) Setup() error               { return nil }
+func (s *likeSearcher) Index(doc Document) error   { return nil }
+func (s *likeSearcher) Delete(reportID uint) error { return nil }
+
+// Kolom yang dicocokkan likeSearcher beserta bobotnya
+var likeColumns = []struct {
+	expr   string
+	weight float64
+}{
+	{"reports.title", weightTitle},
+	{"reports.description", weightBody},
+	{"reports.lokasi", weightBody},
+	{"EXISTS (SELECT 1 FROM comments WHERE comments.report_id = reports.id AND comments.text LIKE ?)", weightDiscussion},
+	{"EXISTS (SELECT 1 FROM follow_ups WHERE follow_ups.report_id = reports.id AND follow_ups.deskripsi LIKE ?)", weightDiscussion},
+}
+
+// likeExpr mengembalikan kondisi "kolom cocok dengan pattern"
+func likeExpr(expr string) string {
+	if strings.HasPrefix(expr, "EXISTS") {
+		return expr
+	}
+	return expr + " LIKE ?"
+}
+
+// likeQuery menyusun kondisi (laporan cocok dengan minimal satu kata) dan ekspresi skor
+// (jumlah bobot kolom yang cocok untuk tiap kata) beserta argumennya
+func likeQuery(terms []string) (where string, whereArgs []interface{}, score string, scoreArgs []interface{}) {
+	var conds, scores []string
+	for _, t := range terms {
+		// kata sudah berupa kata dasar, jadi dicari sebagai potongan kata ("lubang" ~ "berlubang")
+		pattern := "%" + escapeLike(t) + "%"
+		for _, col := range likeColumns {
+			conds = append(conds, likeExpr(col.expr))
+			whereArgs = append(whereArgs, pattern)
+			scores = append(scores, fmt.Sprintf("(CASE WHEN %s THEN %.1f ELSE 0 END)", likeExpr(col.expr), col.weight))
+			scoreArgs = append(scoreArgs, pattern)
+		}
+	}
+	return "(" + strings.Join(conds, " OR ") + ")", whereArgs, "(" + strings.Join(scores, " + ") + ") AS score", scoreArgs
+}
+
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
+
+func (s *likeSearcher) Search(q Query) ([]Hit, int64, error) {
+	if len(q.Terms) == 0 {
+		return nil, 0, nil
+	}
+	where, whereArgs, score, scoreArgs := likeQuery(q.Terms)
+
+	base := s.db.Table("reports").Where(where, whereArgs...)
+	if q.Scope != nil {
+		base = base.Scopes(q.Scope)
+	}
+
+	var total int64
+	if err := base.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var hits []Hit
+	err := base.Select("reports.id AS report_id, "+score, scoreArgs...).
+		Order("score DESC, reports.id DESC").
+		Limit(q.Limit).Offset(q.Offset).
+		Scan(&hits).Error
+	return hits, total, err
+}
@@ -0,0 +1,99 @@
+package search
+
+import (
+	"html"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	markOpen  = "<mark>"
+	markClose = "</mark>"
+)
+
+// Highlight mencari kata di text yang kata dasarnya ada di terms, lalu mengembalikan
+// potongan teks (sekitar radius karakter dari kecocokan pertama) dengan kata yang
+// cocok dibungkus <mark>. Teks di-escape sebagai HTML. ok bernilai false jika tidak ada kecocokan.
+func Highlight(text string, terms []string, radius int) (snippet string, ok bool) {
+	if text == "" || len(terms) == 0 {
+		return "", false
+	}
+	want := make(map[string]bool, len(terms))
+	for _, t := range terms {
+		want[t] = true
+	}
+
+	type span struct{ start, end int }
+	var matches []span
+
+	start := -1
+	flush := func(end int) {
+		if start < 0 {
+			return
+		}
+		word := strings.ToLower(text[start:end])
+		if want[Stem(word)] || want[word] {
+			matches = append(matches, span{start, end})
+		}
+		start = -1
+	}
+	for i, r := range text {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if start < 0 {
+				start = i
+			}
+			continue
+		}
+		flush(i)
+	}
+	flush(len(text))
+
+	if len(matches) == 0 {
+		return "", false
+	}
+
+	// batas potongan di sekitar kecocokan pertama, dirapikan ke batas spasi
+	from, to := 0, len(text)
+	if radius > 0 {
+		from = matches[0].start - radius
+		if from < 0 {
+			from = 0
+		} else if sp := strings.IndexByte(text[from:matches[0].start], ' '); sp >= 0 {
+			from += sp + 1
+		}
+		to = matches[0].end + radius
+		if to > len(text) {
+			to = len(text)
+		} else if sp := strings.LastIndexByte(text[matches[0].end:to], ' '); sp >= 0 {
+			to = matches[0].end + sp
+		}
+		for from > 0 && !utf8.RuneStart(text[from]) {
+			from--
+		}
+		for to < len(text) && !utf8.RuneStart(text[to]) {
+			to++
+		}
+	}
+
+	var b strings.Builder
+	if from > 0 {
+		b.WriteString("…")
+	}
+	pos := from
+	for _, m := range matches {
+		if m.start < from || m.end > to {
+			continue
+		}
+		b.WriteString(html.EscapeString(text[pos:m.start]))
+		b.WriteString(markOpen)
+		b.WriteString(html.EscapeString(text[m.start:m.end]))
+		b.WriteString(markClose)
+		pos = m.end
+	}
+	b.WriteString(html.EscapeString(text[pos:to]))
+	if to < len(text) {
+		b.WriteString("…")
+	}
+	return b.String(), true
+}
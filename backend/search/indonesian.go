@@ -0,0 +1,146 @@
+package search
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Kata umum bahasa Indonesia yang tidak berguna untuk pencarian
+var stopwords = map[string]bool{}
+
+func init() {
+	for _, w := range strings.Fields(`
+		ada adalah agar akan aku anda antara apa apakah atau bagai bagaimana bahwa baik banyak
+		belum beberapa begitu bila bisa boleh bukan dalam dan dapat dari demi dengan di dia
+		hal hanya harus hingga ia ialah ini itu jadi jika juga kalau kami kamu karena ke kemudian
+		kepada ketika kita lagi lah lain lalu maka masih mau melalui mereka mungkin namun nya oleh
+		pada para pernah pun saat saja sama sampai sangat saya se sebagai sebelum sedang sehingga
+		sejak selain semua seperti serta sesudah setelah sudah supaya tak tanpa telah tentang
+		tersebut tetapi tidak untuk yaitu yakni yang
+	`) {
+		stopwords[w] = true
+	}
+}
+
+// Tokenize memecah teks menjadi kata huruf kecil (huruf dan angka saja)
+func Tokenize(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// Analyze mengubah teks menjadi daftar kata dasar tanpa stopword
+func Analyze(text string) []string {
+	var terms []string
+	for _, tok := range Tokenize(text) {
+		if stopwords[tok] {
+			continue
+		}
+		stem := Stem(tok)
+		if len(stem) < 2 || stopwords[stem] {
+			continue
+		}
+		terms = append(terms, stem)
+	}
+	return terms
+}
+
+// Normalize adalah Analyze yang digabung kembali menjadi satu string
+func Normalize(text string) string {
+	return strings.Join(Analyze(text), " ")
+}
+
+func isVowel(b byte) bool {
+	return b == 'a' || b == 'i' || b == 'u' || b == 'e' || b == 'o'
+}
+
+// Stem mengambil kata dasar dengan versi sederhana algoritma Nazief-Adriani tanpa kamus:
+// buang partikel (-lah, -kah, -tah, -pun), kata ganti milik (-ku, -mu, -nya),
+// akhiran (-i, -kan, -an) lalu awalan (di-, ke-, se-, me-, be-, pe-, te-).
+// Kata hasil minimal 3 huruf agar tidak terlalu agresif.
+func Stem(word string) string {
+	if len(word) <= 4 || strings.IndexFunc(word, unicode.IsDigit) >= 0 {
+		return word
+	}
+
+	w := word
+	w = trimSuffix(w, 5, "lah", "kah", "tah", "pun")
+	w = trimSuffix(w, 3, "nya", "ku", "mu")
+
+	// akhiran turunan hanya dibuang jika sisa kata cukup panjang ("jalan" tetap "jalan")
+	beforeSuffix := w
+	w = trimSuffix(w, 4, "kan", "an", "i")
+
+	stemmed := removePrefix(w)
+	if len(stemmed) < 3 {
+		// akhiran ternyata bagian kata dasar (mis. "pantai"), coba tanpa membuang akhiran
+		stemmed = removePrefix(beforeSuffix)
+	}
+	if len(stemmed) < 3 {
+		return word
+	}
+	return stemmed
+}
+
+func trimSuffix(w string, minLen int, suffixes ...string) string {
+	for _, s := range suffixes {
+		if strings.HasSuffix(w, s) && len(w)-len(s) >= minLen {
+			return w[:len(w)-len(s)]
+		}
+	}
+	return w
+}
+
+// removePrefix membuang hingga dua awalan beserta aturan peluluhan bunyi.
+// Setelah awalan nasal (me-/pe- yang luluh) tidak ada awalan lain yang dibuang.
+func removePrefix(w string) string {
+	for i := 0; i < 2; i++ {
+		next, final := removeOnePrefix(w)
+		// awalan kedua hanya dibuang jika sisa kata masih cukup panjang ("bersih" tidak jadi "sih")
+		if next == w || len(next) < 3+i {
+			break
+		}
+		w = next
+		if final {
+			break
+		}
+	}
+	return w
+}
+
+func removeOnePrefix(w string) (string, bool) {
+	switch {
+	case strings.HasPrefix(w, "di"), strings.HasPrefix(w, "ke"), strings.HasPrefix(w, "se"):
+		return w[2:], false
+	case strings.HasPrefix(w, "ber"), strings.HasPrefix(w, "ter"), strings.HasPrefix(w, "per"):
+		return w[3:], false
+	case strings.HasPrefix(w, "memper"):
+		return w[6:], true
+
+	case strings.HasPrefix(w, "meny"), strings.HasPrefix(w, "peny"):
+		// menyapu -> sapu
+		return "s" + w[4:], true
+	case strings.HasPrefix(w, "meng"), strings.HasPrefix(w, "peng"):
+		// mengangkut -> angkut (tanpa kamus, peluluhan k tidak dikembalikan)
+		return w[4:], true
+	case strings.HasPrefix(w, "mem"), strings.HasPrefix(w, "pem"):
+		rest := w[3:]
+		if len(rest) > 0 && isVowel(rest[0]) {
+			// memukul -> pukul
+			return "p" + rest, true
+		}
+		return rest, true
+	case strings.HasPrefix(w, "men"), strings.HasPrefix(w, "pen"):
+		rest := w[3:]
+		if len(rest) > 0 && isVowel(rest[0]) {
+			// menanam -> tanam
+			return "t" + rest, true
+		}
+		return rest, true
+	case strings.HasPrefix(w, "me"), strings.HasPrefix(w, "pe"):
+		return w[2:], true
+	case strings.HasPrefix(w, "be"), strings.HasPrefix(w, "te"):
+		return w[2:], false
+	}
+	return w, false
+}
@@ -0,0 +1,146 @@
+// Package search menyediakan pencarian full-text atas laporan beserta komentar
+// dan tindak lanjutnya. Teks dinormalisasi dengan stemming dan stopword bahasa
+// Indonesia sebelum diindeks, lalu disimpan di FULLTEXT index (MySQL) atau
+// tabel virtual FTS5 (SQLite) di balik satu interface Searcher. Jika indeks
+// tidak bisa dibuat, pencarian memakai LIKE langsung ke tabel laporan.
+package search
+
+import (
+	"context"
+	"project-backend/models"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// Bobot relevansi per kolom: judul > deskripsi/lokasi > komentar/tindak lanjut
+const (
+	weightTitle      = 3.0
+	weightBody       = 2.0
+	weightDiscussion = 1.0
+)
+
+// Document adalah teks satu laporan yang sudah dinormalisasi
+type Document struct {
+	ReportID   uint
+	Title      string
+	Body       string
+	Discussion string
+}
+
+// Hit adalah satu hasil pencarian
+type Hit struct {
+	ReportID uint    `json:"report_id"`
+	Score    float64 `json:"score"`
+}
+
+// Query adalah parameter pencarian. Scope dipakai untuk membatasi laporan
+// (mis. kategori admin); kolom tabel laporan dapat dirujuk sebagai "reports.".
+type Query struct {
+	Terms  []string
+	Scope  func(*gorm.DB) *gorm.DB
+	Limit  int
+	Offset int
+}
+
+// Searcher adalah backend pencarian full-text
+type Searcher interface {
+	// Setup membuat tabel/indeks yang dibutuhkan
+	Setup() error
+	Index(doc Document) error
+	Delete(reportID uint) error
+	Search(q Query) ([]Hit, int64, error)
+}
+
+// New memilih backend sesuai dialect database
+func New(db *gorm.DB) Searcher {
+	if db.Dialector.Name() == "sqlite" {
+		return &sqliteSearcher{db: db}
+	}
+	return &mysqlSearcher{db: db}
+}
+
+var (
+	defaultDB       *gorm.DB
+	defaultSearcher Searcher
+)
+
+// Init menyiapkan searcher bawaan yang dipakai IndexReport dan Search. Jika Setup
+// gagal, searcher LIKE dipakai sebagai gantinya dan error Setup tetap dikembalikan.
+func Init(db *gorm.DB) error {
+	defaultDB = db
+	s := New(db)
+	if err := s.Setup(); err != nil {
+		defaultSearcher = &likeSearcher{db: db}
+		return err
+	}
+	defaultSearcher = s
+	return nil
+}
+
+// Default mengembalikan searcher bawaan (nil jika Init belum dipanggil)
+func Default() Searcher {
+	return defaultSearcher
+}
+
+// BuildDocument menyusun dokumen pencarian dari laporan, komentar dan tindak lanjutnya
+func BuildDocument(report models.Report, comments []models.Comment, followups []models.FollowUp) Document {
+	var disc []string
+	for _, cm := range comments {
+		disc = append(disc, cm.Text)
+	}
+	for _, f := range followups {
+		disc = append(disc, f.Deskripsi)
+	}
+	return Document{
+		ReportID:   report.ID,
+		Title:      Normalize(report.Title),
+		Body:       Normalize(report.Description + " " + report.Lokasi),
+		Discussion: Normalize(strings.Join(disc, " ")),
+	}
+}
+
+// IndexReport memperbarui indeks untuk satu laporan. Dipanggil dari antrean job
+// agar pengindeksan tidak memperlambat request dan bisa dicoba ulang jika gagal.
+func IndexReport(ctx context.Context, reportID uint) error {
+	if defaultSearcher == nil {
+		return nil
+	}
+	return indexReport(defaultDB.WithContext(ctx), defaultSearcher, reportID)
+}
+
+func indexReport(db *gorm.DB, s Searcher, reportID uint) error {
+	var report models.Report
+	if err := db.First(&report, reportID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return s.Delete(reportID)
+		}
+		return err
+	}
+	var comments []models.Comment
+	var followups []models.FollowUp
+	if err := db.Where("report_id = ?", reportID).Find(&comments).Error; err != nil {
+		return err
+	}
+	if err := db.Where("report_id = ?", reportID).Find(&followups).Error; err != nil {
+		return err
+	}
+	return s.Index(BuildDocument(report, comments, followups))
+}
+
+// ReindexAll membangun ulang indeks seluruh laporan
+func ReindexAll() (int, error) {
+	if defaultSearcher == nil {
+		return 0, nil
+	}
+	var ids []uint
+	if err := defaultDB.Model(&models.Report{}).Order("id").Pluck("id", &ids).Error; err != nil {
+		return 0, err
+	}
+	for i, id := range ids {
+		if err := indexReport(defaultDB, defaultSearcher, id); err != nil {
+			return i, err
+		}
+	}
+	return len(ids), nil
+}
@@ -0,0 +1,108 @@
+package search
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestStem(t *testing.T) {
+	tests := []struct {
+		word, want string
+	}{
+		{"jalan", "jalan"},
+		{"berlubang", "lubang"},
+		{"menyapu", "sapu"},
+		{"memukul", "pukul"},
+		{"menanam", "tanam"},
+		{"kebersihan", "bersih"},
+		{"rusaknya", "rusak"},
+		{"2025", "2025"},
+	}
+	for _, tt := range tests {
+		if got := Stem(tt.word); got != tt.want {
+			t.Errorf("Stem(%q) = %q, want %q", tt.word, got, tt.want)
+		}
+	}
+}
+
+func TestAnalyze(t *testing.T) {
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{"Jalan berlubang di depan pasar", []string{"jalan", "lubang", "depan", "pasar"}},
+		{"yang dan di", nil},
+		{"Sampah, menumpuk!!", []string{"sampah", "tumpuk"}},
+	}
+	for _, tt := range tests {
+		if got := Analyze(tt.text); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Analyze(%q) = %v, want %v", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestHighlight(t *testing.T) {
+	tests := []struct {
+		name   string
+		text   string
+		terms  []string
+		radius int
+		want   string
+		ok     bool
+	}{
+		{"kata dasar", "Jalan berlubang parah", []string{"lubang"}, 0, "Jalan <mark>berlubang</mark> parah", true},
+		{"escape html", "<b>banjir</b>", []string{"banjir"}, 0, "&lt;b&gt;<mark>banjir</mark>&lt;/b&gt;", true},
+		{"tidak cocok", "Lampu jalan mati", []string{"sampah"}, 0, "", false},
+		{"potongan", "satu dua tiga empat banjir lima enam tujuh", []string{"banjir"}, 8, "…empat <mark>banjir</mark> lima…", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := Highlight(tt.text, tt.terms, tt.radius)
+			if got != tt.want || ok != tt.ok {
+				t.Errorf("Highlight = %q, %v; want %q, %v", got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+func TestLikeQuery(t *testing.T) {
+	where, whereArgs, score, scoreArgs := likeQuery([]string{"lubang", "50%_"})
+
+	perTerm := len(likeColumns)
+	if len(whereArgs) != 2*perTerm || len(scoreArgs) != 2*perTerm {
+		t.Fatalf("jumlah argumen = %d/%d, want %d", len(whereArgs), len(scoreArgs), 2*perTerm)
+	}
+	if got := strings.Count(where, "?"); got != len(whereArgs) {
+		t.Errorf("placeholder where = %d, argumen = %d", got, len(whereArgs))
+	}
+	if got := strings.Count(score, "?"); got != len(scoreArgs) {
+		t.Errorf("placeholder score = %d, argumen = %d", got, len(scoreArgs))
+	}
+	if whereArgs[0] != "%lubang%" {
+		t.Errorf("pattern = %v, want %%lubang%%", whereArgs[0])
+	}
+	if want := `%50\%\_%`; whereArgs[perTerm] != want {
+		t.Errorf("pattern = %v, want %s", whereArgs[perTerm], want)
+	}
+	if !strings.HasSuffix(score, " AS score") {
+		t.Errorf("score = %q", score)
+	}
+}
+
+func TestFTSMatch(t *testing.T) {
+	tests := []struct {
+		terms []string
+		want  string
+	}{
+		{[]string{"lubang"}, `"lubang"`},
+		{[]string{"lubang", "jalan"}, `"lubang" OR "jalan"`},
+		// operator FTS5 dan tanda kutip dalam kata tidak boleh lolos sebagai sintaks
+		{[]string{"NOT", `a"b`}, `"NOT" OR "a""b"`},
+	}
+	for _, tt := range tests {
+		if got := ftsMatch(tt.terms); got != tt.want {
+			t.Errorf("ftsMatch(%q) = %s, want %s", tt.terms, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,110 @@
+// Package thumbnail membuat gambar kecil (JPEG) dari foto bukti laporan untuk
+// ditampilkan di daftar dan peta tanpa memuat foto aslinya.
+package thumbnail
+
+import (
+	"errors"
+	"image"
+	"image/color"
+	"image/jpeg"
+	_ "image/png"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+const (
+	// Sisi terpanjang thumbnail dalam piksel
+	MaxSize = 320
+	quality = 80
+	// Batas ukuran foto sumber; header gambar diperiksa sebelum didekode agar foto
+	// berdimensi sangat besar tidak menghabiskan memori
+	MaxSourcePixels = 50_000_000
+)
+
+// ErrTooLarge dikembalikan Generate untuk foto yang melebihi MaxSourcePixels
+var ErrTooLarge = errors.New("dimensi foto terlalu besar untuk dibuat thumbnail")
+
+// Path mengembalikan lokasi thumbnail untuk foto src: <folder>/thumbs/<nama lengkap>.jpg.
+// Ekstensi asli dipertahankan agar foto.png dan foto.jpg tidak berbagi thumbnail.
+func Path(src string) string {
+	dir, name := filepath.Split(src)
+	return filepath.ToSlash(filepath.Join(dir, "thumbs", name+".jpg"))
+}
+
+// Generate membaca foto src (JPEG/PNG), mengecilkannya agar sisi terpanjang paling
+// banyak maxSize piksel, lalu menyimpannya sebagai JPEG di dst.
+func Generate(src, dst string, maxSize int) error {
+	f, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	cfg, _, err := image.DecodeConfig(f)
+	if err != nil {
+		return err
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 || cfg.Width*cfg.Height > MaxSourcePixels {
+		return ErrTooLarge
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	img, _, err := image.Decode(f)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+		return err
+	}
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	if err := jpeg.Encode(out, resize(img, maxSize), &jpeg.Options{Quality: quality}); err != nil {
+		out.Close()
+		os.Remove(dst)
+		return err
+	}
+	return out.Close()
+}
+
+// resize mengecilkan img dengan rata-rata area (box filter) agar hasilnya halus
+func resize(img image.Image, maxSize int) image.Image {
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	nw, nh := w, h
+	if w > maxSize || h > maxSize {
+		nw, nh = maxSize, h*maxSize/w
+	}
+	if h > w && h > maxSize {
+		nw, nh = w*maxSize/h, maxSize
+	}
+	if nw < 1 {
+		nw = 1
+	}
+	if nh < 1 {
+		nh = 1
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, nw, nh))
+	for y := 0; y < nh; y++ {
+		y0, y1 := b.Min.Y+y*h/nh, b.Min.Y+(y+1)*h/nh
+		for x := 0; x < nw; x++ {
+			x0, x1 := b.Min.X+x*w/nw, b.Min.X+(x+1)*w/nw
+			var r, g, bl, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					cr, cg, cb, ca := img.At(sx, sy).RGBA()
+					r, g, bl, a = r+uint64(cr), g+uint64(cg), bl+uint64(cb), a+uint64(ca)
+					n++
+				}
+			}
+			// bagian transparan (PNG) diberi latar putih karena JPEG tidak punya alpha
+			bg := 0xffff - a/n
+			dst.Set(x, y, color.RGBA64{uint16(r/n + bg), uint16(g/n + bg), uint16(bl/n + bg), 0xffff})
+		}
+	}
+	return dst
+}
@@ -0,0 +1,105 @@
+package thumbnail
+
+import (
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestPath(t *testing.T) {
+	tests := map[string]string{
+		"bukti_foto/20250801_jalan.jpg": "bukti_foto/thumbs/20250801_jalan.jpg.jpg",
+		"bukti_foto/20250801_jalan.png": "bukti_foto/thumbs/20250801_jalan.png.jpg",
+		"foto":                          "thumbs/foto.jpg",
+	}
+	for src, want := range tests {
+		if got := Path(src); got != want {
+			t.Errorf("Path(%q) = %q, want %q", src, got, want)
+		}
+	}
+}
+
+func writePNG(t *testing.T, path string, w, h int) {
+	t.Helper()
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.Set(x, y, color.NRGBA{uint8(x), uint8(y), 200, 255})
+		}
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if err := png.Encode(f, img); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	tests := []struct {
+		name         string
+		w, h         int
+		wantW, wantH int
+	}{
+		{"lebar", 640, 320, 320, 160},
+		{"tinggi", 200, 800, 80, 320},
+		{"sudah kecil", 100, 50, 100, 50},
+	}
+	dir := t.TempDir()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src := filepath.Join(dir, tt.name+".png")
+			writePNG(t, src, tt.w, tt.h)
+			dst := Path(src)
+			if err := Generate(src, dst, MaxSize); err != nil {
+				t.Fatalf("Generate: %v", err)
+			}
+			f, err := os.Open(dst)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer f.Close()
+			cfg, format, err := image.DecodeConfig(f)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if format != "jpeg" || cfg.Width != tt.wantW || cfg.Height != tt.wantH {
+				t.Errorf("thumbnail %s %dx%d, want jpeg %dx%d", format, cfg.Width, cfg.Height, tt.wantW, tt.wantH)
+			}
+		})
+	}
+}
+
+func TestGenerateRejectsHugeImage(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "besar.png")
+	// header PNG dengan dimensi raksasa tanpa data piksel: harus ditolak dari header saja
+	f, err := os.Create(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ihdr := []byte{
+		'I', 'H', 'D', 'R',
+		0, 0, 0x9c, 0x40, // lebar 40000
+		0, 0, 0x9c, 0x40, // tinggi 40000
+		8, 2, 0, 0, 0,
+	}
+	header := append([]byte{0x89, 'P', 'N', 'G', '\r', '\n', 0x1a, '\n', 0, 0, 0, 13}, ihdr...)
+	header = binary.BigEndian.AppendUint32(header, crc32.ChecksumIEEE(ihdr))
+	f.Write(header)
+	f.Close()
+
+	if err := Generate(src, Path(src), MaxSize); err != ErrTooLarge {
+		t.Errorf("Generate = %v, want ErrTooLarge", err)
+	}
+	if _, err := os.Stat(Path(src)); !os.IsNotExist(err) {
+		t.Error("thumbnail tidak boleh dibuat untuk foto yang ditolak")
+	}
+}
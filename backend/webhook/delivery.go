@@ -0,0 +1,172 @@
+package webhook
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"project-backend/models"
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	// Batas percobaan sebelum pengiriman ditandai failed (sekitar 4 jam total)
+	maxAttempts = 8
+	// Jeda percobaan ulang pertama; berikutnya dua kali lipat (30 detik, 1, 2, 4 ... 64 menit)
+	retryBase    = 30 * time.Second
+	queueBatch   = 50
+	claimTimeout = 5 * time.Minute
+	httpTimeout  = 10 * time.Second
+	// Potongan body respons yang disimpan di log pengiriman
+	maxResponseBody = 2048
+)
+
+var client = &http.Client{Timeout: httpTimeout}
+
+var errWebhookGone = errors.New("webhook sudah dihapus atau dinonaktifkan")
+
+// ErrRedeliveryPending dikembalikan Redeliver jika event yang sama untuk webhook itu
+// masih menunggu di antrean atau sedang dikirim ulang
+var ErrRedeliveryPending = errors.New("pengiriman event ini masih menunggu di antrean")
+
+// Attempt melakukan satu percobaan pengiriman d ke w dan mengembalikan perubahan
+// kolom delivery yang perlu disimpan (status, hasil respons, jadwal percobaan berikutnya)
+func Attempt(w models.Webhook, d models.WebhookDelivery, now time.Time) map[string]interface{} {
+	updates := map[string]interface{}{"attempts": d.Attempts + 1}
+	status, body, duration, err := post(w, d, now)
+	updates["response_status"] = status
+	updates["response_body"] = body
+	updates["duration_ms"] = duration.Milliseconds()
+
+	if err == nil {
+		updates["status"] = models.DeliverySuccess
+		updates["delivered_at"] = now
+		updates["last_error"] = ""
+		return updates
+	}
+	updates["last_error"] = err.Error()
+	if d.Attempts+1 >= maxAttempts {
+		updates["status"] = models.DeliveryFailed
+	} else {
+		updates["next_attempt_at"] = now.Add(retryBase << d.Attempts)
+	}
+	return updates
+}
+
+func post(w models.Webhook, d models.WebhookDelivery, now time.Time) (int, string, time.Duration, error) {
+	body := []byte(d.Payload)
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+	req, err := http.NewRequest(http.MethodPost, w.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, "", 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "LaporPak-Webhook/1.0")
+	req.Header.Set(HeaderEvent, d.Event)
+	req.Header.Set(HeaderDelivery, d.EventID)
+	req.Header.Set(HeaderTimestamp, timestamp)
+	req.Header.Set(HeaderSignature, Sign(w.Secret, timestamp, body))
+
+	start := time.Now()
+	resp, err := client.Do(req)
+	duration := time.Since(start)
+	if err != nil {
+		return 0, "", duration, err
+	}
+	defer resp.Body.Close()
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, maxResponseBody))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, string(respBody), duration, errors.New("respons " + resp.Status)
+	}
+	return resp.StatusCode, string(respBody), duration, nil
+}
+
+// Deliver mencoba mengirim d sekarang dan menyimpan hasilnya
+func Deliver(db *gorm.DB, d *models.WebhookDelivery, now time.Time) error {
+	var w models.Webhook
+	var updates map[string]interface{}
+	if err := db.First(&w, d.WebhookID).Error; err != nil || !w.Active {
+		updates = map[string]interface{}{"status": models.DeliveryFailed, "last_error": errWebhookGone.Error()}
+	} else {
+		updates = Attempt(w, *d, now)
+	}
+	if err := db.Model(d).Updates(updates).Error; err != nil {
+		return err
+	}
+	return db.First(d, d.ID).Error
+}
+
+// ProcessQueue mengirim pengiriman yang sudah jatuh tempo dengan klaim atomik agar
+// tidak terkirim ganda jika ada beberapa proses. Mengembalikan jumlah yang berhasil.
+func ProcessQueue(db *gorm.DB, now time.Time) (int, error) {
+	var due []models.WebhookDelivery
+	if err := db.Where("status = ? AND next_attempt_at <= ?", models.DeliveryPending, now).
+		Order("next_attempt_at").Limit(queueBatch).Find(&due).Error; err != nil {
+		return 0, err
+	}
+
+	delivered := 0
+	for i := range due {
+		d := &due[i]
+		claim := db.Model(&models.WebhookDelivery{}).
+			Where("id = ? AND status = ? AND next_attempt_at = ?", d.ID, models.DeliveryPending, d.NextAttemptAt).
+			Update("next_attempt_at", now.Add(claimTimeout))
+		if claim.Error != nil {
+			return delivered, claim.Error
+		}
+		if claim.RowsAffected == 0 {
+			continue
+		}
+		if err := Deliver(db, d, now); err != nil {
+			return delivered, err
+		}
+		if d.Status == models.DeliverySuccess {
+			delivered++
+		}
+	}
+	return delivered, nil
+}
+
+// Redeliver membuat pengiriman baru dari d (payload dan event ID yang sama) lalu
+// langsung mencobanya. Pengiriman lama tetap tersimpan di log. Jika event itu masih
+// punya pengiriman pending, atau permintaan lain baru saja membuat pengiriman ulang
+// yang sama, ErrRedeliveryPending dikembalikan.
+func Redeliver(db *gorm.DB, d models.WebhookDelivery, now time.Time) (models.WebhookDelivery, error) {
+	source := d.ID
+	retry := models.WebhookDelivery{
+		WebhookID:     d.WebhookID,
+		EventID:       d.EventID,
+		Event:         d.Event,
+		Payload:       d.Payload,
+		Status:        models.DeliveryPending,
+		NextAttemptAt: now.Add(claimTimeout), // sudah diklaim untuk percobaan langsung di bawah
+		RedeliveryOf:  &source,
+	}
+	sameEvent := db.Model(&models.WebhookDelivery{}).Where("webhook_id = ? AND event_id = ?", d.WebhookID, d.EventID)
+	var pending int64
+	if err := sameEvent.Session(&gorm.Session{}).Where("status = ?", models.DeliveryPending).Count(&pending).Error; err != nil {
+		return retry, err
+	}
+	if pending > 0 {
+		return retry, ErrRedeliveryPending
+	}
+	var last uint
+	if err := sameEvent.Session(&gorm.Session{}).Select("COALESCE(MAX(redelivery), 0)").Scan(&last).Error; err != nil {
+		return retry, err
+	}
+	retry.Redelivery = last + 1
+
+	res := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&retry)
+	if res.Error != nil {
+		return retry, res.Error
+	}
+	if res.RowsAffected == 0 {
+		return retry, ErrRedeliveryPending
+	}
+	// jika gagal, status tetap pending dan percobaan berikutnya diambil antrean
+	return retry, Deliver(db, &retry, now)
+}
@@ -0,0 +1,132 @@
+// Package webhook meneruskan event laporan ke sistem lain lewat HTTP POST berisi JSON
+// yang ditandatangani HMAC-SHA256. Pengiriman disimpan di tabel webhook_deliveries dan
+// dicoba ulang dengan jeda yang makin panjang sampai berhasil atau batas percobaan.
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"project-backend/models"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Jenis event yang bisa dilanggan
+const (
+	EventReportCreated       = "report.created"
+	EventReportStatusChanged = "report.status_changed"
+	EventFollowUpCreated     = "followup.created"
+	EventCommentCreated      = "comment.created"
+)
+
+// Events berisi semua jenis event yang bisa dilanggan
+var Events = []string{EventReportCreated, EventReportStatusChanged, EventFollowUpCreated, EventCommentCreated}
+
+// IsValidEvent memeriksa jenis event
+func IsValidEvent(e string) bool {
+	for _, ev := range Events {
+		if ev == e {
+			return true
+		}
+	}
+	return false
+}
+
+// Header yang dikirim bersama setiap pengiriman. Penerima memverifikasi
+// Signature = "sha256=" + hex(HMAC-SHA256(secret, Timestamp + "." + body)).
+const (
+	HeaderEvent     = "X-Webhook-Event"
+	HeaderDelivery  = "X-Webhook-Delivery"
+	HeaderTimestamp = "X-Webhook-Timestamp"
+	HeaderSignature = "X-Webhook-Signature"
+)
+
+// Envelope adalah isi JSON yang dikirim ke webhook
+type Envelope struct {
+	ID        string      `json:"id"`
+	Event     string      `json:"event"`
+	CreatedAt time.Time   `json:"created_at"`
+	Data      interface{} `json:"data"`
+}
+
+// Sign mengembalikan nilai header X-Webhook-Signature untuk body pada timestamp
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// NewSecret membuat secret acak untuk webhook baru
+func NewSecret() string {
+	return "whsec_" + randomHex(24)
+}
+
+func randomHex(n int) string {
+	b := make([]byte, n)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// Matches memeriksa apakah webhook melanggan event pada kategori categoryID
+func Matches(w models.Webhook, event string, categoryID *uint) bool {
+	if !w.Active || !containsItem(w.Events, event) {
+		return false
+	}
+	if strings.TrimSpace(w.CategoryIDs) == "" {
+		return true
+	}
+	return categoryID != nil && containsItem(w.CategoryIDs, strconv.FormatUint(uint64(*categoryID), 10))
+}
+
+func containsItem(list, item string) bool {
+	for _, v := range strings.Split(list, ",") {
+		if strings.TrimSpace(v) == item {
+			return true
+		}
+	}
+	return false
+}
+
+// Enqueue membuat pengiriman event untuk setiap webhook aktif yang cocok dan
+// mengembalikan jumlah yang baru dibuat. eventID menjadi ID di payload dan header
+// X-Webhook-Delivery; indeks unik (webhook, event) membuat webhook yang sudah punya
+// pengiriman untuk eventID dilewati sehingga aman dipanggil ulang, juga dari beberapa
+// proses sekaligus. Pengiriman dilakukan oleh ProcessQueue.
+func Enqueue(db *gorm.DB, eventID, event string, categoryID *uint, at time.Time, data interface{}) (int, error) {
+	var hooks []models.Webhook
+	if err := db.Where("active = ?", true).Find(&hooks).Error; err != nil {
+		return 0, err
+	}
+
+	payload, err := json.Marshal(Envelope{ID: eventID, Event: event, CreatedAt: at, Data: data})
+	if err != nil {
+		return 0, err
+	}
+	var deliveries []models.WebhookDelivery
+	for _, w := range hooks {
+		if Matches(w, event, categoryID) {
+			deliveries = append(deliveries, models.WebhookDelivery{
+				WebhookID:     w.ID,
+				EventID:       eventID,
+				Event:         event,
+				Payload:       string(payload),
+				Status:        models.DeliveryPending,
+				NextAttemptAt: time.Now(),
+			})
+		}
+	}
+	if len(deliveries) == 0 {
+		return 0, nil
+	}
+	res := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&deliveries)
+	return int(res.RowsAffected), res.Error
+}
@@ -1,7 +1,7 @@
 import React, { useEffect, useState } from 'react';
 import { motion } from 'framer-motion';
 import { useInView } from 'react-intersection-observer';
-import { Link, useNavigate } from 'react-router-dom';
+import { Link, useNavigate, useSearchParams } from 'react-router-dom';
 import "react-responsive-carousel/lib/styles/carousel.min.css";
 import api from '../api';
 import Aos from 'aos';
@@ -99,6 +99,7 @@ function Home() {
   const [trackingId, setTrackingId] = useState('');
   const [totalReports, setTotalReports] = useState(0);
   const navigate = useNavigate();
+  const [searchParams] = useSearchParams();
 
   // gabungan video + foto
   const heroSlides = [
@@ -122,12 +123,12 @@ function Home() {
     return () => clearInterval(interval);
   }, [currentIndex, heroSlides.length]);
 
-  const handleTrack = async () => {
-    if (!trackingId) return;
+  const trackReport = async (id) => {
+    if (!id) return;
 
     try {
       const token = sessionStorage.getItem('token');
-      const res = await api.get(`/reports/search?tracking_id=${trackingId}`, {
+      const res = await api.get(`/reports/search?tracking_id=${encodeURIComponent(id)}`, {
         headers: { Authorization: `Bearer ${token}` },
       });
       const report = res.data.data;
@@ -142,6 +143,18 @@ function Home() {
     }
   };
 
+  const handleTrack = () => trackReport(trackingId);
+
+  // Tautan dari QR code bukti pengaduan: /?tracking_id=...
+  useEffect(() => {
+    const fromQr = searchParams.get('tracking_id');
+    if (fromQr) {
+      setTrackingId(fromQr);
+      trackReport(fromQr);
+    }
+    // eslint-disable-next-line react-hooks/exhaustive-deps
+  }, [searchParams]);
+
   useEffect(() => {
     const fetchTotalReports = async () => {
       try {
@@ -346,6 +346,8 @@ function ListPengaduan() {
       {(() => {
         const photos = item.bukti_fotos || [];
         const firstFile = photos.length > 0 ? photos[0].photo_url : null;
+        // thumbnail dibuat di latar; selama belum ada pakai foto asli
+        const preview = photos.length > 0 ? (photos[0].thumbnail_url || firstFile) : null;
 
         if (!firstFile) {
           return (
@@ -365,7 +367,7 @@ function ListPengaduan() {
         ) : (
           <div className="w-full aspect-[3/2] overflow-hidden">
             <img
-              src={`http://localhost:8080/${firstFile}`}
+              src={`http://localhost:8080/${preview}`}
               alt="Foto laporan"
               className="w-full h-full object-cover object-center"
             />
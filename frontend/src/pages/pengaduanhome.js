@@ -85,6 +85,8 @@ function PengaduanHome() {
                {(() => {
   const files = item.bukti_fotos || [];
   const firstFile = files.length > 0 ? files[0].photo_url : null;
+  // thumbnail dibuat di latar; selama belum ada pakai foto asli
+  const preview = files.length > 0 ? (files[0].thumbnail_url || firstFile) : null;
 
   if (!firstFile) {
     return (
@@ -104,7 +106,7 @@ function PengaduanHome() {
   ) : (
     <div className="w-full aspect-[3/2] overflow-hidden">
       <img
-        src={`http://localhost:8080/${firstFile}`}
+        src={`http://localhost:8080/${preview}`}
         alt="Lampiran laporan"
         className="w-full h-full object-cover object-center"
       />